@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+// runImport — подкоманда импорта:
+//
+//	service import [-format csv|ndjson] [-dry-run] [-chunk-size N] FILE
+//
+// FILE "-" читает stdin. В отличие от HTTP-эндпоинта вход читается потоково и
+// не ограничен SERVER_BODY_LIMIT. Отчёт печатается в stdout в JSON, логи — в
+// stderr; код выхода ненулевой, если хотя бы одна строка не импортирована.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "input format: csv or ndjson (default: by file extension, otherwise csv)")
+	dryRun := fs.Bool("dry-run", false, "validate rows without writing")
+	chunkSize := fs.Int("chunk-size", service.DefaultImportChunkSize, "rows per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: service import [-format csv|ndjson] [-dry-run] [-chunk-size N] FILE (use - for stdin)")
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = importFormatFromPath(path)
+	}
+
+	input, err := openImportInput(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	db, err := initStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	svc := service.NewService(db, logger)
+	report, err := svc.ImportExamples(ctx, input, models.ImportOptions{
+		Format:    *format,
+		DryRun:    *dryRun,
+		ChunkSize: *chunkSize,
+	})
+	if report != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+	}
+
+	return nil
+}
+
+func importFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return service.ImportFormatNDJSON
+	default:
+		return service.ImportFormatCSV
+	}
+}
+
+func openImportInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open input: %w", err)
+	}
+	return f, nil
+}
@@ -7,14 +7,24 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"runtime"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"go-service-template/internal/config"
+	"go-service-template/internal/events"
+	"go-service-template/internal/health"
+	"go-service-template/internal/jobs"
+	"go-service-template/internal/lifecycle"
+	"go-service-template/internal/outbox"
+	"go-service-template/internal/scheduler"
 	"go-service-template/internal/server"
 	"go-service-template/internal/service"
+	"go-service-template/internal/storage/cache"
 	"go-service-template/internal/storage/postgres"
+	"go-service-template/internal/webhook"
 )
 
 // @title Service API
@@ -46,6 +56,14 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "import failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "service failed to start:", err)
 		os.Exit(1)
@@ -61,11 +79,18 @@ func run() error {
 	return app.Run()
 }
 
+// App связывает конфигурацию, health-реестр и компоненты приложения. Запуском
+// и остановкой компонентов управляет lifecycle.Manager: новая подсистема
+// регистрирует свои хуки в registerComponents, не трогая Run/Shutdown.
 type App struct {
-	cfg    *config.Config
-	logger *slog.Logger
-	db     *postgres.PostgresStorage
-	server *server.Server
+	cfg       *config.Config
+	logger    *slog.Logger
+	server    *server.Server
+	health    *health.Registry
+	lifecycle *lifecycle.Manager
+	// started закрывается, когда warmUp дождался критичных проверок, в том
+	// числе версии схемы: до этого фоновые воркеры не обращаются к таблицам.
+	started chan struct{}
 }
 
 func NewApp() (*App, error) {
@@ -74,22 +99,427 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
-	logger := setupLogger(cfg.App.DebugMode)
+	logger, logLevel := setupLogger(cfg.App.DebugMode)
+	setupProfiling(cfg.Admin)
 
 	db, err := initStorage(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("connect database: %w", err)
 	}
 
-	services := service.NewServices(db, logger)
-	srv := server.New(services, logger, cfg)
+	registry := setupHealth(db)
+
+	var storage service.Storage = db
+	var cached *cache.Storage
+	if cfg.Cache.Enabled {
+		cached = cache.New(db, cache.NewLRU(cfg.Cache.MaxEntries, cfg.Cache.TTL), logger)
+		storage = cached
+	}
+
+	services := service.NewServices(storage, logger)
+	services.Webhooks = service.NewWebhookService(db.WebhookStore(), cfg.Webhook.AllowPrivateNetworks, logger)
+	services.Audit = service.NewAuditService(db, logger)
+	// Операции выполняются фоновыми задачами: запуск ставит задачу, её
+	// обработчик (registerJobHandlers) вызывает RunOperation.
+	jobsClient := jobs.NewClient(db.JobStore(), cfg.Jobs.MaxAttempts)
+	services.Operations = service.NewOperationService(db.OperationStore(), services.Example,
+		func(ctx context.Context, id int64) error {
+			_, _, err := jobsClient.Enqueue(ctx, service.OperationJobArgs{OperationID: id})
+			return err
+		}, logger)
+	feed := events.NewFeed(db, cfg.Events.BufferSize, cfg.Events.PollInterval, logger)
+	sched := scheduler.New(db.SchedulerStore(), logger)
+	if err := registerScheduledTasks(sched, db, cfg, logger); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("register scheduled tasks: %w", err)
+	}
+	srv := server.New(services, logger, cfg,
+		server.WithLogLevel(logLevel),
+		server.WithHealth(registry),
+		server.WithIdempotency(db.IdempotencyStore()),
+		server.WithEvents(feed),
+		server.WithScheduler(sched),
+	)
+
+	app := &App{
+		cfg:       cfg,
+		logger:    logger,
+		server:    srv,
+		health:    registry,
+		lifecycle: lifecycle.New(logger),
+		started:   make(chan struct{}),
+	}
+	app.registerComponents(db, cached, feed, services, sched)
+
+	return app, nil
+}
+
+// registerComponents описывает компоненты и их зависимости. Менеджер
+// стартует их в порядке зависимостей и останавливает в обратном. cached —
+// кеш записей, nil, если он выключен. Фоновая работа компонентов ждёт
+// awaitStartup: при старте раньше миграций она не должна обращаться к
+// таблицам и столбцам, которых ещё нет.
+func (a *App) registerComponents(db *postgres.PostgresStorage, cached *cache.Storage, feed *events.Feed, services *service.Services, sched *scheduler.Scheduler) {
+	a.lifecycle.Register(lifecycle.Component{
+		Name:    "storage",
+		Timeout: 5 * time.Second,
+		Start:   db.Ping,
+		Stop: func(context.Context) error {
+			return db.Close()
+		},
+	})
+
+	// Периодические задачи обслуживания, в том числе удаление устаревших
+	// данных. Планировщик работает на каждой реплике, каждый запуск
+	// выполняет одна из них.
+	var (
+		stopScheduler context.CancelFunc
+		schedulerDone chan struct{}
+	)
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "scheduler",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, stopScheduler = context.WithCancel(context.Background())
+			schedulerDone = make(chan struct{})
+			a.lifecycle.Go("scheduler", func() error {
+				defer close(schedulerDone)
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				return sched.Run(ctx)
+			})
+			return nil
+		},
+		// Выполняемые задачи прерываются; итог запуска успевает записаться.
+		Stop: func(ctx context.Context) error {
+			stopScheduler()
+			select {
+			case <-schedulerDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	if cached != nil {
+		var (
+			stopListener context.CancelFunc
+			listenerDone chan struct{}
+		)
+		a.lifecycle.Register(lifecycle.Component{
+			Name:      "cache-invalidation",
+			DependsOn: []string{"storage"},
+			Start: func(context.Context) error {
+				var ctx context.Context
+				ctx, stopListener = context.WithCancel(context.Background())
+				listenerDone = make(chan struct{})
+				a.lifecycle.Go("cache invalidation listener", func() error {
+					defer close(listenerDone)
+					if !a.awaitStartup(ctx) {
+						return nil
+					}
+					return db.ListenChanges(ctx, cached, a.logger)
+				})
+				return nil
+			},
+			// Слушатель держит соединение из пула: ждём его до закрытия storage.
+			Stop: func(ctx context.Context) error {
+				stopListener()
+				select {
+				case <-listenerDone:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		})
+	}
+
+	// Журнал изменений: раздача подписчикам SSE и уведомления о новых
+	// событиях.
+	var (
+		stopEvents context.CancelFunc
+		eventsDone sync.WaitGroup
+	)
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "events",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, stopEvents = context.WithCancel(context.Background())
+			eventsDone.Add(2)
+			a.lifecycle.Go("events feed", func() error {
+				defer eventsDone.Done()
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				return feed.Run(ctx)
+			})
+			a.lifecycle.Go("events listener", func() error {
+				defer eventsDone.Done()
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				return db.ListenEvents(ctx, feed.Notify, a.logger)
+			})
+			return nil
+		},
+		// Лента и слушатель читают из пула: ждём их до закрытия storage.
+		Stop: func(ctx context.Context) error {
+			stopEvents()
+			done := make(chan struct{})
+			go func() {
+				eventsDone.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	// Relay outbox: публикация сообщений об изменениях во внешние системы.
+	var (
+		stopRelay context.CancelFunc
+		relayDone chan struct{}
+		publisher *outbox.WriterPublisher
+	)
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "outbox-relay",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			var err error
+			if publisher, err = newOutboxPublisher(a.cfg.Outbox); err != nil {
+				return err
+			}
+			// Каждое сообщение уходит и во внешний публикатор, и в очередь
+			// доставок webhooks.
+			fanout := outbox.Fanout(publisher, webhook.NewPublisher(db.WebhookStore()))
+			relay := outbox.NewRelay(db.OutboxStore(), fanout, a.cfg.Outbox, a.logger)
+			var ctx context.Context
+			ctx, stopRelay = context.WithCancel(context.Background())
+			relayDone = make(chan struct{})
+			a.lifecycle.Go("outbox relay", func() error {
+				defer close(relayDone)
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				return relay.Run(ctx)
+			})
+			return nil
+		},
+		// Публикатор закрывается после того, как relay дописал текущую пачку.
+		Stop: func(ctx context.Context) error {
+			stopRelay()
+			select {
+			case <-relayDone:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return publisher.Close()
+		},
+	})
+
+	// Доставка webhooks подписчикам.
+	var (
+		stopWebhooks context.CancelFunc
+		webhooksDone chan struct{}
+	)
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "webhooks",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			worker := webhook.NewWorker(db.WebhookStore(), a.cfg.Webhook, a.logger)
+			var ctx context.Context
+			ctx, stopWebhooks = context.WithCancel(context.Background())
+			webhooksDone = make(chan struct{})
+			a.lifecycle.Go("webhook worker", func() error {
+				defer close(webhooksDone)
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				return worker.Run(ctx)
+			})
+			return nil
+		},
+		// Ждём текущую пачку, чтобы её попытки успели попасть в журнал.
+		Stop: func(ctx context.Context) error {
+			stopWebhooks()
+			select {
+			case <-webhooksDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	// Фоновые задачи.
+	var (
+		stopJobs      context.CancelFunc
+		runner        *jobs.Runner
+		runnerStarted chan struct{}
+		jobsDone      chan struct{}
+	)
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "jobs",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			runner = jobs.NewRunner(db.JobStore(), a.cfg.Jobs, a.logger)
+			registerJobHandlers(runner, services)
+			var ctx context.Context
+			ctx, stopJobs = context.WithCancel(context.Background())
+			runnerStarted, jobsDone = make(chan struct{}), make(chan struct{})
+			a.lifecycle.Go("job runner", func() error {
+				defer close(jobsDone)
+				if !a.awaitStartup(ctx) {
+					return nil
+				}
+				close(runnerStarted)
+				return runner.Run(ctx)
+			})
+			return nil
+		},
+		// Выполняемые задачи доделываются, пока позволяет бюджет остановки;
+		// затем прерываются и возвращаются в очередь. Если runner так и не
+		// запустился (остановка до конца warmUp), ждать нечего.
+		Stop: func(ctx context.Context) error {
+			stopJobs()
+			select {
+			case <-runnerStarted:
+				return runner.Stop(ctx)
+			case <-jobsDone:
+				return nil
+			}
+		},
+	})
+
+	a.lifecycle.Register(lifecycle.Component{
+		Name:      "http",
+		DependsOn: []string{"storage", "events"},
+		Start: func(context.Context) error {
+			portStr := strconv.Itoa(a.cfg.Server.Port)
+			a.lifecycle.Go("http server", func() error {
+				return a.server.Start(portStr)
+			})
+			return nil
+		},
+		Stop: a.server.Shutdown,
+	})
+}
 
-	return &App{
-		cfg:    cfg,
-		logger: logger,
-		db:     db,
-		server: srv,
-	}, nil
+// registerJobHandlers регистрирует обработчики фоновых задач. Новый тип
+// задачи — тип аргументов с методом Kind и обработчик:
+//
+//	jobs.Register(runner, func(ctx context.Context, job jobs.Job, args ReindexArgs) error { ... })
+func registerJobHandlers(runner *jobs.Runner, services *service.Services) {
+	jobs.Register(runner, func(ctx context.Context, _ jobs.Job, args service.OperationJobArgs) error {
+		return services.Operations.RunOperation(ctx, args.OperationID)
+	})
+}
+
+// registerScheduledTasks регистрирует периодические задачи обслуживания.
+// Новая задача — имя, cron-расписание из конфигурации и функция:
+//
+//	{Name: "reindex", Schedule: cfg.Reindex.Schedule, Run: reindex}
+func registerScheduledTasks(sched *scheduler.Scheduler, db *postgres.PostgresStorage, cfg *config.Config, logger *slog.Logger) error {
+	tasks := []scheduler.Task{
+		{
+			Name:     "idempotency-cleanup",
+			Schedule: cfg.Idempotency.CleanupSchedule,
+			Run: func(ctx context.Context) error {
+				deleted, err := db.IdempotencyStore().DeleteExpired(ctx)
+				if err != nil {
+					return err
+				}
+				logger.Debug("Expired idempotency keys deleted", slog.Int64("count", deleted))
+				return nil
+			},
+		},
+		{
+			Name:     "events-cleanup",
+			Schedule: cfg.Events.CleanupSchedule,
+			Run: func(ctx context.Context) error {
+				deleted, err := db.DeleteEventsBefore(ctx, time.Now().Add(-cfg.Events.Retention))
+				if err != nil {
+					return err
+				}
+				logger.Debug("Old example events deleted", slog.Int64("count", deleted))
+				return nil
+			},
+		},
+		{
+			Name:     "webhook-cleanup",
+			Schedule: cfg.Webhook.CleanupSchedule,
+			Run: func(ctx context.Context) error {
+				deleted, err := db.WebhookStore().DeleteFinishedBefore(ctx, time.Now().Add(-cfg.Webhook.Retention))
+				if err != nil {
+					return err
+				}
+				logger.Debug("Old webhook deliveries deleted", slog.Int64("count", deleted))
+				return nil
+			},
+		},
+		{
+			Name:     "jobs-cleanup",
+			Schedule: cfg.Jobs.CleanupSchedule,
+			Run: func(ctx context.Context) error {
+				deleted, err := db.JobStore().DeleteFinishedBefore(ctx, time.Now().Add(-cfg.Jobs.Retention))
+				if err != nil {
+					return err
+				}
+				logger.Debug("Old jobs deleted", slog.Int64("count", deleted))
+				return nil
+			},
+		},
+		{
+			Name:     "operations-cleanup",
+			Schedule: cfg.Operations.CleanupSchedule,
+			Run: func(ctx context.Context) error {
+				deleted, err := db.OperationStore().DeleteFinishedBefore(ctx, time.Now().Add(-cfg.Operations.Retention))
+				if err != nil {
+					return err
+				}
+				logger.Debug("Finished operations deleted", slog.Int64("count", deleted))
+				return nil
+			},
+		},
+		{
+			Name:     "stats-refresh",
+			Schedule: cfg.Stats.RefreshSchedule,
+			Run:      db.RefreshExampleStats,
+		},
+	}
+	for _, task := range tasks {
+		if err := sched.Add(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newOutboxPublisher создаёт публикатор по OUTBOX_PUBLISHER. Для брокера
+// сообщений реализуйте outbox.Publisher и добавьте его сюда.
+func newOutboxPublisher(cfg config.OutboxConfig) (*outbox.WriterPublisher, error) {
+	if cfg.Publisher == "file" {
+		return outbox.NewFilePublisher(cfg.FilePath)
+	}
+	return outbox.NewStdoutPublisher(), nil
+}
+
+// setupHealth регистрирует проверки зависимостей. Новые подсистемы (воркеры,
+// кеши) добавляют свои проверки сюда же.
+func setupHealth(db *postgres.PostgresStorage) *health.Registry {
+	registry := health.NewRegistry()
+	registry.Register(health.Check{Name: "database", Critical: true, Timeout: 2 * time.Second, Func: db.Ping})
+	registry.Register(health.Check{Name: "migrations", Critical: true, Timeout: 2 * time.Second, Func: db.CheckSchemaVersion})
+	return registry
 }
 
 func loadConfig() (*config.Config, error) {
@@ -123,66 +553,124 @@ func (a *App) Run() error {
 		slog.String("build_date", buildDate),
 	)
 
-	serverErr := make(chan error, 1)
+	if err := a.lifecycle.Start(ctx); err != nil {
+		return fmt.Errorf("start components: %w", err)
+	}
 
-	go func() {
-		if err := a.Start(); err != nil {
-			serverErr <- fmt.Errorf("server run: %w", err)
-			cancel()
-		}
-	}()
+	go a.warmUp(ctx)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	defer signal.Stop(quit)
 
+	var runErr error
 	select {
 	case <-quit:
 		a.logger.Info("Shutdown signal received")
-	case err := <-serverErr:
-		return err
-	case <-ctx.Done():
-		a.logger.Info("Context cancelled")
+		a.drain(quit)
+	case runErr = <-a.lifecycle.Errors():
+		a.logger.Error("Component failed, shutting down", slog.String("error", runErr.Error()))
 	}
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), a.cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
-	return a.Shutdown(shutdownCtx)
+	return errors.Join(runErr, a.Shutdown(shutdownCtx))
 }
 
-func (a *App) Start() error {
-	portStr := strconv.Itoa(a.cfg.Server.Port)
-	return a.server.Start(portStr)
+// warmUp ждёт, пока все критичные проверки (БД, версия миграций) пройдут, и
+// только после этого открывает /startupz. Миграции применяет отдельный шаг
+// деплоя (контейнер migrate), поэтому сервис может стартовать раньше них.
+func (a *App) warmUp(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		report := a.health.Check(ctx)
+		if report.Status == health.StatusUp {
+			a.health.MarkStarted()
+			close(a.started)
+			a.logger.Info("Startup completed")
+			return
+		}
+		if failed, ok := health.FirstFailure(report); ok {
+			a.logger.Debug("Waiting for dependency", slog.String("component", failed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-func (a *App) Shutdown(ctx context.Context) error {
-	a.logger.Info("Server is shutting down...")
+// awaitStartup ждёт завершения warmUp; false — ctx отменён раньше.
+func (a *App) awaitStartup(ctx context.Context) bool {
+	select {
+	case <-a.started:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
 
-	var shutdownErrs []error
+// drain — первая фаза остановки: /readyz сразу начинает отвечать 503, а
+// листенеры продолжают обслуживать трафик ещё SERVER_PRE_STOP_DELAY, пока
+// балансировщик не уберёт под из ротации. Повторный сигнал прерывает ожидание.
+func (a *App) drain(quit <-chan os.Signal) {
+	a.server.BeginDrain()
 
-	if err := a.server.Shutdown(ctx); err != nil {
-		a.logger.Error("Server forced to shutdown", slog.String("error", err.Error()))
-		shutdownErrs = append(shutdownErrs, fmt.Errorf("shutdown server: %w", err))
-	} else {
-		a.logger.Info("Server exited gracefully")
+	delay := a.cfg.Server.PreStopDelay
+	a.logger.Info("Shutdown phase: draining", slog.Duration("pre_stop_delay", delay))
+	if delay <= 0 {
+		return
 	}
 
-	if a.db != nil {
-		if err := a.db.Close(); err != nil {
-			a.logger.Error("Failed to close database", slog.String("error", err.Error()))
-			shutdownErrs = append(shutdownErrs, fmt.Errorf("close database: %w", err))
-		}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-quit:
+		a.logger.Warn("Second shutdown signal received, skipping pre-stop delay")
+	}
+}
+
+// Shutdown останавливает компоненты в порядке, обратном запуску: сначала
+// HTTP-листенеры, затем хранилище.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.logger.Info("Shutdown phase: stopping components",
+		slog.Duration("timeout", a.cfg.Server.ShutdownTimeout),
+	)
+
+	if err := a.lifecycle.Stop(ctx); err != nil {
+		return err
 	}
 
-	return errors.Join(shutdownErrs...)
+	a.logger.Info("Service stopped gracefully")
+	return nil
+}
+
+// setupProfiling включает сбор block- и mutex-профилей, если pprof включён.
+// Оба профиля имеют накладные расходы, поэтому по умолчанию выключены.
+func setupProfiling(cfg config.AdminConfig) {
+	if !cfg.PprofEnabled {
+		return
+	}
+	runtime.SetBlockProfileRate(cfg.BlockProfileRate)
+	runtime.SetMutexProfileFraction(cfg.MutexProfileFraction)
 }
 
 // setupLogger возвращает slog-логгер, пишущий только в stdout. В контейнерах
 // сбором stdout занимается платформа (Docker/k8s) — приложение не должно владеть лог-файлами.
-func setupLogger(debugMode bool) *slog.Logger {
+// Уровень хранится в LevelVar, чтобы его можно было менять через admin-поверхность.
+func setupLogger(debugMode bool) (*slog.Logger, *slog.LevelVar) {
+	level := new(slog.LevelVar)
 	if debugMode {
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		level.Set(slog.LevelDebug)
+		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})), level
 	}
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	level.Set(slog.LevelInfo)
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})), level
 }
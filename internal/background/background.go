@@ -0,0 +1,52 @@
+// Package background — общие помощники фоновых воркеров (outbox, webhooks,
+// задачи, планировщик): задержка повтора, запись итога после отмены и
+// обрезка текста ошибки.
+package background
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const (
+	// StoreTimeout ограничивает запись итога работы (попытки, задачи,
+	// запуска). Она выполняется и после отмены ctx воркера, иначе сделанное
+	// повторилось бы после истечения аренды.
+	StoreTimeout = 5 * time.Second
+	// MaxErrorLength ограничивает текст ошибки, сохраняемый в БД.
+	MaxErrorLength = 500
+)
+
+// StoreContext возвращает контекст записи итога: он не отменяется вместе с
+// ctx, но ограничен StoreTimeout.
+func StoreContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), StoreTimeout)
+}
+
+// Backoff возвращает задержку перед попыткой attempt+1: base, удваиваемая с
+// каждой попыткой, но не больше maxDelay.
+func Backoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// Jitter делает случайной вторую половину задержки, чтобы повторы многих
+// воркеров не шли залпом: результат лежит в [delay/2, delay].
+func Jitter(delay time.Duration) time.Duration {
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// ErrorText — текст err, обрезанный до MaxErrorLength байт без разрыва
+// UTF-8.
+func ErrorText(err error) string {
+	s := err.Error()
+	if len(s) <= MaxErrorLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:MaxErrorLength], "")
+}
@@ -0,0 +1,45 @@
+package background
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{10, time.Minute},
+		{1000, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(time.Second, time.Minute, tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		if got := Jitter(time.Minute); got < 30*time.Second || got > time.Minute {
+			t.Fatalf("expected delay in [30s, 1m], got %s", got)
+		}
+	}
+}
+
+func TestErrorText(t *testing.T) {
+	if got := ErrorText(errors.New("short")); got != "short" {
+		t.Errorf("unexpected text %q", got)
+	}
+	got := ErrorText(errors.New(strings.Repeat("я", MaxErrorLength)))
+	if len(got) > MaxErrorLength || !utf8.ValidString(got) {
+		t.Errorf("expected valid UTF-8 within %d bytes, got %d bytes", MaxErrorLength, len(got))
+	}
+}
@@ -2,15 +2,33 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
 	"time"
+
+	"go-service-template/internal/scheduler"
+)
+
+const (
+	redactedValue       = "***"
+	defaultCacheControl = "private, no-cache"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	App      AppConfig
+	Database    DatabaseConfig
+	Server      ServerConfig
+	Admin       AdminConfig
+	Idempotency IdempotencyConfig
+	Cache       CacheConfig
+	Events      EventsConfig
+	WebSocket   WebSocketConfig
+	Outbox      OutboxConfig
+	Webhook     WebhookConfig
+	Jobs        JobsConfig
+	Operations  OperationsConfig
+	Stats       StatsConfig
+	App         AppConfig
 }
 
 type DatabaseConfig struct {
@@ -24,6 +42,16 @@ type DatabaseConfig struct {
 	MinConns        int
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+	// TxIsolation — уровень изоляции для Storage.WithinTx:
+	// read_committed | repeatable_read | serializable.
+	TxIsolation string
+	// TxMaxRetries — сколько раз повторять транзакцию при ошибке сериализации
+	// или дедлоке (SQLSTATE 40001/40P01).
+	TxMaxRetries int
+	// SearchLanguage — конфигурация полнотекстового поиска PostgreSQL
+	// (english, russian, simple...). Должна совпадать с той, которой
+	// построена колонка examples.search_vector.
+	SearchLanguage string
 }
 
 type ServerConfig struct {
@@ -34,6 +62,180 @@ type ServerConfig struct {
 	BodyLimit        int    // максимальный размер тела запроса в байтах
 	RateLimit        int    // лимит запросов в минуту на один IP (0 отключает лимитер)
 	CORSAllowOrigins string // список разрешённых CORS-источников через запятую
+	// PreStopDelay — пауза между переводом /readyz в 503 и закрытием листенеров,
+	// чтобы балансировщик (k8s Endpoints) успел убрать под из ротации.
+	PreStopDelay time.Duration
+	// ShutdownTimeout — бюджет на завершение запросов в обработке и закрытие ресурсов.
+	ShutdownTimeout time.Duration
+	// ExampleCacheControl и ListCacheControl — значение Cache-Control для
+	// GET /examples/:id и GET /examples. По умолчанию "private, no-cache":
+	// клиент хранит ответ, но каждый раз перепроверяет его по ETag.
+	ExampleCacheControl string
+	ListCacheControl    string
+}
+
+// AdminConfig описывает отдельный служебный листенер: пробы, метрики, конфиг
+// и управление уровнем логов. Port == 0 отключает его — тогда пробы остаются
+// на публичном порту.
+type AdminConfig struct {
+	Host string
+	Port int
+	// Token — bearer-токен для /debug/* на admin-листенере. Пустой токен
+	// оставляет /debug/* открытыми только на loopback-адресе, на остальных
+	// они отвечают 403; пробы и /metrics токен не требуют. pprof без токена
+	// не включается.
+	Token string
+	// PprofEnabled включает net/http/pprof, дамп горутин и захват trace.
+	PprofEnabled bool
+	// ProfileMaxDuration ограничивает параметр seconds для CPU-профиля и trace.
+	ProfileMaxDuration   time.Duration
+	BlockProfileRate     int // см. runtime.SetBlockProfileRate, 0 — не собирать
+	MutexProfileFraction int // см. runtime.SetMutexProfileFraction, 0 — не собирать
+}
+
+// IdempotencyConfig управляет поддержкой заголовка Idempotency-Key.
+type IdempotencyConfig struct {
+	// TTL — сколько хранится ответ и действует ключ.
+	TTL time.Duration
+	// WaitTimeout — сколько повторный запрос ждёт завершения первого, прежде
+	// чем получить 409. 0 — отвечать 409 сразу.
+	WaitTimeout time.Duration
+	// LockTimeout — через сколько незавершённый запрос (процесс упал) считается
+	// брошенным и ключ можно перезахватить. Должен превышать время самого
+	// долгого запроса.
+	LockTimeout time.Duration
+	// CleanupSchedule — cron-расписание удаления истёкших ключей.
+	CleanupSchedule string
+}
+
+// CacheConfig управляет read-through кешем записей в памяти процесса.
+type CacheConfig struct {
+	Enabled bool
+	// MaxEntries — сколько записей держит LRU; при переполнении вытесняются
+	// давно не читанные.
+	MaxEntries int
+	// TTL — время жизни записи в кеше. Ограничивает устаревание, если
+	// изменение пришло мимо этого процесса.
+	TTL time.Duration
+}
+
+// EventsConfig управляет журналом изменений и потоком /examples/stream.
+type EventsConfig struct {
+	// HeartbeatInterval — период комментариев-пингов в SSE-потоке, чтобы
+	// прокси и балансировщики не закрывали простаивающее соединение.
+	HeartbeatInterval time.Duration
+	// BufferSize — сколько неотправленных событий может накопиться у одного
+	// подписчика; при переполнении он отключается и дочитывает журнал после
+	// переподключения.
+	BufferSize int
+	// PollInterval — период опроса журнала на случай потерянного NOTIFY.
+	PollInterval time.Duration
+	// Retention — сколько хранятся события; дальше продолжить поток по
+	// Last-Event-ID нельзя.
+	Retention time.Duration
+	// CleanupSchedule — cron-расписание удаления старых событий.
+	CleanupSchedule string
+}
+
+// WebSocketConfig ограничивает подписки одного соединения /api/v1/ws.
+type WebSocketConfig struct {
+	// MaxSubscriptions — сколько подписок может держать одно соединение.
+	MaxSubscriptions int
+	// MaxExampleIDs — сколько ID записей суммарно во всех подписках соединения.
+	MaxExampleIDs int
+	// PingInterval — период ping от сервера; соединение без ответа дольше
+	// двух интервалов закрывается.
+	PingInterval time.Duration
+}
+
+// OutboxConfig управляет relay сообщений transactional outbox.
+type OutboxConfig struct {
+	// Publisher — куда публикуются сообщения: stdout или file.
+	Publisher string
+	// FilePath — файл для Publisher = file.
+	FilePath string
+	// PollInterval — период опроса очереди, когда она пуста.
+	PollInterval time.Duration
+	// BatchSize — сколько сообщений захватывается за раз.
+	BatchSize int
+	// MaxAttempts — после стольких неудачных попыток сообщение переводится в
+	// dead letter.
+	MaxAttempts int
+	// Lease — на сколько захватывается пачка. Если relay упал, не дописав
+	// результат, сообщения снова станут доступны после Lease. Должен
+	// превышать PublishTimeout.
+	Lease time.Duration
+	// PublishTimeout ограничивает одну попытку публикации.
+	PublishTimeout time.Duration
+	// RetryBaseDelay и RetryMaxDelay — задержка перед повтором: удваивается
+	// с каждой попыткой от базовой до максимальной.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// WebhookConfig управляет доставкой webhooks.
+type WebhookConfig struct {
+	// Timeout ограничивает один HTTP-запрос к получателю.
+	Timeout time.Duration
+	// PollInterval — период опроса очереди доставок, когда она пуста.
+	PollInterval time.Duration
+	// BatchSize — сколько доставок захватывается и отправляется параллельно.
+	BatchSize int
+	// Lease — на сколько захватывается пачка; должен превышать Timeout.
+	Lease time.Duration
+	// MaxAttempts — после стольких попыток доставка помечается failed.
+	MaxAttempts int
+	// RetryBaseDelay и RetryMaxDelay — задержка повтора: удваивается с каждой
+	// попыткой, половина задержки случайна (jitter).
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// DisableAfter — после стольких неудачных попыток подряд (по всем
+	// доставкам подписки) подписка выключается.
+	DisableAfter int
+	// Retention — сколько хранятся завершённые доставки и журнал попыток.
+	Retention time.Duration
+	// CleanupSchedule — cron-расписание удаления старых доставок.
+	CleanupSchedule string
+	// AllowPrivateNetworks разрешает адреса подписок во внутренних сетях
+	// (loopback, частные, link-local). По умолчанию они запрещены: иначе
+	// webhook позволяет слать запросы от имени сервиса внутрь периметра.
+	AllowPrivateNetworks bool
+}
+
+// JobsConfig управляет фоновыми задачами.
+type JobsConfig struct {
+	// Workers — сколько задач выполняется одновременно.
+	Workers int
+	// PollInterval — период опроса очереди, когда она пуста.
+	PollInterval time.Duration
+	// VisibilityTimeout — на сколько захватывается задача. Пока обработчик
+	// работает, захват продлевается; если воркер упал, задача вернётся в
+	// очередь по его истечении.
+	VisibilityTimeout time.Duration
+	// MaxAttempts — попыток по умолчанию, если при постановке не задано иное.
+	MaxAttempts int
+	// RetryBaseDelay и RetryMaxDelay — задержка повтора: удваивается с каждой
+	// попыткой, половина задержки случайна (jitter).
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Retention — сколько хранятся завершённые задачи.
+	Retention time.Duration
+	// CleanupSchedule — cron-расписание удаления старых задач.
+	CleanupSchedule string
+}
+
+// OperationsConfig управляет асинхронными операциями.
+type OperationsConfig struct {
+	// Retention — сколько хранятся завершённые операции.
+	Retention time.Duration
+	// CleanupSchedule — cron-расписание удаления старых операций.
+	CleanupSchedule string
+}
+
+// StatsConfig управляет материализованной статистикой example_stats_daily.
+type StatsConfig struct {
+	// RefreshSchedule — cron-расписание пересчёта статистики.
+	RefreshSchedule string
 }
 
 type AppConfig struct {
@@ -43,7 +245,23 @@ type AppConfig struct {
 	EnableSwagger bool
 }
 
+// removedEnv — переменные, которые больше не читаются. Заданная переменная
+// из списка — ошибка запуска: иначе настройка молча перестала бы действовать.
+var removedEnv = []struct{ name, hint string }{
+	{"IDEMPOTENCY_CLEANUP_INTERVAL", "use IDEMPOTENCY_CLEANUP_SCHEDULE (cron expression)"},
+	{"EVENTS_CLEANUP_INTERVAL", "use EVENTS_CLEANUP_SCHEDULE (cron expression)"},
+	{"WEBHOOK_CLEANUP_INTERVAL", "use WEBHOOK_CLEANUP_SCHEDULE (cron expression)"},
+	{"JOBS_CLEANUP_INTERVAL", "use JOBS_CLEANUP_SCHEDULE (cron expression)"},
+	{"SCHEDULER_ENABLED", "scheduled tasks run on every replica, each tick on exactly one of them"},
+}
+
 func Load() (*Config, error) {
+	for _, env := range removedEnv {
+		if _, ok := os.LookupEnv(env.name); ok {
+			return nil, fmt.Errorf("config: %s is no longer supported: %s", env.name, env.hint)
+		}
+	}
+
 	config := &Config{}
 	var err error
 
@@ -74,6 +292,12 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	config.Database.TxIsolation = getEnv("DB_TX_ISOLATION", "read_committed")
+	config.Database.TxMaxRetries, err = getEnvInt("DB_TX_MAX_RETRIES", 3)
+	if err != nil {
+		return nil, err
+	}
+	config.Database.SearchLanguage = getEnv("DB_SEARCH_LANGUAGE", "english")
 
 	config.Server.Host = getEnv("SERVER_HOST", "localhost")
 	config.Server.Port, err = getEnvInt("SERVER_PORT", 8080)
@@ -97,6 +321,207 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	config.Server.CORSAllowOrigins = getEnv("CORS_ALLOW_ORIGINS", "*")
+	config.Server.PreStopDelay, err = getEnvDuration("SERVER_PRE_STOP_DELAY", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.Server.ShutdownTimeout, err = getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Server.ExampleCacheControl = getEnv("SERVER_CACHE_CONTROL_EXAMPLE", defaultCacheControl)
+	config.Server.ListCacheControl = getEnv("SERVER_CACHE_CONTROL_LIST", defaultCacheControl)
+
+	config.Admin.Host = getEnv("ADMIN_HOST", "127.0.0.1")
+	config.Admin.Port, err = getEnvInt("ADMIN_PORT", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.Admin.Token = getEnv("ADMIN_TOKEN", "")
+	config.Admin.PprofEnabled, err = getEnvBool("ADMIN_PPROF_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+	config.Admin.ProfileMaxDuration, err = getEnvDuration("ADMIN_PROFILE_MAX_DURATION", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.Admin.BlockProfileRate, err = getEnvInt("ADMIN_BLOCK_PROFILE_RATE", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.Admin.MutexProfileFraction, err = getEnvInt("ADMIN_MUTEX_PROFILE_FRACTION", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Idempotency.TTL, err = getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Idempotency.WaitTimeout, err = getEnvDuration("IDEMPOTENCY_WAIT_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Idempotency.LockTimeout, err = getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.Idempotency.CleanupSchedule = getEnv("IDEMPOTENCY_CLEANUP_SCHEDULE", "0 * * * *")
+
+	config.Cache.Enabled, err = getEnvBool("CACHE_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+	config.Cache.MaxEntries, err = getEnvInt("CACHE_MAX_ENTRIES", 10000)
+	if err != nil {
+		return nil, err
+	}
+	config.Cache.TTL, err = getEnvDuration("CACHE_TTL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Events.HeartbeatInterval, err = getEnvDuration("EVENTS_HEARTBEAT_INTERVAL", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Events.BufferSize, err = getEnvInt("EVENTS_BUFFER_SIZE", 256)
+	if err != nil {
+		return nil, err
+	}
+	config.Events.PollInterval, err = getEnvDuration("EVENTS_POLL_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Events.Retention, err = getEnvDuration("EVENTS_RETENTION", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Events.CleanupSchedule = getEnv("EVENTS_CLEANUP_SCHEDULE", "10 * * * *")
+
+	config.WebSocket.MaxSubscriptions, err = getEnvInt("WS_MAX_SUBSCRIPTIONS", 20)
+	if err != nil {
+		return nil, err
+	}
+	config.WebSocket.MaxExampleIDs, err = getEnvInt("WS_MAX_EXAMPLE_IDS", 1000)
+	if err != nil {
+		return nil, err
+	}
+	config.WebSocket.PingInterval, err = getEnvDuration("WS_PING_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Outbox.Publisher = getEnv("OUTBOX_PUBLISHER", "stdout")
+	config.Outbox.FilePath = getEnv("OUTBOX_FILE_PATH", "outbox.ndjson")
+	config.Outbox.PollInterval, err = getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.BatchSize, err = getEnvInt("OUTBOX_BATCH_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.MaxAttempts, err = getEnvInt("OUTBOX_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.Lease, err = getEnvDuration("OUTBOX_LEASE", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.PublishTimeout, err = getEnvDuration("OUTBOX_PUBLISH_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.RetryBaseDelay, err = getEnvDuration("OUTBOX_RETRY_BASE_DELAY", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Outbox.RetryMaxDelay, err = getEnvDuration("OUTBOX_RETRY_MAX_DELAY", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Webhook.Timeout, err = getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.PollInterval, err = getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.BatchSize, err = getEnvInt("WEBHOOK_BATCH_SIZE", 50)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.Lease, err = getEnvDuration("WEBHOOK_LEASE", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.MaxAttempts, err = getEnvInt("WEBHOOK_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.RetryBaseDelay, err = getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.RetryMaxDelay, err = getEnvDuration("WEBHOOK_RETRY_MAX_DELAY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.DisableAfter, err = getEnvInt("WEBHOOK_DISABLE_AFTER", 50)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.Retention, err = getEnvDuration("WEBHOOK_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook.CleanupSchedule = getEnv("WEBHOOK_CLEANUP_SCHEDULE", "20 * * * *")
+	config.Webhook.AllowPrivateNetworks, err = getEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.Workers, err = getEnvInt("JOBS_WORKERS", 4)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.PollInterval, err = getEnvDuration("JOBS_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.VisibilityTimeout, err = getEnvDuration("JOBS_VISIBILITY_TIMEOUT", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.MaxAttempts, err = getEnvInt("JOBS_MAX_ATTEMPTS", 25)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.RetryBaseDelay, err = getEnvDuration("JOBS_RETRY_BASE_DELAY", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.RetryMaxDelay, err = getEnvDuration("JOBS_RETRY_MAX_DELAY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.Retention, err = getEnvDuration("JOBS_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.CleanupSchedule = getEnv("JOBS_CLEANUP_SCHEDULE", "40 * * * *")
+
+	config.Operations.Retention, err = getEnvDuration("OPERATIONS_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Operations.CleanupSchedule = getEnv("OPERATIONS_CLEANUP_SCHEDULE", "30 3 * * *")
+
+	config.Stats.RefreshSchedule = getEnv("STATS_REFRESH_SCHEDULE", "*/15 * * * *")
 
 	config.App.DebugMode, err = getEnvBool("DEBUG_MODE", false)
 	if err != nil {
@@ -130,6 +555,168 @@ func (c *Config) validate() error {
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("config: SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port)
 	}
+	if c.Server.PreStopDelay < 0 {
+		return fmt.Errorf("config: SERVER_PRE_STOP_DELAY must be non-negative, got %s", c.Server.PreStopDelay)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("config: SERVER_SHUTDOWN_TIMEOUT must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+	if c.Admin.Port < 0 || c.Admin.Port > 65535 {
+		return fmt.Errorf("config: ADMIN_PORT must be between 0 and 65535, got %d", c.Admin.Port)
+	}
+	if c.Admin.Port != 0 && c.Admin.Port == c.Server.Port {
+		return fmt.Errorf("config: ADMIN_PORT must differ from SERVER_PORT, got %d", c.Admin.Port)
+	}
+	if c.Admin.PprofEnabled {
+		if !c.AdminEnabled() {
+			return fmt.Errorf("config: ADMIN_PPROF_ENABLED requires ADMIN_PORT")
+		}
+		if c.Admin.Token == "" {
+			return fmt.Errorf("config: ADMIN_PPROF_ENABLED requires ADMIN_TOKEN")
+		}
+		if c.Admin.ProfileMaxDuration <= 0 {
+			return fmt.Errorf("config: ADMIN_PROFILE_MAX_DURATION must be positive, got %s", c.Admin.ProfileMaxDuration)
+		}
+	}
+	switch c.Database.TxIsolation {
+	case "read_committed", "repeatable_read", "serializable":
+	default:
+		return fmt.Errorf("config: DB_TX_ISOLATION must be one of read_committed|repeatable_read|serializable, got %q", c.Database.TxIsolation)
+	}
+	if c.Database.TxMaxRetries < 0 {
+		return fmt.Errorf("config: DB_TX_MAX_RETRIES must be non-negative, got %d", c.Database.TxMaxRetries)
+	}
+	if c.Database.SearchLanguage == "" {
+		return fmt.Errorf("config: DB_SEARCH_LANGUAGE must not be empty")
+	}
+	if c.Idempotency.TTL <= 0 {
+		return fmt.Errorf("config: IDEMPOTENCY_TTL must be positive, got %s", c.Idempotency.TTL)
+	}
+	if c.Idempotency.WaitTimeout < 0 {
+		return fmt.Errorf("config: IDEMPOTENCY_WAIT_TIMEOUT must be non-negative, got %s", c.Idempotency.WaitTimeout)
+	}
+	if c.Idempotency.LockTimeout <= 0 || c.Idempotency.LockTimeout > c.Idempotency.TTL {
+		return fmt.Errorf("config: IDEMPOTENCY_LOCK_TIMEOUT must be positive and not exceed IDEMPOTENCY_TTL, got %s", c.Idempotency.LockTimeout)
+	}
+	if _, err := scheduler.Parse(c.Idempotency.CleanupSchedule); err != nil {
+		return fmt.Errorf("config: IDEMPOTENCY_CLEANUP_SCHEDULE: %w", err)
+	}
+	if c.Cache.Enabled {
+		if c.Cache.MaxEntries <= 0 {
+			return fmt.Errorf("config: CACHE_MAX_ENTRIES must be positive, got %d", c.Cache.MaxEntries)
+		}
+		if c.Cache.TTL <= 0 {
+			return fmt.Errorf("config: CACHE_TTL must be positive, got %s", c.Cache.TTL)
+		}
+	}
+	if c.Events.HeartbeatInterval <= 0 {
+		return fmt.Errorf("config: EVENTS_HEARTBEAT_INTERVAL must be positive, got %s", c.Events.HeartbeatInterval)
+	}
+	if c.Events.BufferSize <= 0 {
+		return fmt.Errorf("config: EVENTS_BUFFER_SIZE must be positive, got %d", c.Events.BufferSize)
+	}
+	if c.Events.PollInterval <= 0 {
+		return fmt.Errorf("config: EVENTS_POLL_INTERVAL must be positive, got %s", c.Events.PollInterval)
+	}
+	if c.Events.Retention <= 0 {
+		return fmt.Errorf("config: EVENTS_RETENTION must be positive, got %s", c.Events.Retention)
+	}
+	if _, err := scheduler.Parse(c.Events.CleanupSchedule); err != nil {
+		return fmt.Errorf("config: EVENTS_CLEANUP_SCHEDULE: %w", err)
+	}
+	if c.WebSocket.MaxSubscriptions <= 0 {
+		return fmt.Errorf("config: WS_MAX_SUBSCRIPTIONS must be positive, got %d", c.WebSocket.MaxSubscriptions)
+	}
+	if c.WebSocket.MaxExampleIDs <= 0 {
+		return fmt.Errorf("config: WS_MAX_EXAMPLE_IDS must be positive, got %d", c.WebSocket.MaxExampleIDs)
+	}
+	if c.WebSocket.PingInterval <= 0 {
+		return fmt.Errorf("config: WS_PING_INTERVAL must be positive, got %s", c.WebSocket.PingInterval)
+	}
+	switch c.Outbox.Publisher {
+	case "stdout":
+	case "file":
+		if c.Outbox.FilePath == "" {
+			return fmt.Errorf("config: OUTBOX_FILE_PATH is required for OUTBOX_PUBLISHER=file")
+		}
+	default:
+		return fmt.Errorf("config: OUTBOX_PUBLISHER must be one of stdout|file, got %q", c.Outbox.Publisher)
+	}
+	if c.Outbox.PollInterval <= 0 {
+		return fmt.Errorf("config: OUTBOX_POLL_INTERVAL must be positive, got %s", c.Outbox.PollInterval)
+	}
+	if c.Outbox.BatchSize <= 0 {
+		return fmt.Errorf("config: OUTBOX_BATCH_SIZE must be positive, got %d", c.Outbox.BatchSize)
+	}
+	if c.Outbox.MaxAttempts <= 0 {
+		return fmt.Errorf("config: OUTBOX_MAX_ATTEMPTS must be positive, got %d", c.Outbox.MaxAttempts)
+	}
+	if c.Outbox.PublishTimeout <= 0 {
+		return fmt.Errorf("config: OUTBOX_PUBLISH_TIMEOUT must be positive, got %s", c.Outbox.PublishTimeout)
+	}
+	if c.Outbox.Lease <= c.Outbox.PublishTimeout {
+		return fmt.Errorf("config: OUTBOX_LEASE must exceed OUTBOX_PUBLISH_TIMEOUT, got %s", c.Outbox.Lease)
+	}
+	if c.Outbox.RetryBaseDelay <= 0 || c.Outbox.RetryMaxDelay < c.Outbox.RetryBaseDelay {
+		return fmt.Errorf("config: OUTBOX_RETRY_BASE_DELAY must be positive and not exceed OUTBOX_RETRY_MAX_DELAY, got %s", c.Outbox.RetryBaseDelay)
+	}
+	if c.Webhook.Timeout <= 0 {
+		return fmt.Errorf("config: WEBHOOK_TIMEOUT must be positive, got %s", c.Webhook.Timeout)
+	}
+	if c.Webhook.Lease <= c.Webhook.Timeout {
+		return fmt.Errorf("config: WEBHOOK_LEASE must exceed WEBHOOK_TIMEOUT, got %s", c.Webhook.Lease)
+	}
+	if c.Webhook.PollInterval <= 0 {
+		return fmt.Errorf("config: WEBHOOK_POLL_INTERVAL must be positive, got %s", c.Webhook.PollInterval)
+	}
+	if c.Webhook.BatchSize <= 0 {
+		return fmt.Errorf("config: WEBHOOK_BATCH_SIZE must be positive, got %d", c.Webhook.BatchSize)
+	}
+	if c.Webhook.MaxAttempts <= 0 {
+		return fmt.Errorf("config: WEBHOOK_MAX_ATTEMPTS must be positive, got %d", c.Webhook.MaxAttempts)
+	}
+	if c.Webhook.RetryBaseDelay <= 0 || c.Webhook.RetryMaxDelay < c.Webhook.RetryBaseDelay {
+		return fmt.Errorf("config: WEBHOOK_RETRY_BASE_DELAY must be positive and not exceed WEBHOOK_RETRY_MAX_DELAY, got %s", c.Webhook.RetryBaseDelay)
+	}
+	if c.Webhook.DisableAfter <= 0 {
+		return fmt.Errorf("config: WEBHOOK_DISABLE_AFTER must be positive, got %d", c.Webhook.DisableAfter)
+	}
+	if c.Webhook.Retention <= 0 {
+		return fmt.Errorf("config: WEBHOOK_RETENTION must be positive, got %s", c.Webhook.Retention)
+	}
+	if _, err := scheduler.Parse(c.Webhook.CleanupSchedule); err != nil {
+		return fmt.Errorf("config: WEBHOOK_CLEANUP_SCHEDULE: %w", err)
+	}
+	if c.Jobs.Workers <= 0 {
+		return fmt.Errorf("config: JOBS_WORKERS must be positive, got %d", c.Jobs.Workers)
+	}
+	if c.Jobs.PollInterval <= 0 {
+		return fmt.Errorf("config: JOBS_POLL_INTERVAL must be positive, got %s", c.Jobs.PollInterval)
+	}
+	if c.Jobs.VisibilityTimeout <= 0 {
+		return fmt.Errorf("config: JOBS_VISIBILITY_TIMEOUT must be positive, got %s", c.Jobs.VisibilityTimeout)
+	}
+	if c.Jobs.MaxAttempts <= 0 {
+		return fmt.Errorf("config: JOBS_MAX_ATTEMPTS must be positive, got %d", c.Jobs.MaxAttempts)
+	}
+	if c.Jobs.RetryBaseDelay <= 0 || c.Jobs.RetryMaxDelay < c.Jobs.RetryBaseDelay {
+		return fmt.Errorf("config: JOBS_RETRY_BASE_DELAY must be positive and not exceed JOBS_RETRY_MAX_DELAY, got %s", c.Jobs.RetryBaseDelay)
+	}
+	if c.Jobs.Retention <= 0 {
+		return fmt.Errorf("config: JOBS_RETENTION must be positive, got %s", c.Jobs.Retention)
+	}
+	if _, err := scheduler.Parse(c.Jobs.CleanupSchedule); err != nil {
+		return fmt.Errorf("config: JOBS_CLEANUP_SCHEDULE: %w", err)
+	}
+	if c.Operations.Retention <= 0 {
+		return fmt.Errorf("config: OPERATIONS_RETENTION must be positive, got %s", c.Operations.Retention)
+	}
+	if _, err := scheduler.Parse(c.Operations.CleanupSchedule); err != nil {
+		return fmt.Errorf("config: OPERATIONS_CLEANUP_SCHEDULE: %w", err)
+	}
+	if _, err := scheduler.Parse(c.Stats.RefreshSchedule); err != nil {
+		return fmt.Errorf("config: STATS_REFRESH_SCHEDULE: %w", err)
+	}
 	switch c.Database.SSLMode {
 	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
 	default:
@@ -138,6 +725,33 @@ func (c *Config) validate() error {
 	return nil
 }
 
+// AdminLoopback сообщает, что admin-листенер доступен только с этой же
+// машины. Пустой ADMIN_HOST — все интерфейсы.
+func (c *Config) AdminLoopback() bool {
+	if c.Admin.Host == "localhost" {
+		return true
+	}
+	ip, err := netip.ParseAddr(c.Admin.Host)
+	return err == nil && ip.IsLoopback()
+}
+
+// AdminEnabled сообщает, поднимается ли отдельный admin-листенер.
+func (c *Config) AdminEnabled() bool {
+	return c.Admin.Port != 0
+}
+
+// Redacted возвращает копию конфигурации без секретов — для дампа на admin-поверхности.
+func (c *Config) Redacted() Config {
+	redacted := *c
+	if redacted.Database.Password != "" {
+		redacted.Database.Password = redactedValue
+	}
+	if redacted.Admin.Token != "" {
+		redacted.Admin.Token = redactedValue
+	}
+	return redacted
+}
+
 func (c *Config) DatabaseDSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
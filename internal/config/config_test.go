@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,6 +29,15 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Database.SSLMode != "disable" {
 		t.Errorf("expected DB_SSLMODE=disable, got %q", cfg.Database.SSLMode)
 	}
+	if cfg.Database.TxIsolation != "read_committed" {
+		t.Errorf("expected DB_TX_ISOLATION=read_committed, got %q", cfg.Database.TxIsolation)
+	}
+	if cfg.Database.TxMaxRetries != 3 {
+		t.Errorf("expected DB_TX_MAX_RETRIES=3, got %d", cfg.Database.TxMaxRetries)
+	}
+	if cfg.Database.SearchLanguage != "english" {
+		t.Errorf("expected DB_SEARCH_LANGUAGE=english, got %q", cfg.Database.SearchLanguage)
+	}
 	if cfg.Server.Host != "localhost" {
 		t.Errorf("expected SERVER_HOST=localhost, got %q", cfg.Server.Host)
 	}
@@ -55,6 +65,24 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Server.CORSAllowOrigins != "*" {
 		t.Errorf("expected CORSAllowOrigins=*, got %q", cfg.Server.CORSAllowOrigins)
 	}
+	if cfg.Server.PreStopDelay != 0 {
+		t.Errorf("expected PreStopDelay=0, got %v", cfg.Server.PreStopDelay)
+	}
+	if cfg.Server.ShutdownTimeout != 30*time.Second {
+		t.Errorf("expected ShutdownTimeout=30s, got %v", cfg.Server.ShutdownTimeout)
+	}
+	if cfg.Server.ExampleCacheControl != "private, no-cache" || cfg.Server.ListCacheControl != "private, no-cache" {
+		t.Errorf("expected Cache-Control=private, no-cache, got %q and %q", cfg.Server.ExampleCacheControl, cfg.Server.ListCacheControl)
+	}
+	if cfg.Admin.Host != "127.0.0.1" {
+		t.Errorf("expected ADMIN_HOST=127.0.0.1, got %q", cfg.Admin.Host)
+	}
+	if cfg.AdminEnabled() {
+		t.Error("expected admin listener to be disabled by default")
+	}
+	if cfg.Idempotency.TTL != 24*time.Hour || cfg.Idempotency.WaitTimeout != 5*time.Second || cfg.Idempotency.LockTimeout != time.Minute {
+		t.Errorf("unexpected idempotency defaults: %+v", cfg.Idempotency)
+	}
 }
 
 func TestLoad_CustomValues(t *testing.T) {
@@ -196,6 +224,129 @@ func TestLoad_Validation(t *testing.T) {
 		}
 	})
 
+	t.Run("non-positive shutdown timeout", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "0s")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for SERVER_SHUTDOWN_TIMEOUT=0s")
+		}
+	})
+
+	t.Run("admin port clashes with server port", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("ADMIN_PORT", "8080")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for ADMIN_PORT equal to SERVER_PORT")
+		}
+	})
+
+	t.Run("pprof without admin token", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("ADMIN_PORT", "9090")
+		t.Setenv("ADMIN_PPROF_ENABLED", "true")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for ADMIN_PPROF_ENABLED without ADMIN_TOKEN")
+		}
+	})
+
+	t.Run("invalid tx isolation", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("DB_TX_ISOLATION", "snapshot")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for invalid DB_TX_ISOLATION")
+		}
+	})
+
+	t.Run("idempotency lock timeout exceeds ttl", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("IDEMPOTENCY_TTL", "1m")
+		t.Setenv("IDEMPOTENCY_LOCK_TIMEOUT", "2m")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for IDEMPOTENCY_LOCK_TIMEOUT")
+		}
+	})
+
+	t.Run("enabled cache without entries", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("CACHE_ENABLED", "true")
+		t.Setenv("CACHE_MAX_ENTRIES", "0")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for CACHE_MAX_ENTRIES=0")
+		}
+	})
+
+	t.Run("unknown outbox publisher", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("OUTBOX_PUBLISHER", "kafka")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for unknown OUTBOX_PUBLISHER")
+		}
+	})
+
+	t.Run("outbox lease shorter than publish timeout", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("OUTBOX_LEASE", "5s")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for OUTBOX_LEASE below OUTBOX_PUBLISH_TIMEOUT")
+		}
+	})
+
+	t.Run("webhook lease shorter than timeout", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("WEBHOOK_LEASE", "5s")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for WEBHOOK_LEASE below WEBHOOK_TIMEOUT")
+		}
+	})
+
+	t.Run("zero job workers", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("JOBS_WORKERS", "0")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for JOBS_WORKERS")
+		}
+	})
+
+	t.Run("invalid cleanup schedule", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("OPERATIONS_CLEANUP_SCHEDULE", "61 * * * *")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected validation error for OPERATIONS_CLEANUP_SCHEDULE")
+		}
+	})
+
+	t.Run("removed cleanup interval", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "pass")
+		t.Setenv("IDEMPOTENCY_CLEANUP_INTERVAL", "1h")
+
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), "IDEMPOTENCY_CLEANUP_SCHEDULE") {
+			t.Fatalf("expected error pointing to IDEMPOTENCY_CLEANUP_SCHEDULE, got %v", err)
+		}
+	})
+
 	t.Run("invalid sslmode", func(t *testing.T) {
 		t.Setenv("DB_PASSWORD", "pass")
 		t.Setenv("DB_SSLMODE", "bogus")
@@ -206,3 +357,21 @@ func TestLoad_Validation(t *testing.T) {
 		}
 	})
 }
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{
+		Database: DatabaseConfig{Password: "secret"},
+		Admin:    AdminConfig{Token: "token"},
+	}
+
+	redacted := cfg.Redacted()
+	if redacted.Database.Password != "***" {
+		t.Errorf("expected redacted password, got %q", redacted.Database.Password)
+	}
+	if redacted.Admin.Token != "***" {
+		t.Errorf("expected redacted admin token, got %q", redacted.Admin.Token)
+	}
+	if cfg.Database.Password != "secret" {
+		t.Error("Redacted must not modify the original config")
+	}
+}
@@ -0,0 +1,232 @@
+// Package events рассылает события журнала изменений examples подписчикам
+// потока /api/v1/examples/stream. Источник — таблица example_events, общая
+// для всех реплик; о новых событиях Feed узнаёт по LISTEN/NOTIFY (Notify) и,
+// на случай потерянного уведомления, периодическим опросом.
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+)
+
+// pollBatchSize — сколько событий читается из журнала за один запрос.
+const pollBatchSize = 500
+
+// ErrCannotResume — продолжить поток с запрошенного ID без пропусков нельзя:
+// события после него уже удалены по сроку хранения или такого ID в журнале
+// ещё не было.
+var ErrCannotResume = errors.New("cannot resume from the requested event id")
+
+// Store — журнал событий.
+type Store interface {
+	EventsSince(ctx context.Context, afterID int64, limit int) ([]models.ExampleEvent, error)
+	EventBounds(ctx context.Context) (oldest, latest int64, err error)
+}
+
+// Feed читает новые события журнала и раздаёт их подписчикам.
+type Feed struct {
+	store        Store
+	logger       *slog.Logger
+	bufferSize   int
+	pollInterval time.Duration
+
+	wake chan struct{}
+	// ready закрывается, когда Run прочитал начальную позицию журнала. До
+	// этого Latest и Replay ждут: иначе подписчик мог бы начать с позиции
+	// раньше той, с которой Feed начнёт раздачу, и пропустить события между ними.
+	ready chan struct{}
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription — подписка на новые события. Если подписчик не успевает
+// разбирать буфер, Feed отключает его (закрывает Done), а не копит события
+// в памяти: клиент переподключится с Last-Event-ID и дочитает из журнала.
+type Subscription struct {
+	events chan models.ExampleEvent
+	done   chan struct{}
+}
+
+// Events — канал новых событий.
+func (s *Subscription) Events() <-chan models.ExampleEvent {
+	return s.events
+}
+
+// Done закрывается, когда подписчик отключён из-за переполнения буфера.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// NewFeed создаёт Feed. bufferSize — сколько неотправленных событий может
+// накопиться у одного подписчика.
+func NewFeed(store Store, bufferSize int, pollInterval time.Duration, logger *slog.Logger) *Feed {
+	return &Feed{
+		store:        store,
+		logger:       logger,
+		bufferSize:   bufferSize,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+		ready:        make(chan struct{}),
+		subs:         make(map[*Subscription]struct{}),
+	}
+}
+
+// Notify сообщает, что в журнале появились события. Не блокируется:
+// несколько уведомлений подряд схлопываются в одно чтение.
+func (f *Feed) Notify() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe регистрирует подписчика. События, появившиеся в журнале до
+// подписки, читаются через Replay.
+func (f *Feed) Subscribe() *Subscription {
+	sub := &Subscription{
+		events: make(chan models.ExampleEvent, f.bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	metrics.StreamSubscribers.Add(1)
+	return sub
+}
+
+// Unsubscribe снимает подписку. Повторный вызов и вызов для уже
+// отключённого подписчика безопасны.
+func (f *Feed) Unsubscribe(sub *Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subs[sub]; ok {
+		delete(f.subs, sub)
+		metrics.StreamSubscribers.Add(-1)
+	}
+}
+
+// Latest возвращает ID последнего события журнала — точку, с которой
+// начинает подписчик без Last-Event-ID.
+func (f *Feed) Latest(ctx context.Context) (int64, error) {
+	if err := f.waitReady(ctx); err != nil {
+		return 0, err
+	}
+	_, latest, err := f.store.EventBounds(ctx)
+	return latest, err
+}
+
+// Replay возвращает до limit событий после afterID или ErrCannotResume.
+func (f *Feed) Replay(ctx context.Context, afterID int64, limit int) ([]models.ExampleEvent, error) {
+	if err := f.waitReady(ctx); err != nil {
+		return nil, err
+	}
+	events, err := f.store.EventsSince(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 && events[0].ID == afterID+1 {
+		return events, nil
+	}
+
+	// Первое событие не следует сразу за afterID: это либо откатившиеся
+	// транзакции (пропуски в последовательности), либо удалённая история,
+	// либо ID из другого журнала.
+	oldest, latest, err := f.store.EventBounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if oldest > afterID+1 || latest < afterID {
+		return nil, ErrCannotResume
+	}
+	return events, nil
+}
+
+// Run читает журнал по уведомлениям и раз в pollInterval и раздаёт новые
+// события подписчикам, пока ctx не отменён.
+func (f *Feed) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.pollInterval)
+	defer ticker.Stop()
+
+	head, known := int64(0), false
+	for {
+		if !known {
+			_, latest, err := f.store.EventBounds(ctx)
+			if err == nil {
+				head, known = latest, true
+				close(f.ready)
+			} else if ctx.Err() == nil {
+				f.logger.Error("Failed to read events head", slog.String("error", err.Error()))
+			}
+		} else {
+			head = f.poll(ctx, head)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-f.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Feed) waitReady(ctx context.Context) error {
+	select {
+	case <-f.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// poll раздаёт все события после head и возвращает новую позицию.
+func (f *Feed) poll(ctx context.Context, head int64) int64 {
+	for {
+		events, err := f.store.EventsSince(ctx, head, pollBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				f.logger.Error("Failed to read events", slog.Int64("after_id", head), slog.String("error", err.Error()))
+			}
+			return head
+		}
+		if len(events) == 0 {
+			return head
+		}
+
+		f.broadcast(events)
+		head = events[len(events)-1].ID
+		if len(events) < pollBatchSize {
+			return head
+		}
+	}
+}
+
+func (f *Feed) broadcast(events []models.ExampleEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for sub := range f.subs {
+		for _, event := range events {
+			select {
+			case sub.events <- event:
+				continue
+			default:
+			}
+			// Буфер полон: подписчик отстаёт, отключаем его.
+			delete(f.subs, sub)
+			close(sub.done)
+			metrics.StreamSubscribers.Add(-1)
+			metrics.StreamSubscribersDropped.Add(1)
+			break
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// memoryStore — журнал событий в памяти.
+type memoryStore struct {
+	mu     sync.Mutex
+	events []models.ExampleEvent
+}
+
+func (s *memoryStore) append(ids ...int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.events = append(s.events, models.ExampleEvent{ID: id, Type: "updated", ExampleID: int(id)})
+	}
+}
+
+func (s *memoryStore) EventsSince(_ context.Context, afterID int64, limit int) ([]models.ExampleEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []models.ExampleEvent
+	for _, event := range s.events {
+		if event.ID > afterID && len(out) < limit {
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStore) EventBounds(context.Context) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return 0, 0, nil
+	}
+	return s.events[0].ID, s.events[len(s.events)-1].ID, nil
+}
+
+func startFeed(t *testing.T, store Store, bufferSize int) *Feed {
+	t.Helper()
+	feed := NewFeed(store, bufferSize, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = feed.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return feed
+}
+
+func receive(t *testing.T, sub *Subscription) models.ExampleEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return models.ExampleEvent{}
+	}
+}
+
+func TestFeed_BroadcastsNewEvents(t *testing.T) {
+	store := &memoryStore{}
+	store.append(1, 2)
+	feed := startFeed(t, store, 10)
+
+	sub := feed.Subscribe()
+	defer feed.Unsubscribe(sub)
+	if latest, err := feed.Latest(context.Background()); err != nil || latest != 2 {
+		t.Fatalf("Latest() = %d, %v; want 2", latest, err)
+	}
+
+	store.append(3, 4)
+	feed.Notify()
+
+	for _, want := range []int64{3, 4} {
+		if got := receive(t, sub).ID; got != want {
+			t.Fatalf("expected event %d, got %d", want, got)
+		}
+	}
+}
+
+func TestFeed_DropsSlowSubscriber(t *testing.T) {
+	store := &memoryStore{}
+	feed := startFeed(t, store, 1)
+	if _, err := feed.Latest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := feed.Subscribe()
+	store.append(1, 2)
+	feed.Notify()
+
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected slow subscriber to be dropped")
+	}
+	feed.Unsubscribe(sub) // повторное снятие безопасно
+}
+
+func TestFeed_Replay(t *testing.T) {
+	store := &memoryStore{}
+	store.append(5, 7, 8)
+	feed := startFeed(t, store, 10)
+	ctx := context.Background()
+
+	events, err := feed.Replay(ctx, 5, 10)
+	if err != nil || len(events) != 2 || events[0].ID != 7 {
+		t.Fatalf("Replay(5) = %v, %v; want events 7 and 8 across the sequence gap", events, err)
+	}
+	if events, err := feed.Replay(ctx, 8, 10); err != nil || len(events) != 0 {
+		t.Fatalf("Replay(8) = %v, %v; want no events", events, err)
+	}
+	if _, err := feed.Replay(ctx, 2, 10); !errors.Is(err, ErrCannotResume) {
+		t.Fatalf("Replay(2) error = %v; want ErrCannotResume for deleted history", err)
+	}
+	if _, err := feed.Replay(ctx, 100, 10); !errors.Is(err, ErrCannotResume) {
+		t.Fatalf("Replay(100) error = %v; want ErrCannotResume for unknown id", err)
+	}
+}
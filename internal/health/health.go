@@ -0,0 +1,125 @@
+// Package health — реестр проверок зависимостей для проб readiness/startup.
+// Компоненты (БД, миграции, воркеры, кеши) регистрируют проверки со своими
+// таймаутами и критичностью; падение некритичной проверки отражается в отчёте,
+// но не снимает под с трафика.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// CheckFunc возвращает ошибку, если компонент недоступен.
+type CheckFunc func(ctx context.Context) error
+
+type Check struct {
+	Name string
+	// Timeout ограничивает одну проверку; 0 — 2 секунды.
+	Timeout time.Duration
+	// Critical: упавшая критичная проверка переводит readiness в 503.
+	Critical bool
+	Func     CheckFunc
+}
+
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []Check
+	started atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register добавляет проверку. Безопасно вызывать конкурентно с Check.
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = defaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+}
+
+// MarkStarted отмечает завершение стартовых процедур (миграции, прогрев) —
+// после этого /startupz отвечает 200.
+func (r *Registry) MarkStarted() {
+	r.started.Store(true)
+}
+
+func (r *Registry) Started() bool {
+	return r.started.Load()
+}
+
+// Check параллельно выполняет все проверки и собирает отчёт в порядке регистрации.
+func (r *Registry) Check(ctx context.Context) models.HealthReport {
+	r.mu.RLock()
+	checks := make([]Check, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	components := make([]models.ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := models.HealthReport{Status: StatusUp, Components: components}
+	for _, component := range components {
+		if component.Critical && component.Status != StatusUp {
+			report.Status = StatusDown
+			break
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) models.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	err := check.Func(ctx)
+	latency := time.Since(startedAt)
+
+	component := models.ComponentHealth{
+		Name:      check.Name,
+		Status:    StatusUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+
+	return component
+}
+
+// FirstFailure возвращает имя первой упавшей критичной проверки.
+func FirstFailure(report models.HealthReport) (string, bool) {
+	for _, component := range report.Components {
+		if component.Critical && component.Status != StatusUp {
+			return component.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	t.Run("all up", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "database", Critical: true, Func: func(context.Context) error { return nil }})
+
+		report := r.Check(context.Background())
+		if report.Status != StatusUp {
+			t.Fatalf("expected up, got %q", report.Status)
+		}
+		if len(report.Components) != 1 || report.Components[0].Name != "database" {
+			t.Fatalf("unexpected components: %+v", report.Components)
+		}
+	})
+
+	t.Run("non-critical failure keeps status up", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "database", Critical: true, Func: func(context.Context) error { return nil }})
+		r.Register(Check{Name: "cache", Func: func(context.Context) error { return errors.New("miss") }})
+
+		report := r.Check(context.Background())
+		if report.Status != StatusUp {
+			t.Fatalf("expected up, got %q", report.Status)
+		}
+		if report.Components[1].Status != StatusDown || report.Components[1].Error != "miss" {
+			t.Fatalf("expected cache down, got %+v", report.Components[1])
+		}
+		if _, failed := FirstFailure(report); failed {
+			t.Fatal("expected no critical failure")
+		}
+	})
+
+	t.Run("critical failure", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "database", Critical: true, Func: func(context.Context) error { return errors.New("down") }})
+
+		report := r.Check(context.Background())
+		if report.Status != StatusDown {
+			t.Fatalf("expected down, got %q", report.Status)
+		}
+		if name, failed := FirstFailure(report); !failed || name != "database" {
+			t.Fatalf("expected database failure, got %q", name)
+		}
+	})
+
+	t.Run("per-check timeout", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{
+			Name:     "slow",
+			Critical: true,
+			Timeout:  10 * time.Millisecond,
+			Func: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+
+		report := r.Check(context.Background())
+		if report.Status != StatusDown {
+			t.Fatalf("expected down after timeout, got %q", report.Status)
+		}
+	})
+}
+
+func TestRegistry_Started(t *testing.T) {
+	r := NewRegistry()
+	if r.Started() {
+		t.Fatal("expected registry not started")
+	}
+
+	r.MarkStarted()
+	if !r.Started() {
+		t.Fatal("expected registry started")
+	}
+}
@@ -0,0 +1,39 @@
+// Package idempotency хранит ответы на запросы с заголовком Idempotency-Key,
+// чтобы повтор запроса (ретрай клиента после таймаута) получил исходный ответ,
+// а не выполнился второй раз. HTTP-часть — middleware в internal/server;
+// здесь — модель записи, интерфейс хранилища и in-memory реализация.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response — сохранённый ответ на первый запрос с ключом.
+type Response struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+}
+
+// Record — состояние ключа. Response == nil, пока первый запрос выполняется.
+type Record struct {
+	RequestHash []byte
+	Response    *Response
+}
+
+// Store — хранилище ключей. Ключ уникален в пределах principal: разные
+// клиенты могут использовать одинаковые ключи независимо.
+type Store interface {
+	// Acquire атомарно закрепляет ключ за текущим запросом на lockTimeout и
+	// возвращает acquired=true. Если ключ уже занят — возвращает существующую
+	// запись. Истёкшие (старше ttl) записи и брошенные блокировки (процесс
+	// упал, не дописав ответ) перезахватываются.
+	Acquire(ctx context.Context, principal, key string, requestHash []byte, lockTimeout, ttl time.Duration) (rec *Record, acquired bool, err error)
+	// Complete сохраняет ответ и снимает блокировку.
+	Complete(ctx context.Context, principal, key string, resp *Response) error
+	// Release удаляет незавершённую запись, чтобы запрос можно было повторить.
+	Release(ctx context.Context, principal, key string) error
+	// DeleteExpired удаляет записи с истёкшим TTL.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	record      Record
+	lockedUntil time.Time
+	expiresAt   time.Time
+}
+
+// MemoryStore — Store в памяти процесса для тестов и локального запуска.
+// Ключи не переживают рестарт и не разделяются между репликами.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[[2]string]*memoryEntry
+	now     func() time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[[2]string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Acquire(_ context.Context, principal, key string, requestHash []byte, lockTimeout, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	id := [2]string{principal, key}
+	if entry, ok := s.entries[id]; ok {
+		abandoned := entry.record.Response == nil && !now.Before(entry.lockedUntil)
+		if now.Before(entry.expiresAt) && !abandoned {
+			record := entry.record
+			return &record, false, nil
+		}
+	}
+
+	s.entries[id] = &memoryEntry{
+		record:      Record{RequestHash: requestHash},
+		lockedUntil: now.Add(lockTimeout),
+		expiresAt:   now.Add(ttl),
+	}
+	return &Record{RequestHash: requestHash}, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, principal, key string, resp *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[[2]string{principal, key}]; ok && entry.record.Response == nil {
+		entry.record.Response = resp
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, principal, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := [2]string{principal, key}
+	if entry, ok := s.entries[id]; ok && entry.record.Response == nil {
+		delete(s.entries, id)
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpired(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var deleted int64
+	for id, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,48 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	st := NewMemoryStore()
+	st.now = func() time.Time { return now }
+
+	if _, acquired, _ := st.Acquire(ctx, "alice", "k", []byte("h1"), time.Minute, time.Hour); !acquired {
+		t.Fatal("expected first acquire to succeed")
+	}
+
+	rec, acquired, _ := st.Acquire(ctx, "alice", "k", []byte("h2"), time.Minute, time.Hour)
+	if acquired || rec.Response != nil || string(rec.RequestHash) != "h1" {
+		t.Fatalf("expected in-progress record, got %+v (acquired %v)", rec, acquired)
+	}
+
+	if _, acquired, _ := st.Acquire(ctx, "bob", "k", []byte("h1"), time.Minute, time.Hour); !acquired {
+		t.Fatal("keys must be scoped by principal")
+	}
+
+	_ = st.Complete(ctx, "alice", "k", &Response{Status: 201, Body: []byte("ok")})
+	rec, _, _ = st.Acquire(ctx, "alice", "k", []byte("h1"), time.Minute, time.Hour)
+	if rec.Response == nil || rec.Response.Status != 201 {
+		t.Fatalf("expected stored response, got %+v", rec)
+	}
+
+	t.Run("abandoned lock is reclaimed", func(t *testing.T) {
+		_, _, _ = st.Acquire(ctx, "carol", "k", []byte("h1"), time.Minute, time.Hour)
+		now = now.Add(2 * time.Minute)
+		if _, acquired, _ := st.Acquire(ctx, "carol", "k", []byte("h1"), time.Minute, time.Hour); !acquired {
+			t.Fatal("expected abandoned lock to be reclaimed")
+		}
+	})
+
+	t.Run("expired records are deleted", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		if deleted, _ := st.DeleteExpired(ctx); deleted != 3 {
+			t.Fatalf("expected 3 expired records, got %d", deleted)
+		}
+	})
+}
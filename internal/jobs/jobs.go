@@ -0,0 +1,144 @@
+// Package jobs выполняет фоновые задачи из очереди в Postgres. Задача — строка
+// таблицы jobs с типом (kind) и аргументами в JSON. Client ставит задачи в
+// очередь, Runner захватывает их через SELECT ... FOR UPDATE SKIP LOCKED и
+// вызывает обработчики, зарегистрированные через Register при старте.
+// Упавшие задачи повторяются с экспоненциальной задержкой. Пока обработчик
+// работает, воркер продлевает захват задачи; задача упавшего воркера
+// возвращается в очередь по истечении таймаута видимости.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Статусы задачи.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Args — аргументы задачи. Kind задаёт тип задачи и выбирает обработчик,
+// поэтому должен быть уникален и не зависеть от значения аргументов.
+type Args interface {
+	Kind() string
+}
+
+// Job — захваченная задача.
+type Job struct {
+	ID      int64
+	Kind    string
+	Payload json.RawMessage
+	// Attempt — номер текущей попытки, начиная с 1.
+	Attempt     int
+	MaxAttempts int
+	UniqueKey   string
+	RunAt       time.Time
+	CreatedAt   time.Time
+}
+
+// NewJob — задача для постановки в очередь.
+type NewJob struct {
+	Kind        string
+	Payload     json.RawMessage
+	UniqueKey   string
+	MaxAttempts int
+	// RunAt — не раньше какого момента выполнять; нулевое значение — сразу.
+	RunAt time.Time
+}
+
+// Store — очередь задач.
+type Store interface {
+	// Insert ставит задачу в очередь. Если незавершённая задача того же типа
+	// с тем же UniqueKey уже есть, новая не создаётся: возвращается ID
+	// существующей и inserted = false.
+	Insert(ctx context.Context, job NewJob) (id int64, inserted bool, err error)
+	// Claim захватывает на visibility до limit задач перечисленных типов,
+	// чьё время пришло, — включая задачи с истёкшим захватом — и увеличивает
+	// их номер попытки.
+	Claim(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]Job, error)
+	// Extend продлевает захват задачи ещё на visibility. false — задачу уже
+	// перезахватили или завершили.
+	Extend(ctx context.Context, job Job, visibility time.Duration) (bool, error)
+	// Complete помечает задачу выполненной.
+	Complete(ctx context.Context, job Job) error
+	// Retry возвращает задачу в очередь: она выполнится через retryIn.
+	Retry(ctx context.Context, job Job, retryIn time.Duration, errMsg string) error
+	// Fail помечает задачу failed: повторов больше не будет.
+	Fail(ctx context.Context, job Job, errMsg string) error
+	// Release возвращает прерванную задачу в очередь без учёта попытки.
+	Release(ctx context.Context, job Job) error
+}
+
+// Option меняет параметры постановки задачи.
+type Option func(*NewJob)
+
+// RunAt откладывает выполнение задачи до t.
+func RunAt(t time.Time) Option {
+	return func(j *NewJob) { j.RunAt = t }
+}
+
+// UniqueKey не даёт поставить вторую задачу того же типа с тем же ключом,
+// пока первая не завершилась.
+func UniqueKey(key string) Option {
+	return func(j *NewJob) { j.UniqueKey = key }
+}
+
+// MaxAttempts переопределяет число попыток по умолчанию.
+func MaxAttempts(n int) Option {
+	return func(j *NewJob) { j.MaxAttempts = n }
+}
+
+// Client ставит задачи в очередь.
+type Client struct {
+	store       Store
+	maxAttempts int
+}
+
+// NewClient создаёт клиента; maxAttempts — попыток по умолчанию.
+func NewClient(store Store, maxAttempts int) *Client {
+	return &Client{store: store, maxAttempts: maxAttempts}
+}
+
+// Enqueue ставит задачу в очередь и возвращает её ID. inserted = false, если
+// задача с тем же UniqueKey уже ждёт или выполняется — тогда ID её.
+func (c *Client) Enqueue(ctx context.Context, args Args, opts ...Option) (id int64, inserted bool, err error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return 0, false, fmt.Errorf("marshal %s job args: %w", args.Kind(), err)
+	}
+
+	job := NewJob{Kind: args.Kind(), Payload: payload, MaxAttempts: c.maxAttempts}
+	for _, opt := range opts {
+		opt(&job)
+	}
+	if job.MaxAttempts <= 0 {
+		return 0, false, fmt.Errorf("%s job: max attempts must be positive, got %d", job.Kind, job.MaxAttempts)
+	}
+
+	return c.store.Insert(ctx, job)
+}
+
+// permanentError — ошибка, после которой задачу не повторяют.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку обработчика неисправимой: задача сразу
+// становится failed, оставшиеся попытки не тратятся.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
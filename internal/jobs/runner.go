@@ -0,0 +1,269 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go-service-template/internal/background"
+	"go-service-template/internal/config"
+	"go-service-template/internal/metrics"
+)
+
+// HandlerFunc выполняет задачу. Ошибка означает неудачную попытку: задача
+// повторится, пока не исчерпает попытки, — если ошибка не обёрнута в Permanent.
+// ctx отменяется при жёсткой остановке сервиса и если захват задачи потерян.
+type HandlerFunc func(ctx context.Context, job Job) error
+
+// Runner выполняет задачи зарегистрированных типов в Workers параллельных
+// воркерах.
+type Runner struct {
+	store    Store
+	cfg      config.JobsConfig
+	logger   *slog.Logger
+	handlers map[string]HandlerFunc
+
+	// abortCtx прерывает выполняемые обработчики при жёсткой остановке.
+	abortCtx context.Context
+	abort    context.CancelFunc
+	done     chan struct{}
+}
+
+func NewRunner(store Store, cfg config.JobsConfig, logger *slog.Logger) *Runner {
+	abortCtx, abort := context.WithCancel(context.Background())
+	return &Runner{
+		store:    store,
+		cfg:      cfg,
+		logger:   logger,
+		handlers: make(map[string]HandlerFunc),
+		abortCtx: abortCtx,
+		abort:    abort,
+		done:     make(chan struct{}),
+	}
+}
+
+// Handle регистрирует обработчик задач типа kind. Вызывайте до Run;
+// повторная регистрация типа — ошибка программиста и вызывает панику.
+func (r *Runner) Handle(kind string, fn HandlerFunc) {
+	if _, ok := r.handlers[kind]; ok {
+		panic(fmt.Sprintf("jobs: handler for %q already registered", kind))
+	}
+	r.handlers[kind] = fn
+}
+
+// Register регистрирует типизированный обработчик: аргументы задачи
+// декодируются в T, тип задачи берётся из T.Kind(). T должен быть
+// типом-значением. Аргументы, которые не декодируются, — неисправимая ошибка.
+func Register[T Args](r *Runner, fn func(ctx context.Context, job Job, args T) error) {
+	var zero T
+	r.Handle(zero.Kind(), func(ctx context.Context, job Job) error {
+		var args T
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			return Permanent(fmt.Errorf("decode %s job args: %w", job.Kind, err))
+		}
+		return fn(ctx, job, args)
+	})
+}
+
+// Run выполняет задачи, пока ctx не отменён; затем перестаёт брать новые и
+// ждёт выполняемые (см. Stop).
+func (r *Runner) Run(ctx context.Context) error {
+	defer close(r.done)
+
+	kinds := slices.Sorted(maps.Keys(r.handlers))
+	if len(kinds) == 0 {
+		r.logger.Info("No job handlers registered, job runner is idle")
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for range r.cfg.Workers {
+		wg.Go(func() {
+			r.work(ctx, kinds)
+		})
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// Stop ждёт, пока Run завершит выполняемые задачи. Если ctx истёк раньше,
+// обработчики прерываются, а их задачи возвращаются в очередь без учёта
+// попытки — их сразу подхватит другая реплика.
+func (r *Runner) Stop(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	r.logger.Warn("Job shutdown budget exhausted, interrupting running jobs")
+	r.abort()
+	timer := time.NewTimer(background.StoreTimeout)
+	defer timer.Stop()
+	select {
+	case <-r.done:
+	case <-timer.C:
+	}
+	return ctx.Err()
+}
+
+// work — цикл одного воркера: задача нашлась — сразу следующая, иначе
+// ожидание PollInterval.
+func (r *Runner) work(ctx context.Context, kinds []string) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		wait := r.cfg.PollInterval
+		if r.runNext(ctx, kinds) {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (r *Runner) runNext(ctx context.Context, kinds []string) bool {
+	claimed, err := r.store.Claim(ctx, kinds, 1, r.cfg.VisibilityTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Failed to claim jobs", slog.String("error", err.Error()))
+		}
+		return false
+	}
+	if len(claimed) == 0 {
+		return false
+	}
+
+	r.execute(claimed[0])
+	return true
+}
+
+func (r *Runner) execute(job Job) {
+	logger := r.logger.With(
+		slog.Int64("job_id", job.ID),
+		slog.String("kind", job.Kind),
+		slog.Int("attempt", job.Attempt),
+	)
+	storeCtx, cancel := background.StoreContext(context.Background())
+	defer cancel()
+
+	if job.Attempt > job.MaxAttempts {
+		// Последняя попытка не вернула задачу за таймаут видимости —
+		// воркер упал или завис.
+		r.finish(storeCtx, logger, job, errors.New("visibility timeout expired on the last attempt"))
+		return
+	}
+
+	metrics.JobsRunning.Add(1)
+	started := time.Now()
+	handlerCtx, cancelHandler := context.WithCancel(r.abortCtx)
+	heartbeatDone := make(chan bool, 1)
+	go func() {
+		heartbeatDone <- r.heartbeat(handlerCtx, cancelHandler, logger, job)
+	}()
+	err := runHandler(handlerCtx, r.handlers[job.Kind], job)
+	cancelHandler()
+	lost := <-heartbeatDone
+	metrics.JobsRunning.Add(-1)
+
+	switch {
+	case lost:
+		// Задача уже у другого воркера: результат этой попытки не записываем.
+		metrics.JobsProcessed.Add("lost", 1)
+		logger.Warn("Job claim lost while running")
+	case err == nil:
+		metrics.JobsProcessed.Add("succeeded", 1)
+		if err := r.store.Complete(storeCtx, job); err != nil {
+			logger.Error("Failed to complete job", slog.String("error", err.Error()))
+			return
+		}
+		logger.Debug("Job succeeded", slog.Duration("took", time.Since(started)))
+	case r.abortCtx.Err() != nil:
+		metrics.JobsProcessed.Add("interrupted", 1)
+		if err := r.store.Release(storeCtx, job); err != nil {
+			logger.Error("Failed to release interrupted job", slog.String("error", err.Error()))
+			return
+		}
+		logger.Info("Job interrupted by shutdown")
+	case isPermanent(err) || job.Attempt >= job.MaxAttempts:
+		r.finish(storeCtx, logger, job, err)
+	default:
+		metrics.JobsProcessed.Add("retried", 1)
+		retryIn := r.backoff(job.Attempt)
+		logger.Warn("Job failed, will retry",
+			slog.Duration("retry_in", retryIn),
+			slog.String("error", err.Error()),
+		)
+		if err := r.store.Retry(storeCtx, job, retryIn, background.ErrorText(err)); err != nil {
+			logger.Error("Failed to reschedule job", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// heartbeat продлевает захват задачи каждую треть таймаута видимости, пока
+// ctx не отменён. Если захват потерян, отменяет обработчик и возвращает true.
+func (r *Runner) heartbeat(ctx context.Context, cancel context.CancelFunc, logger *slog.Logger, job Job) bool {
+	ticker := time.NewTicker(r.cfg.VisibilityTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		extendCtx, cancelExtend := context.WithTimeout(ctx, background.StoreTimeout)
+		ok, err := r.store.Extend(extendCtx, job, r.cfg.VisibilityTimeout)
+		cancelExtend()
+		if err != nil {
+			// Сбой связи с БД: захват ещё действует, попробуем на следующем тике.
+			if ctx.Err() == nil {
+				logger.Warn("Failed to extend job claim", slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if !ok {
+			cancel()
+			return true
+		}
+	}
+}
+
+// finish помечает задачу failed: повторов больше не будет.
+func (r *Runner) finish(ctx context.Context, logger *slog.Logger, job Job, cause error) {
+	metrics.JobsProcessed.Add("failed", 1)
+	logger.Error("Job failed permanently", slog.String("error", cause.Error()))
+	if err := r.store.Fail(ctx, job, background.ErrorText(cause)); err != nil {
+		logger.Error("Failed to mark job failed", slog.String("error", err.Error()))
+	}
+}
+
+// runHandler вызывает обработчик, превращая панику в ошибку попытки, чтобы
+// одна задача не уронила сервис.
+func runHandler(ctx context.Context, handler HandlerFunc, job Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// backoff — background.Backoff с jitter по настройкам повторов задач.
+func (r *Runner) backoff(attempt int) time.Duration {
+	return background.Jitter(background.Backoff(r.cfg.RetryBaseDelay, r.cfg.RetryMaxDelay, attempt))
+}
@@ -0,0 +1,236 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+)
+
+// fakeStore отдаёт заданные задачи по одной и запоминает результаты.
+type fakeStore struct {
+	mu        sync.Mutex
+	queue     []Job
+	inserted  []NewJob
+	completed []int64
+	retried   map[int64]time.Duration
+	failed    map[int64]string
+	released  []int64
+	extended  int
+	claimLost bool
+}
+
+func newFakeStore(queue ...Job) *fakeStore {
+	return &fakeStore{
+		queue:   queue,
+		retried: make(map[int64]time.Duration),
+		failed:  make(map[int64]string),
+	}
+}
+
+func (s *fakeStore) Insert(_ context.Context, job NewJob) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inserted = append(s.inserted, job)
+	return int64(len(s.inserted)), true, nil
+}
+
+func (s *fakeStore) Claim(context.Context, []string, int, time.Duration) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return nil, nil
+	}
+	job := s.queue[0]
+	s.queue = s.queue[1:]
+	return []Job{job}, nil
+}
+
+func (s *fakeStore) Extend(context.Context, Job, time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.extended++
+	return !s.claimLost, nil
+}
+
+func (s *fakeStore) Complete(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed = append(s.completed, job.ID)
+	return nil
+}
+
+func (s *fakeStore) Retry(_ context.Context, job Job, retryIn time.Duration, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retried[job.ID] = retryIn
+	return nil
+}
+
+func (s *fakeStore) Fail(_ context.Context, job Job, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed[job.ID] = errMsg
+	return nil
+}
+
+func (s *fakeStore) Release(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = append(s.released, job.ID)
+	return nil
+}
+
+type greetArgs struct {
+	Name string `json:"name"`
+}
+
+func (greetArgs) Kind() string { return "greet" }
+
+func newTestRunner(store Store) *Runner {
+	return NewRunner(store, config.JobsConfig{
+		Workers:           2,
+		PollInterval:      10 * time.Millisecond,
+		VisibilityTimeout: time.Minute,
+		RetryBaseDelay:    time.Second,
+		RetryMaxDelay:     time.Minute,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func greetJob(id int64, attempt int, payload string) Job {
+	return Job{ID: id, Kind: "greet", Payload: json.RawMessage(payload), Attempt: attempt, MaxAttempts: 3}
+}
+
+func TestRunner_Outcomes(t *testing.T) {
+	store := newFakeStore(
+		greetJob(1, 1, `{"name":"ok"}`),
+		greetJob(2, 1, `{"name":"flaky"}`),
+		greetJob(3, 3, `{"name":"flaky"}`),
+		greetJob(4, 1, `{"name":"bad"}`),
+		greetJob(5, 1, `{"name":"panic"}`),
+		greetJob(6, 1, `"not an object"`),
+		greetJob(7, 4, `{"name":"ok"}`),
+	)
+	runner := newTestRunner(store)
+	Register(runner, func(_ context.Context, _ Job, args greetArgs) error {
+		switch args.Name {
+		case "flaky":
+			return errors.New("temporary")
+		case "bad":
+			return Permanent(errors.New("invalid input"))
+		case "panic":
+			panic("boom")
+		}
+		return nil
+	})
+
+	for range 7 {
+		runner.runNext(context.Background(), []string{"greet"})
+	}
+
+	if len(store.completed) != 1 || store.completed[0] != 1 {
+		t.Errorf("expected job 1 completed, got %v", store.completed)
+	}
+	if retryIn, ok := store.retried[2]; !ok || retryIn < 500*time.Millisecond || retryIn > time.Second {
+		t.Errorf("expected job 2 retried in [500ms, 1s], got %v (%v)", retryIn, ok)
+	}
+	if _, ok := store.retried[5]; !ok {
+		t.Errorf("expected panicking job 5 to be retried, got %v", store.retried)
+	}
+	// 3 — последняя попытка, 4 — Permanent, 6 — аргументы не декодируются,
+	// 7 — захвачена повторно после последней попытки.
+	for _, id := range []int64{3, 4, 6, 7} {
+		if _, ok := store.failed[id]; !ok {
+			t.Errorf("expected job %d failed, got %v", id, store.failed)
+		}
+	}
+}
+
+func TestRunner_StopInterruptsRunningJobs(t *testing.T) {
+	store := newFakeStore(greetJob(1, 1, `{"name":"slow"}`))
+	runner := newTestRunner(store)
+	started := make(chan struct{})
+	Register(runner, func(ctx context.Context, _ Job, _ greetArgs) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	go func() { _ = runner.Run(ctx) }()
+	<-started
+	stop()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := runner.Stop(stopCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.released) != 1 || len(store.retried) != 0 {
+		t.Fatalf("expected the job released without a retry, got released %v, retried %v", store.released, store.retried)
+	}
+}
+
+func TestRunner_Heartbeat(t *testing.T) {
+	newRunner := func(store *fakeStore) *Runner {
+		runner := newTestRunner(store)
+		runner.cfg.VisibilityTimeout = 30 * time.Millisecond
+		Register(runner, func(ctx context.Context, _ Job, _ greetArgs) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			}
+		})
+		return runner
+	}
+
+	t.Run("extends claim of a long job", func(t *testing.T) {
+		store := newFakeStore(greetJob(1, 1, `{}`))
+		newRunner(store).runNext(context.Background(), []string{"greet"})
+
+		if store.extended < 2 || len(store.completed) != 1 {
+			t.Fatalf("expected completed job with extended claim, got %d extensions, completed %v", store.extended, store.completed)
+		}
+	})
+
+	t.Run("cancels job when claim is lost", func(t *testing.T) {
+		store := newFakeStore(greetJob(1, 1, `{}`))
+		store.claimLost = true
+		newRunner(store).runNext(context.Background(), []string{"greet"})
+
+		if len(store.completed) != 0 || len(store.retried) != 0 || len(store.failed) != 0 {
+			t.Fatalf("expected no result recorded for a lost job, got completed %v, retried %v, failed %v",
+				store.completed, store.retried, store.failed)
+		}
+	})
+}
+
+func TestClient_Enqueue(t *testing.T) {
+	store := newFakeStore()
+	client := NewClient(store, 5)
+	runAt := time.Now().Add(time.Hour)
+
+	if _, _, err := client.Enqueue(context.Background(), greetArgs{Name: "a"}, RunAt(runAt), UniqueKey("a")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := store.inserted[0]
+	if got.Kind != "greet" || string(got.Payload) != `{"name":"a"}` || got.MaxAttempts != 5 ||
+		got.UniqueKey != "a" || !got.RunAt.Equal(runAt) {
+		t.Errorf("unexpected job %+v", got)
+	}
+
+	if _, _, err := client.Enqueue(context.Background(), greetArgs{}, MaxAttempts(0)); err == nil {
+		t.Error("expected error for zero max attempts")
+	}
+}
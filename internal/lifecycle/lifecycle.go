@@ -0,0 +1,268 @@
+// Package lifecycle управляет запуском и остановкой компонентов приложения
+// (хранилище, кеши, воркеры, HTTP-листенеры). Компоненты объявляют зависимости;
+// менеджер стартует их в порядке зависимостей, останавливает в обратном,
+// ограничивает каждый хук таймаутом, собирает ошибки и сообщает о хуках,
+// которые не завершились и после таймаута.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Component — единица жизненного цикла. Хуки не должны блокироваться надолго:
+// долгоживущую работу (Listen, цикл воркера) запускайте через Manager.Go.
+type Component struct {
+	Name      string
+	DependsOn []string
+	Start     func(ctx context.Context) error // nil — нечего запускать
+	Stop      func(ctx context.Context) error // nil — нечего останавливать
+	// Timeout ограничивает каждый из хуков; 0 — только общий контекст. Stop
+	// получает Timeout как собственный бюджет: он не сокращается оттого, что
+	// предыдущие компоненты израсходовали общий контекст остановки.
+	Timeout time.Duration
+}
+
+// ErrHookAbandoned — хук не завершился и после таймаута: он продолжает
+// работать в фоне, и ресурсы компонента могут быть не освобождены.
+var ErrHookAbandoned = errors.New("hook still running after timeout")
+
+// abandonedHookWait — сколько Stop в конце ждёт хуки, брошенные по таймауту.
+var abandonedHookWait = time.Second
+
+// abandonedHook — хук, брошенный по таймауту; done закрывается, когда он
+// всё-таки завершится.
+type abandonedHook struct {
+	name string
+	done <-chan struct{}
+}
+
+type Manager struct {
+	logger *slog.Logger
+
+	mu         sync.Mutex
+	components []Component
+	started    []Component
+	abandoned  []abandonedHook
+
+	errs chan error
+}
+
+func New(logger *slog.Logger) *Manager {
+	return &Manager{
+		logger: logger,
+		errs:   make(chan error, 1),
+	}
+}
+
+// Register добавляет компонент. Вызывайте до Start.
+func (m *Manager) Register(component Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component)
+}
+
+// Start запускает компоненты в порядке зависимостей. При ошибке уже
+// запущенные компоненты останавливаются в обратном порядке.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	ordered, err := sortByDependencies(m.components)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, component := range ordered {
+		startedAt := time.Now()
+		if err := m.runHook(ctx, "start "+component.Name, component.Timeout, component.Start); err != nil {
+			startErr := fmt.Errorf("start %s: %w", component.Name, err)
+			if stopErr := m.Stop(ctx); stopErr != nil {
+				return errors.Join(startErr, stopErr)
+			}
+			return startErr
+		}
+
+		m.mu.Lock()
+		m.started = append(m.started, component)
+		m.mu.Unlock()
+
+		m.logger.Info("Component started",
+			slog.String("component", component.Name),
+			slog.Duration("took", time.Since(startedAt)),
+		)
+	}
+
+	return nil
+}
+
+// Stop останавливает запущенные компоненты в обратном порядке. Ошибка или
+// таймаут одного компонента не прерывает остановку остальных. Компонент с
+// Timeout останавливается в своём бюджете, даже если общий ctx уже истёк. В
+// конце Stop ждёт брошенные по таймауту хуки до abandonedHookWait и
+// возвращает ErrHookAbandoned для тех, что так и не завершились.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		component := started[i]
+		startedAt := time.Now()
+		stopCtx := ctx
+		if component.Timeout > 0 {
+			stopCtx = context.WithoutCancel(ctx)
+		}
+		if err := m.runHook(stopCtx, "stop "+component.Name, component.Timeout, component.Stop); err != nil {
+			m.logger.Error("Component failed to stop",
+				slog.String("component", component.Name),
+				slog.String("error", err.Error()),
+			)
+			errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, err))
+			continue
+		}
+
+		m.logger.Info("Component stopped",
+			slog.String("component", component.Name),
+			slog.Duration("took", time.Since(startedAt)),
+		)
+	}
+
+	errs = append(errs, m.awaitAbandoned()...)
+	return errors.Join(errs...)
+}
+
+// awaitAbandoned ждёт брошенные хуки до abandonedHookWait и возвращает
+// ошибки для оставшихся.
+func (m *Manager) awaitAbandoned() []error {
+	m.mu.Lock()
+	abandoned := m.abandoned
+	m.abandoned = nil
+	m.mu.Unlock()
+
+	timer := time.NewTimer(abandonedHookWait)
+	defer timer.Stop()
+
+	var errs []error
+	expired := false
+	for _, hook := range abandoned {
+		select {
+		case <-hook.done:
+			continue
+		default:
+		}
+		if !expired {
+			select {
+			case <-hook.done:
+				continue
+			case <-timer.C:
+				expired = true
+			}
+		}
+		m.logger.Error("Hook is still running after its timeout", slog.String("hook", hook.name))
+		errs = append(errs, fmt.Errorf("%s: %w", hook.name, ErrHookAbandoned))
+	}
+	return errs
+}
+
+// Go запускает долгоживущую работу компонента в фоне. Первая ошибка
+// доставляется в Errors — по ней приложение начинает остановку.
+func (m *Manager) Go(name string, fn func() error) {
+	go func() {
+		if err := fn(); err != nil {
+			select {
+			case m.errs <- fmt.Errorf("%s: %w", name, err):
+			default:
+				m.logger.Error("Component failed", slog.String("component", name), slog.String("error", err.Error()))
+			}
+		}
+	}()
+}
+
+// Errors отдаёт фатальные ошибки фоновой работы компонентов.
+func (m *Manager) Errors() <-chan error {
+	return m.errs
+}
+
+// runHook вызывает хук с таймаутом. Хук, не уложившийся в него, продолжает
+// работать в фоне; он запоминается, чтобы Stop дождался его или сообщил о нём.
+func (m *Manager) runHook(ctx context.Context, name string, timeout time.Duration, hook func(ctx context.Context) error) error {
+	if hook == nil {
+		return nil
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		result <- hook(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		m.mu.Lock()
+		m.abandoned = append(m.abandoned, abandonedHook{name: name, done: finished})
+		m.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// sortByDependencies — топологическая сортировка (Kahn) с сохранением порядка
+// регистрации среди независимых компонентов.
+func sortByDependencies(components []Component) ([]Component, error) {
+	index := make(map[string]int, len(components))
+	for i, component := range components {
+		if _, dup := index[component.Name]; dup {
+			return nil, fmt.Errorf("lifecycle: duplicate component %q", component.Name)
+		}
+		index[component.Name] = i
+	}
+
+	pending := make([]int, len(components))
+	dependents := make([][]int, len(components))
+	for i, component := range components {
+		for _, dep := range component.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("lifecycle: component %q depends on unknown %q", component.Name, dep)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	ordered := make([]Component, 0, len(components))
+	done := make([]bool, len(components))
+	for len(ordered) < len(components) {
+		progressed := false
+		for i, component := range components {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			done[i] = true
+			progressed = true
+			ordered = append(ordered, component)
+			for _, dependent := range dependents[i] {
+				pending[dependent]--
+			}
+			break
+		}
+		if !progressed {
+			return nil, errors.New("lifecycle: dependency cycle detected")
+		}
+	}
+
+	return ordered, nil
+}
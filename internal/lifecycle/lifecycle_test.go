@@ -0,0 +1,208 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, deps ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: deps,
+		Start: func(context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestManager_StartStopOrder(t *testing.T) {
+	rec := &recorder{}
+	m := New(testLogger())
+	// Регистрация в "неправильном" порядке: менеджер сам выстраивает зависимости.
+	m.Register(rec.component("http", "storage", "cache"))
+	m.Register(rec.component("cache", "storage"))
+	m.Register(rec.component("storage"))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+
+	want := []string{
+		"start storage", "start cache", "start http",
+		"stop http", "stop cache", "stop storage",
+	}
+	if !reflect.DeepEqual(rec.events, want) {
+		t.Fatalf("unexpected order:\n  got:  %v\n  want: %v", rec.events, want)
+	}
+}
+
+func TestManager_StartFailureRollsBack(t *testing.T) {
+	rec := &recorder{}
+	m := New(testLogger())
+	m.Register(rec.component("storage"))
+	m.Register(Component{
+		Name:      "worker",
+		DependsOn: []string{"storage"},
+		Start:     func(context.Context) error { return errors.New("boom") },
+	})
+
+	err := m.Start(context.Background())
+	if err == nil {
+		t.Fatal("expected start error")
+	}
+
+	want := []string{"start storage", "stop storage"}
+	if !reflect.DeepEqual(rec.events, want) {
+		t.Fatalf("unexpected events: %v", rec.events)
+	}
+}
+
+func TestManager_StopAggregatesErrorsAndTimeouts(t *testing.T) {
+	rec := &recorder{}
+	errFlush := errors.New("flush failed")
+	m := New(testLogger())
+	m.Register(rec.component("storage"))
+	m.Register(Component{
+		Name:      "cache",
+		DependsOn: []string{"storage"},
+		Stop:      func(context.Context) error { return errFlush },
+	})
+	m.Register(Component{
+		Name:      "worker",
+		DependsOn: []string{"storage"},
+		Timeout:   10 * time.Millisecond,
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	err := m.Stop(context.Background())
+	if !errors.Is(err, errFlush) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected aggregated errors, got: %v", err)
+	}
+	if rec.events[len(rec.events)-1] != "stop storage" {
+		t.Fatalf("expected storage to stop despite earlier failures, got %v", rec.events)
+	}
+}
+
+func TestManager_StopGivesEachComponentItsBudget(t *testing.T) {
+	var storageErr error
+	m := New(testLogger())
+	m.Register(Component{
+		Name:    "storage",
+		Timeout: time.Second,
+		Stop: func(ctx context.Context) error {
+			storageErr = ctx.Err()
+			return nil
+		},
+	})
+	m.Register(Component{
+		Name:      "http",
+		DependsOn: []string{"storage"},
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	// Общий бюджет целиком уходит на http.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected http to time out, got: %v", err)
+	}
+	if storageErr != nil {
+		t.Fatalf("expected storage to stop within its own budget, got context error %v", storageErr)
+	}
+}
+
+func TestManager_StopReportsAbandonedHooks(t *testing.T) {
+	defer func(wait time.Duration) { abandonedHookWait = wait }(abandonedHookWait)
+	abandonedHookWait = 10 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+	m := New(testLogger())
+	m.Register(Component{
+		Name:    "worker",
+		Timeout: 10 * time.Millisecond,
+		// Хук не смотрит на ctx и переживает свой таймаут.
+		Stop: func(context.Context) error {
+			<-release
+			return nil
+		},
+	})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	err := m.Stop(context.Background())
+	if !errors.Is(err, ErrHookAbandoned) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected abandoned hook to be reported, got: %v", err)
+	}
+}
+
+func TestManager_DependencyErrors(t *testing.T) {
+	t.Run("unknown dependency", func(t *testing.T) {
+		m := New(testLogger())
+		m.Register(Component{Name: "http", DependsOn: []string{"storage"}})
+
+		if err := m.Start(context.Background()); err == nil {
+			t.Fatal("expected error for unknown dependency")
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		m := New(testLogger())
+		m.Register(Component{Name: "a", DependsOn: []string{"b"}})
+		m.Register(Component{Name: "b", DependsOn: []string{"a"}})
+
+		if err := m.Start(context.Background()); err == nil {
+			t.Fatal("expected error for dependency cycle")
+		}
+	})
+}
+
+func TestManager_GoReportsErrors(t *testing.T) {
+	m := New(testLogger())
+	m.Go("http", func() error { return errors.New("bind failed") })
+
+	select {
+	case err := <-m.Errors():
+		if err == nil || err.Error() != "http: bind failed" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected error from background component")
+	}
+}
@@ -0,0 +1,67 @@
+// Package metrics содержит процессные метрики сервиса. Используется expvar из
+// стандартной библиотеки: метрики публикуются в JSON на admin-поверхности
+// (/metrics) без внешних зависимостей.
+package metrics
+
+import (
+	"expvar"
+	"net/http"
+	"strconv"
+)
+
+var (
+	// HTTPRequests — число обработанных запросов публичного API по HTTP-статусу.
+	HTTPRequests = expvar.NewMap("http_requests_total")
+	// HTTPInFlight — число запросов, обрабатываемых в данный момент.
+	HTTPInFlight = expvar.NewInt("http_requests_in_flight")
+	// HTTPDroppedOnShutdown — запросы, не успевшие завершиться за бюджет остановки.
+	HTTPDroppedOnShutdown = expvar.NewInt("http_requests_dropped_on_shutdown_total")
+	// IdempotencyReplays — ответы, отданные из хранилища по повторному Idempotency-Key.
+	IdempotencyReplays = expvar.NewInt("idempotency_replays_total")
+	// CacheHits и CacheMisses — чтения записи по ID из кеша и мимо него.
+	CacheHits   = expvar.NewInt("cache_hits_total")
+	CacheMisses = expvar.NewInt("cache_misses_total")
+	// CacheEvictions — записи, вытесненные из LRU из-за лимита размера.
+	CacheEvictions = expvar.NewInt("cache_evictions_total")
+	// CacheRemoteInvalidations — записи, удалённые из кеша по уведомлению
+	// LISTEN/NOTIFY от любой реплики.
+	CacheRemoteInvalidations = expvar.NewInt("cache_remote_invalidations_total")
+	// CacheFlushes — полные сбросы кеша после (пере)подключения слушателя.
+	CacheFlushes = expvar.NewInt("cache_flushes_total")
+	// StreamSubscribers — открытые подписки на поток изменений (SSE).
+	StreamSubscribers = expvar.NewInt("stream_subscribers")
+	// StreamSubscribersDropped — подписчики, отключённые из-за переполнения буфера.
+	StreamSubscribersDropped = expvar.NewInt("stream_subscribers_dropped_total")
+	// WebSocketConnections — открытые соединения /api/v1/ws.
+	WebSocketConnections = expvar.NewInt("websocket_connections")
+	// OutboxPublished — сообщения outbox, подтверждённые публикатором.
+	OutboxPublished = expvar.NewInt("outbox_published_total")
+	// OutboxFailures — неудачные попытки публикации (включая повторяемые).
+	OutboxFailures = expvar.NewInt("outbox_publish_failures_total")
+	// OutboxDeadLettered — сообщения, исчерпавшие попытки и переведённые в dead letter.
+	OutboxDeadLettered = expvar.NewInt("outbox_dead_lettered_total")
+	// WebhookAttempts — попытки доставки webhooks по результату (success, failure).
+	WebhookAttempts = expvar.NewMap("webhook_attempts_total")
+	// WebhookSubscriptionsDisabled — подписки, выключенные после серии ошибок.
+	WebhookSubscriptionsDisabled = expvar.NewInt("webhook_subscriptions_disabled_total")
+	// JobsProcessed — попытки выполнения фоновых задач по результату
+	// (succeeded, retried, failed, interrupted, lost).
+	JobsProcessed = expvar.NewMap("jobs_processed_total")
+	// JobsRunning — задачи, выполняемые в данный момент.
+	JobsRunning = expvar.NewInt("jobs_running")
+	// ScheduledRuns — запуски периодических задач по результату (succeeded,
+	// failed, skipped — tick выполнила другая реплика).
+	ScheduledRuns = expvar.NewMap("scheduled_runs_total")
+	// ListenerReconnects — обрывы соединения LISTEN по имени канала.
+	ListenerReconnects = expvar.NewMap("listener_reconnects_total")
+)
+
+// ObserveHTTPRequest учитывает завершённый HTTP-запрос с данным статусом.
+func ObserveHTTPRequest(status int) {
+	HTTPRequests.Add(strconv.Itoa(status), 1)
+}
+
+// Handler отдаёт все опубликованные метрики (включая memstats и cmdline из expvar).
+func Handler() http.Handler {
+	return expvar.Handler()
+}
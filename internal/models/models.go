@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -12,6 +13,9 @@ type Example struct {
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// ExternalKey — ключ записи во внешней системе; по нему импорт делает
+	// upsert. Пустой у записей, созданных через API.
+	ExternalKey string `json:"external_key,omitempty"`
 }
 
 type ExampleRequest struct {
@@ -21,10 +25,84 @@ type ExampleRequest struct {
 	IsActive    bool    `json:"is_active" example:"true"`
 }
 
+// ExampleFilter — условия выборки для списка и экспорта. Nil-поля не
+// ограничивают выборку; Limit = 0 означает «без ограничения» (только экспорт).
+type ExampleFilter struct {
+	IsActive      *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+// ExampleStatsQuery — параметры статистики по записям.
+type ExampleStatsQuery struct {
+	// Filter — условия как у списка; Limit и Offset не учитываются.
+	Filter ExampleFilter
+	// Bucket — интервал группировки по created_at: hour, day, week, month;
+	// пусто — без группировки.
+	Bucket string
+	// Timezone — часовой пояс IANA, в котором считаются границы интервалов.
+	Timezone string
+}
+
+// ValueStats — агрегаты по полю value. Avg, Min и Max — null, если записей нет.
+type ValueStats struct {
+	Count int64    `json:"count" example:"42"`
+	Sum   float64  `json:"sum" example:"1050.5"`
+	Avg   *float64 `json:"avg" example:"25.01"`
+	Min   *float64 `json:"min" example:"0"`
+	Max   *float64 `json:"max" example:"100"`
+}
+
+type ActiveStats struct {
+	IsActive bool `json:"is_active" example:"true"`
+	ValueStats
+}
+
+type BucketStats struct {
+	// Start — начало интервала в часовом поясе запроса.
+	Start time.Time `json:"start"`
+	ValueStats
+}
+
+type ExampleStats struct {
+	Total    ValueStats    `json:"total"`
+	ByActive []ActiveStats `json:"by_active"`
+	Bucket   string        `json:"bucket,omitempty" example:"day"`
+	Timezone string        `json:"timezone,omitempty" example:"Europe/Moscow"`
+	// Buckets — интервалы, в которых есть записи, по возрастанию.
+	Buckets []BucketStats `json:"buckets,omitempty"`
+}
+
 type ExampleResponse struct {
 	Data []Example `json:"data"`
 }
 
+// ExampleSearchQuery — параметры поиска по name и description.
+type ExampleSearchQuery struct {
+	// Filter — условия как у списка.
+	Filter ExampleFilter
+	// Query — поисковая строка в синтаксисе websearch_to_tsquery.
+	Query string
+	// Mode — fulltext или fuzzy (по триграммам, с опечатками).
+	Mode string
+}
+
+// ExampleSearchResult — найденная запись с релевантностью и фрагментами,
+// в которых совпадения обрамлены <mark>...</mark>. Остальной текст
+// экранирован для HTML: <mark> — единственная разметка во фрагментах.
+type ExampleSearchResult struct {
+	Example
+	Rank          float64 `json:"rank" example:"0.61"`
+	NameHighlight string  `json:"name_highlight" example:"<mark>Example</mark> Name"`
+	Snippet       string  `json:"snippet" example:"<mark>Example</mark> description"`
+}
+
+type ExampleSearchResponse struct {
+	Data []ExampleSearchResult `json:"data"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error" example:"Invalid input data"`
 }
@@ -32,3 +110,339 @@ type ErrorResponse struct {
 type MessageResponse struct {
 	Message string `json:"message" example:"Operation completed successfully"`
 }
+
+type LogLevelRequest struct {
+	Level string `json:"level" example:"debug"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level" example:"info"`
+}
+
+// ScheduledTask — периодическая задача планировщика и её последний запуск.
+type ScheduledTask struct {
+	Name     string            `json:"name" example:"idempotency-cleanup"`
+	Schedule string            `json:"schedule" example:"0 * * * *"`
+	NextRun  *time.Time        `json:"next_run,omitempty"`
+	LastRun  *ScheduledTaskRun `json:"last_run,omitempty"`
+}
+
+// ScheduledTaskRun — запуск периодической задачи на любой из реплик.
+type ScheduledTaskRun struct {
+	// Tick — момент расписания, к которому относится запуск.
+	Tick       time.Time  `json:"tick"`
+	Status     string     `json:"status" example:"succeeded"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type SchedulerStatusResponse struct {
+	Tasks []ScheduledTask `json:"tasks"`
+}
+
+type ComponentHealth struct {
+	Name      string  `json:"name" example:"database"`
+	Status    string  `json:"status" example:"up"`
+	Critical  bool    `json:"critical" example:"true"`
+	LatencyMs float64 `json:"latency_ms" example:"1.25"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status     string            `json:"status" example:"up"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// BatchOperation — одна операция пакетного запроса. Для create нужен Data,
+// для update — ID и Data, для delete — только ID.
+type BatchOperation struct {
+	Op   string          `json:"op" example:"create" enums:"create,update,delete"`
+	ID   int             `json:"id,omitempty" example:"1"`
+	Data *ExampleRequest `json:"data,omitempty"`
+}
+
+type BatchRequest struct {
+	// Mode: atomic — всё или ничего; best_effort — применяются все валидные операции.
+	Mode       string           `json:"mode" example:"atomic" enums:"atomic,best_effort"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchItemResult struct {
+	Index   int      `json:"index" example:"0"`
+	Op      string   `json:"op" example:"create"`
+	Status  int      `json:"status" example:"201"`
+	Example *Example `json:"example,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	// Err — исходная ошибка сервиса; HTTP-слой превращает её в Status и Error.
+	Err error `json:"-"`
+}
+
+type BatchResponse struct {
+	Mode      string            `json:"mode" example:"atomic"`
+	Applied   bool              `json:"applied" example:"true"`
+	Succeeded int               `json:"succeeded" example:"2"`
+	Failed    int               `json:"failed" example:"0"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// ImportRow — одна строка импорта: поля ExampleRequest плюс внешний ключ,
+// по которому запись создаётся или обновляется.
+type ImportRow struct {
+	ExternalKey string `json:"external_key" example:"crm-42"`
+	ExampleRequest
+}
+
+// ImportOptions управляет импортом. ChunkSize = 0 — размер порции по умолчанию.
+type ImportOptions struct {
+	Format    string
+	DryRun    bool
+	ChunkSize int
+	// Progress, если задан, вызывается после каждой порции с числом
+	// обработанных строк.
+	Progress func(processed int)
+}
+
+type ImportLineError struct {
+	// Line — номер строки во входном файле (с единицы; для CSV заголовок — строка 1).
+	Line        int    `json:"line" example:"3"`
+	ExternalKey string `json:"external_key,omitempty" example:"crm-42"`
+	Error       string `json:"error" example:"name is required"`
+}
+
+type ImportReport struct {
+	DryRun  bool `json:"dry_run" example:"false"`
+	Total   int  `json:"total" example:"3"`
+	Created int  `json:"created" example:"1"`
+	Updated int  `json:"updated" example:"1"`
+	// Valid — строки, прошедшие проверку (в dry_run — вместо Created/Updated).
+	Valid  int               `json:"valid" example:"2"`
+	Failed int               `json:"failed" example:"1"`
+	Errors []ImportLineError `json:"errors"`
+	// ErrorsTruncated — ошибок больше, чем помещается в отчёт.
+	ErrorsTruncated bool `json:"errors_truncated,omitempty"`
+}
+
+// ExampleEvent — событие журнала изменений (поток /examples/stream). ID
+// растёт в порядке commit; Example — запись после изменения, для deleted —
+// до удаления.
+type ExampleEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	ExampleID int       `json:"example_id"`
+	Example   *Example  `json:"example"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WSRequest — сообщение клиента в /api/v1/ws. Type: subscribe, unsubscribe
+// или ping. Подписка задаётся списком ExampleIDs или фильтром Filter; ID
+// подписки выбирает клиент.
+type WSRequest struct {
+	Type       string              `json:"type" example:"subscribe"`
+	ID         string              `json:"id,omitempty" example:"sub-1"`
+	ExampleIDs []int               `json:"example_ids,omitempty"`
+	Filter     *SubscriptionFilter `json:"filter,omitempty"`
+}
+
+// SubscriptionFilter отбирает события по состоянию записи после изменения.
+// Nil-поля не ограничивают выборку.
+type SubscriptionFilter struct {
+	IsActive      *bool      `json:"is_active,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// WSMessage — сообщение сервера в /api/v1/ws. Type: welcome, subscribed,
+// unsubscribed, event, error или pong. Для event Subscriptions перечисляет
+// подписки клиента, под которые подошло событие.
+type WSMessage struct {
+	Type          string        `json:"type"`
+	ID            string        `json:"id,omitempty"`
+	Principal     string        `json:"principal,omitempty"`
+	Subscriptions []string      `json:"subscriptions,omitempty"`
+	Event         *ExampleEvent `json:"event,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// ExampleDeleted — payload сообщения outbox об удалении записи.
+type ExampleDeleted struct {
+	ID int `json:"id"`
+}
+
+// OutboxMessage — сообщение для внешних систем из таблицы outbox. Пишется в
+// одной транзакции с изменением данных; сообщения одного агрегата
+// (AggregateType + AggregateID) публикуются по порядку ID. Attempts —
+// номер текущей попытки публикации.
+type OutboxMessage struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int             `json:"-"`
+}
+
+// WebhookSubscription — подписка партнёра на события outbox. Secret
+// используется для подписи запросов и в ответах API не возвращается.
+type WebhookSubscription struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"-"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	// ConsecutiveFailures — неудачные попытки подряд; при достижении порога
+	// подписка выключается (DisabledAt).
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// WebhookRequest — создание или изменение подписки. EventTypes — типы
+// событий outbox или "*" для всех. При изменении пустой Secret оставляет
+// прежний, Active = true включает подписку и сбрасывает счётчик ошибок.
+type WebhookRequest struct {
+	URL        string   `json:"url" example:"https://partner.example.com/hooks"`
+	Secret     string   `json:"secret" example:"whsec_3q2+7w=="`
+	EventTypes []string `json:"event_types" example:"example.created,example.updated"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+type WebhookResponse struct {
+	Data []WebhookSubscription `json:"data"`
+}
+
+// WebhookAttempt — одна попытка доставки из журнала. StatusCode == 0 —
+// ответа не было (ошибка соединения или таймаут).
+type WebhookAttempt struct {
+	ID         int64     `json:"id"`
+	DeliveryID int64     `json:"delivery_id"`
+	MessageID  int64     `json:"message_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookAttemptResponse struct {
+	Data []WebhookAttempt `json:"data"`
+}
+
+// AuditEntry — запись журнала аудита: кто (Actor), что (Action над
+// EntityType/EntityID) и когда изменил. Before — состояние до изменения
+// (null для create), After — после (null для delete).
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id,omitempty"`
+	ClientIP   string          `json:"client_ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditFilter — фильтр журнала аудита; пустые поля не ограничивают выборку.
+type AuditFilter struct {
+	Actor         string
+	EntityType    string
+	EntityID      string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+type AuditResponse struct {
+	Data []AuditEntry `json:"data"`
+}
+
+// ExampleRevision — версия записи после изменения Op (created, updated или
+// deleted; для deleted Example — состояние до удаления).
+type ExampleRevision struct {
+	ExampleID int       `json:"example_id"`
+	Revision  int       `json:"revision"`
+	Op        string    `json:"op"`
+	Example   Example   `json:"example"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExampleRevisionResponse struct {
+	Data []ExampleRevision `json:"data"`
+}
+
+// FieldChange — поле, значение которого различается между версиями.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// RevisionDiff — различия между версиями From и To одной записи.
+type RevisionDiff struct {
+	ExampleID int           `json:"example_id"`
+	From      int           `json:"from"`
+	To        int           `json:"to"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// Operation — долгая асинхронная операция (импорт, выгрузка, очистка). Клиент получает
+// её в ответ 202 Accepted и опрашивает GET /api/v1/operations/{id}.
+type Operation struct {
+	ID     int64  `json:"id" example:"1"`
+	Kind   string `json:"kind" example:"examples.import"`
+	Status string `json:"status" example:"running"`
+	// Progress — сколько единиц работы сделано (строк импорта и выгрузки,
+	// удалённых записей); общий объём заранее неизвестен.
+	Progress int64 `json:"progress" example:"1500"`
+	// Result — результат операции (для отменённой — частичный).
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// CancelRequested — отмена запрошена, но операция ещё не остановилась.
+	CancelRequested bool `json:"cancel_requested"`
+	// Actor — кто запустил операцию.
+	Actor      string     `json:"actor" example:"anonymous"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Params и Input — параметры и входные данные запуска; RequestID и
+	// ClientIP — запрос запуска. Наружу не отдаются.
+	Params    json.RawMessage `json:"-"`
+	Input     []byte          `json:"-"`
+	RequestID string          `json:"-"`
+	ClientIP  string          `json:"-"`
+}
+
+// ExportResult — результат операции examples.export. Сам файл отдаёт
+// GET /api/v1/operations/{id}/output.
+type ExportResult struct {
+	Format string `json:"format" example:"csv"`
+	Rows   int    `json:"rows" example:"1500"`
+	// Size — размер файла, сжатого gzip, в байтах.
+	Size int `json:"size" example:"48213"`
+}
+
+// OperationOutput — файл, созданный операцией, и его формат. Data сжат gzip.
+type OperationOutput struct {
+	Format string
+	Data   []byte
+}
+
+// PurgeRequest — условия массового удаления записей; нужно хотя бы одно.
+type PurgeRequest struct {
+	IsActive      *bool      `json:"is_active,omitempty" example:"false"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty" example:"2026-01-01T00:00:00Z"`
+}
+
+// PurgeResult — результат операции examples.purge.
+type PurgeResult struct {
+	Deleted int `json:"deleted" example:"1500"`
+}
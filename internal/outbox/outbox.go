@@ -0,0 +1,152 @@
+// Package outbox публикует сообщения transactional outbox во внешние
+// системы. Сервис пишет сообщение в таблицу outbox в той же транзакции, что и
+// изменение данных (service.TxStorage.AppendOutbox); Relay разбирает очередь
+// и отдаёт сообщения Publisher. Доставка — at-least-once: после сбоя между
+// публикацией и удалением из очереди сообщение будет опубликовано повторно,
+// поэтому получатели должны дедуплицировать его по ID.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-service-template/internal/background"
+	"go-service-template/internal/config"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+)
+
+// Publisher доставляет сообщение во внешнюю систему. Ошибка означает, что
+// доставка не подтверждена и сообщение нужно повторить. Publish вызывается
+// конкурентно для сообщений разных агрегатов.
+type Publisher interface {
+	Publish(ctx context.Context, msg models.OutboxMessage) error
+}
+
+// Store — очередь сообщений outbox.
+type Store interface {
+	// Claim захватывает на lease до limit сообщений, готовых к отправке, и
+	// увеличивает их Attempts. От каждого агрегата берётся только самое
+	// раннее неотправленное сообщение: следующие ждут, пока оно не будет
+	// опубликовано или переведено в dead letter.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	// Delete удаляет опубликованное сообщение.
+	Delete(ctx context.Context, id int64) error
+	// Retry снимает захват и откладывает следующую попытку на delay.
+	Retry(ctx context.Context, id int64, attempt int, delay time.Duration, lastErr string) error
+	// DeadLetter помечает сообщение как недоставленное; оно остаётся в
+	// таблице для разбора, но больше не публикуется и не задерживает
+	// следующие сообщения агрегата.
+	DeadLetter(ctx context.Context, id int64, attempt int, lastErr string) error
+}
+
+// Relay переносит сообщения из Store в Publisher.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	cfg       config.OutboxConfig
+	logger    *slog.Logger
+}
+
+func NewRelay(store Store, publisher Publisher, cfg config.OutboxConfig, logger *slog.Logger) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run разбирает очередь, пока ctx не отменён. Полная пачка означает, что в
+// очереди, скорее всего, есть ещё сообщения, и следующая берётся сразу; иначе
+// relay ждёт PollInterval.
+func (r *Relay) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		wait := r.cfg.PollInterval
+		if r.relayBatch(ctx) == r.cfg.BatchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// relayBatch публикует одну пачку и возвращает её размер. Сообщения пачки
+// относятся к разным агрегатам, поэтому публикуются параллельно.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	batch, err := r.store.Claim(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Failed to claim outbox messages", slog.String("error", err.Error()))
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range batch {
+		wg.Go(func() {
+			r.deliver(ctx, msg)
+		})
+	}
+	wg.Wait()
+
+	return len(batch)
+}
+
+func (r *Relay) deliver(ctx context.Context, msg models.OutboxMessage) {
+	publishCtx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+	publishErr := r.publisher.Publish(publishCtx, msg)
+	cancel()
+
+	storeCtx, cancel := background.StoreContext(ctx)
+	defer cancel()
+
+	logger := r.logger.With(
+		slog.Int64("id", msg.ID),
+		slog.String("event_type", msg.EventType),
+		slog.String("aggregate_id", msg.AggregateID),
+		slog.Int("attempt", msg.Attempts),
+	)
+
+	if publishErr == nil {
+		metrics.OutboxPublished.Add(1)
+		if err := r.store.Delete(storeCtx, msg.ID); err != nil {
+			logger.Error("Failed to delete published outbox message", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	metrics.OutboxFailures.Add(1)
+	if msg.Attempts >= r.cfg.MaxAttempts {
+		metrics.OutboxDeadLettered.Add(1)
+		logger.Error("Outbox message dead-lettered", slog.String("error", publishErr.Error()))
+		if err := r.store.DeadLetter(storeCtx, msg.ID, msg.Attempts, publishErr.Error()); err != nil {
+			logger.Error("Failed to dead-letter outbox message", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	delay := r.backoff(msg.Attempts)
+	logger.Warn("Failed to publish outbox message, retrying",
+		slog.Duration("delay", delay),
+		slog.String("error", publishErr.Error()),
+	)
+	if err := r.store.Retry(storeCtx, msg.ID, msg.Attempts, delay, publishErr.Error()); err != nil {
+		logger.Error("Failed to reschedule outbox message", slog.String("error", err.Error()))
+	}
+}
+
+// backoff — background.Backoff по настройкам повторов relay, без jitter.
+func (r *Relay) backoff(attempt int) time.Duration {
+	return background.Backoff(r.cfg.RetryBaseDelay, r.cfg.RetryMaxDelay, attempt)
+}
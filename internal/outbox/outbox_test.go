@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/models"
+)
+
+// fakeStore повторяет семантику очереди: от агрегата выдаётся только самое
+// раннее не отправленное и не мёртвое сообщение. Задержки повтора не
+// соблюдаются — отложенное сообщение доступно в следующей пачке.
+type fakeStore struct {
+	mu       sync.Mutex
+	messages []models.OutboxMessage
+	claimed  map[int64]bool
+	dead     map[int64]string
+	delays   []time.Duration
+}
+
+func newFakeStore(aggregates ...string) *fakeStore {
+	s := &fakeStore{claimed: make(map[int64]bool), dead: make(map[int64]string)}
+	for i, aggregate := range aggregates {
+		s.messages = append(s.messages, models.OutboxMessage{ID: int64(i + 1), AggregateType: "example", AggregateID: aggregate})
+	}
+	return s
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _ time.Duration) ([]models.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var batch []models.OutboxMessage
+	for i := range s.messages {
+		msg := &s.messages[i]
+		if _, ok := s.dead[msg.ID]; ok || seen[msg.AggregateID] {
+			continue
+		}
+		seen[msg.AggregateID] = true
+		if s.claimed[msg.ID] || len(batch) == limit {
+			continue
+		}
+		s.claimed[msg.ID] = true
+		msg.Attempts++
+		batch = append(batch, *msg)
+	}
+	return batch, nil
+}
+
+func (s *fakeStore) Delete(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = slices.DeleteFunc(s.messages, func(msg models.OutboxMessage) bool { return msg.ID == id })
+	return nil
+}
+
+func (s *fakeStore) Retry(_ context.Context, id int64, _ int, delay time.Duration, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, id)
+	s.delays = append(s.delays, delay)
+	return nil
+}
+
+func (s *fakeStore) DeadLetter(_ context.Context, id int64, _ int, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, id)
+	s.dead[id] = lastErr
+	return nil
+}
+
+// fakePublisher запоминает опубликованные ID и отказывает сообщениям из fail.
+type fakePublisher struct {
+	mu        sync.Mutex
+	published []int64
+	fail      map[int64]int // сколько раз ещё отказать
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg models.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail[msg.ID] > 0 {
+		p.fail[msg.ID]--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, msg.ID)
+	return nil
+}
+
+func testRelay(store Store, publisher Publisher) *Relay {
+	cfg := config.OutboxConfig{
+		BatchSize:      10,
+		MaxAttempts:    3,
+		Lease:          time.Minute,
+		PublishTimeout: time.Second,
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  3 * time.Second,
+	}
+	return NewRelay(store, publisher, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestRelay_KeepsOrderPerAggregate(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore("a", "b", "a")
+	publisher := &fakePublisher{fail: map[int64]int{1: 1}}
+	relay := testRelay(store, publisher)
+
+	// Первая попытка сообщения 1 неудачна: 3 (тот же агрегат) ждёт, 2 уходит.
+	if n := relay.relayBatch(ctx); n != 2 {
+		t.Fatalf("expected 2 claimed messages, got %d", n)
+	}
+	if !slices.Equal(publisher.published, []int64{2}) {
+		t.Fatalf("expected only message 2 published, got %v", publisher.published)
+	}
+
+	relay.relayBatch(ctx)
+	relay.relayBatch(ctx)
+	if !slices.Equal(publisher.published, []int64{2, 1, 3}) {
+		t.Fatalf("expected order 2, 1, 3, got %v", publisher.published)
+	}
+	if len(store.messages) != 0 {
+		t.Fatalf("expected empty queue, got %+v", store.messages)
+	}
+}
+
+func TestRelay_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore("a", "a")
+	publisher := &fakePublisher{fail: map[int64]int{1: 100}}
+	relay := testRelay(store, publisher)
+
+	for range 3 {
+		relay.relayBatch(ctx)
+	}
+	if _, ok := store.dead[1]; !ok {
+		t.Fatalf("expected message 1 dead-lettered after 3 attempts")
+	}
+	if !slices.Equal(store.delays, []time.Duration{time.Second, 2 * time.Second}) {
+		t.Errorf("unexpected retry delays: %v", store.delays)
+	}
+
+	// Мёртвое сообщение не задерживает следующее сообщение агрегата.
+	relay.relayBatch(ctx)
+	if !slices.Equal(publisher.published, []int64{2}) {
+		t.Fatalf("expected message 2 published after dead letter, got %v", publisher.published)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := testRelay(nil, nil)
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 3 * time.Second},
+		{50, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.ndjson")
+	publisher, err := NewFilePublisher(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := range int64(2) {
+		msg := models.OutboxMessage{ID: id + 1, EventType: "example.created", Payload: json.RawMessage(`{"id":1}`)}
+		if err := publisher.Publish(context.Background(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := publisher.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var ids []int64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var msg models.OutboxMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, msg.ID)
+	}
+	if !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("expected messages 1 and 2, got %v", ids)
+	}
+}
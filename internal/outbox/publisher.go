@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go-service-template/internal/models"
+)
+
+// WriterPublisher пишет сообщения в поток по одному JSON на строку (NDJSON).
+// Предназначен для локальной разработки: вместо брокера сообщения видны в
+// stdout или в файле.
+type WriterPublisher struct {
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File
+}
+
+var _ Publisher = (*WriterPublisher)(nil)
+
+// NewStdoutPublisher пишет сообщения в stdout рядом с логами.
+func NewStdoutPublisher() *WriterPublisher {
+	return &WriterPublisher{w: os.Stdout}
+}
+
+// NewFilePublisher дописывает сообщения в файл path, создавая его при
+// необходимости. Каждое сообщение сбрасывается на диск до подтверждения.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open outbox file: %w", err)
+	}
+	return &WriterPublisher{w: file, file: file}, nil
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, msg models.OutboxMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.w.Write(data); err != nil {
+		return err
+	}
+	if p.file != nil {
+		return p.file.Sync()
+	}
+	return nil
+}
+
+// Close закрывает файл; для stdout ничего не делает.
+func (p *WriterPublisher) Close() error {
+	if p.file == nil {
+		return nil
+	}
+	return p.file.Close()
+}
+
+// Fanout публикует сообщение во все publishers по очереди. Сообщение
+// подтверждается, только если его приняли все; при повторе его снова
+// получат и те, кто уже принял (at-least-once).
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+type fanout []Publisher
+
+func (f fanout) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
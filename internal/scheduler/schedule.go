@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears ограничивает поиск следующего запуска: выражение вроде
+// «30 февраля» не совпадёт никогда.
+const maxSearchYears = 5
+
+// Schedule — разобранное cron-выражение. Поля — битовые маски допустимых
+// значений.
+type Schedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	// domAny и dowAny — поле задано как «*». Если ограничены оба дня
+	// (месяца и недели), подходит любой из них, как в классическом cron.
+	domAny, dowAny bool
+}
+
+// macros — сокращения для частых расписаний.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type fieldSpec struct {
+	name     string
+	min, max int
+}
+
+var fields = [5]fieldSpec{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse разбирает cron-выражение из пяти полей — минута, час, день месяца,
+// месяц, день недели (0 и 7 — воскресенье) — или макрос (@hourly, @daily,
+// @weekly, @monthly, @yearly). Поле — «*», число, диапазон «a-b», шаг
+// «*/n» или «a-b/n» либо их список через запятую.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := macros[spec]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron %q: expected %d fields, got %d", expr, len(fields), len(parts))
+	}
+
+	var masks [5]uint64
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		masks[i] = mask
+	}
+
+	// Воскресенье можно записать и как 7.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] = masks[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		expr:   expr,
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+func parseField(field string, spec fieldSpec) (uint64, error) {
+	var mask uint64
+	for item := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		lo, hi := spec.min, spec.max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseValue(from, spec); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(to, spec); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("%s range %q is reversed", spec.name, rangePart)
+				}
+			}
+		}
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("%s step %q must be a positive integer", spec.name, stepPart)
+			}
+			if rangePart != "*" && !strings.Contains(rangePart, "-") {
+				// «5/15» — с 5 до конца диапазона с шагом 15.
+				hi = spec.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+func parseValue(s string, spec fieldSpec) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s value %q is not a number", spec.name, s)
+	}
+	if v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("%s value %d is out of range %d-%d", spec.name, v, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+// String возвращает исходное выражение.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next возвращает ближайший момент запуска строго после t — с точностью до
+// минуты, в часовом поясе t. Нулевое время — выражение не совпадает ни с
+// одной датой.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			// Следующая подходящая минута в этом часе или начало следующего.
+			next := s.minute >> uint(t.Minute()+1) << uint(t.Minute()+1)
+			if next == 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(next)-t.Minute()) * time.Minute)
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
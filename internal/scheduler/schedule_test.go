@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 5m",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): expected error", expr)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Понедельник.
+	from := time.Date(2026, time.March, 16, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 16, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 16, 10, 30, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, time.March, 16, 10, 25, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, time.March, 16, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 16, 11, 0, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2026, time.March, 17, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, time.March, 16, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2026, time.March, 22, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 22, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		// День месяца и день недели ограничены оба — подходит любой.
+		{"0 0 1 * 3", time.Date(2026, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: expected %s, got %s", tt.expr, tt.want, got)
+		}
+	}
+
+	never, _ := Parse("0 0 30 2 *")
+	if got := never.Next(from); !got.IsZero() {
+		t.Errorf("expected zero time for February 30, got %s", got)
+	}
+}
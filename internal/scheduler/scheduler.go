@@ -0,0 +1,198 @@
+// Package scheduler запускает периодические задачи обслуживания по
+// cron-расписанию. Планировщик работает на каждой реплике, но каждый запуск
+// (tick) выполняет ровно одна: перед запуском она берёт advisory-блокировку
+// задачи в Postgres и отмечает tick как выполненный. Последний запуск и его
+// итог хранятся в БД и видны на admin-поверхности.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-service-template/internal/background"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+)
+
+// Статусы запуска.
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Task — периодическая задача.
+type Task struct {
+	// Name — уникальное имя; по нему берётся блокировка и хранится
+	// последний запуск.
+	Name string
+	// Schedule — cron-выражение (см. Parse), время — UTC.
+	Schedule string
+	// Run выполняет задачу. ctx отменяется при остановке сервиса.
+	Run func(ctx context.Context) error
+}
+
+// Store хранит последние запуски задач и выбирает реплику для запуска.
+type Store interface {
+	// Acquire берёт advisory-блокировку задачи name и отмечает запуск tick.
+	// ok = false — блокировку держит другая реплика (предыдущий запуск ещё
+	// идёт) или tick уже выполнен. release снимает блокировку.
+	Acquire(ctx context.Context, name string, tick time.Time) (release func(), ok bool, err error)
+	// Finish записывает итог запуска tick.
+	Finish(ctx context.Context, name string, tick time.Time, status, errMsg string) error
+	// LastRuns возвращает последний запуск каждой задачи по имени.
+	LastRuns(ctx context.Context) (map[string]models.ScheduledTaskRun, error)
+}
+
+type task struct {
+	Task
+	schedule *Schedule
+}
+
+// Scheduler запускает зарегистрированные задачи по расписанию.
+type Scheduler struct {
+	store  Store
+	logger *slog.Logger
+	tasks  []*task
+	// now — текущее время; в тестах подменяется.
+	now func() time.Time
+}
+
+func New(store Store, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		store:  store,
+		logger: logger,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Add регистрирует задачу. Вызывайте до Run.
+func (s *Scheduler) Add(t Task) error {
+	if t.Name == "" || t.Run == nil {
+		return errors.New("scheduler: task name and func are required")
+	}
+	for _, existing := range s.tasks {
+		if existing.Name == t.Name {
+			return fmt.Errorf("scheduler: task %q already registered", t.Name)
+		}
+	}
+	schedule, err := Parse(t.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: task %q: %w", t.Name, err)
+	}
+	s.tasks = append(s.tasks, &task{Task: t, schedule: schedule})
+	return nil
+}
+
+// Run запускает задачи по расписанию, пока ctx не отменён, и ждёт
+// выполняемые запуски.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.tasks) == 0 {
+		s.logger.Info("No scheduled tasks registered, scheduler is idle")
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range s.tasks {
+		wg.Go(func() {
+			s.loop(ctx, t)
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+// loop ждёт очередной tick задачи и запускает её. Tick, пропущенные, пока
+// шёл долгий запуск, не догоняются.
+func (s *Scheduler) loop(ctx context.Context, t *task) {
+	for {
+		tick := t.schedule.Next(s.now())
+		if tick.IsZero() {
+			s.logger.Warn("Scheduled task never fires", slog.String("task", t.Name), slog.String("schedule", t.Schedule))
+			return
+		}
+
+		timer := time.NewTimer(tick.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runTick(ctx, t, tick)
+	}
+}
+
+func (s *Scheduler) runTick(ctx context.Context, t *task, tick time.Time) {
+	logger := s.logger.With(slog.String("task", t.Name), slog.Time("tick", tick))
+
+	release, ok, err := s.store.Acquire(ctx, t.Name, tick)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to acquire scheduled task", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if !ok {
+		metrics.ScheduledRuns.Add("skipped", 1)
+		logger.Debug("Scheduled task runs on another replica")
+		return
+	}
+	defer release()
+
+	started := time.Now()
+	runErr := runTask(ctx, t.Run)
+
+	status, errMsg := StatusSucceeded, ""
+	if runErr != nil {
+		status, errMsg = StatusFailed, background.ErrorText(runErr)
+		logger.Error("Scheduled task failed", slog.String("error", runErr.Error()))
+	} else {
+		logger.Info("Scheduled task finished", slog.Duration("took", time.Since(started)))
+	}
+	metrics.ScheduledRuns.Add(status, 1)
+
+	storeCtx, cancel := background.StoreContext(ctx)
+	defer cancel()
+	if err := s.store.Finish(storeCtx, t.Name, tick, status, errMsg); err != nil {
+		logger.Error("Failed to record scheduled task run", slog.String("error", err.Error()))
+	}
+}
+
+// runTask вызывает задачу, превращая панику в ошибку запуска.
+func runTask(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return fn(ctx)
+}
+
+// Status возвращает состояние зарегистрированных задач: расписание,
+// ближайший запуск и последний запуск на любой реплике.
+func (s *Scheduler) Status(ctx context.Context) ([]models.ScheduledTask, error) {
+	runs, err := s.store.LastRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	statuses := make([]models.ScheduledTask, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		status := models.ScheduledTask{Name: t.Name, Schedule: t.Schedule}
+		if next := t.schedule.Next(now); !next.IsZero() {
+			status.NextRun = &next
+		}
+		if run, ok := runs[t.Name]; ok {
+			status.LastRun = &run
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
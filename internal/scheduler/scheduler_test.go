@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// fakeStore выдаёт каждый tick один раз, как таблица scheduled_tasks.
+type fakeStore struct {
+	mu       sync.Mutex
+	runs     map[string]models.ScheduledTaskRun
+	locked   map[string]bool
+	released int
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		runs:   make(map[string]models.ScheduledTaskRun),
+		locked: make(map[string]bool),
+	}
+}
+
+func (s *fakeStore) Acquire(_ context.Context, name string, tick time.Time) (func(), bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked[name] {
+		return nil, false, nil
+	}
+	if run, ok := s.runs[name]; ok && !run.Tick.Before(tick) {
+		return nil, false, nil
+	}
+	s.locked[name] = true
+	s.runs[name] = models.ScheduledTaskRun{Tick: tick, Status: StatusRunning, StartedAt: time.Now()}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.locked[name] = false
+		s.released++
+	}, true, nil
+}
+
+func (s *fakeStore) Finish(_ context.Context, name string, tick time.Time, status, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	finished := time.Now()
+	run := s.runs[name]
+	run.Status, run.Error, run.FinishedAt = status, errMsg, &finished
+	s.runs[name] = run
+	return nil
+}
+
+func (s *fakeStore) LastRuns(context.Context) (map[string]models.ScheduledTaskRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make(map[string]models.ScheduledTaskRun, len(s.runs))
+	for name, run := range s.runs {
+		runs[name] = run
+	}
+	return runs, nil
+}
+
+func newTestScheduler(store Store) *Scheduler {
+	return New(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestScheduler_RunTick(t *testing.T) {
+	store := newFakeStore()
+	s := newTestScheduler(store)
+	calls := 0
+	if err := s.Add(Task{Name: "cleanup", Schedule: "@hourly", Run: func(context.Context) error {
+		calls++
+		if calls == 2 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := s.tasks[0]
+	tick := time.Date(2026, time.March, 16, 11, 0, 0, 0, time.UTC)
+
+	s.runTick(context.Background(), task, tick)
+	// Другая реплика опоздала к тому же tick.
+	s.runTick(context.Background(), task, tick)
+	if calls != 1 || store.runs["cleanup"].Status != StatusSucceeded || store.released != 1 {
+		t.Fatalf("expected one successful run, got %d calls, run %+v", calls, store.runs["cleanup"])
+	}
+
+	s.runTick(context.Background(), task, tick.Add(time.Hour))
+	if run := store.runs["cleanup"]; run.Status != StatusFailed || run.Error != "database unavailable" {
+		t.Fatalf("expected failed run recorded, got %+v", run)
+	}
+}
+
+func TestScheduler_SkipsWhileLocked(t *testing.T) {
+	store := newFakeStore()
+	store.locked["cleanup"] = true
+	s := newTestScheduler(store)
+	called := false
+	_ = s.Add(Task{Name: "cleanup", Schedule: "@hourly", Run: func(context.Context) error {
+		called = true
+		return nil
+	}})
+
+	s.runTick(context.Background(), s.tasks[0], time.Now())
+	if called {
+		t.Fatal("expected task not to run while another replica holds the lock")
+	}
+}
+
+func TestScheduler_RecoversPanic(t *testing.T) {
+	store := newFakeStore()
+	s := newTestScheduler(store)
+	_ = s.Add(Task{Name: "boom", Schedule: "@daily", Run: func(context.Context) error {
+		panic("boom")
+	}})
+
+	s.runTick(context.Background(), s.tasks[0], time.Now())
+	if run := store.runs["boom"]; run.Status != StatusFailed || run.Error != "panic: boom" {
+		t.Fatalf("expected panic recorded as failure, got %+v", run)
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := newTestScheduler(newFakeStore())
+	noop := func(context.Context) error { return nil }
+
+	if err := s.Add(Task{Name: "a", Schedule: "* * *", Run: noop}); err == nil {
+		t.Error("expected error for invalid schedule")
+	}
+	if err := s.Add(Task{Name: "a", Schedule: "@daily", Run: noop}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Add(Task{Name: "a", Schedule: "@hourly", Run: noop}); err == nil {
+		t.Error("expected error for duplicate task name")
+	}
+}
+
+func TestScheduler_Status(t *testing.T) {
+	store := newFakeStore()
+	tick := time.Date(2026, time.March, 16, 10, 0, 0, 0, time.UTC)
+	store.runs["cleanup"] = models.ScheduledTaskRun{Tick: tick, Status: StatusSucceeded}
+	s := newTestScheduler(store)
+	s.now = func() time.Time { return tick.Add(17 * time.Minute) }
+	noop := func(context.Context) error { return nil }
+	_ = s.Add(Task{Name: "cleanup", Schedule: "0 * * * *", Run: noop})
+	_ = s.Add(Task{Name: "report", Schedule: "0 0 * * *", Run: noop})
+
+	tasks, err := s.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %+v", tasks)
+	}
+	if tasks[0].LastRun == nil || !tasks[0].LastRun.Tick.Equal(tick) || !tasks[0].NextRun.Equal(tick.Add(time.Hour)) {
+		t.Errorf("unexpected cleanup status %+v", tasks[0])
+	}
+	if tasks[1].LastRun != nil {
+		t.Errorf("expected report never run, got %+v", tasks[1].LastRun)
+	}
+}
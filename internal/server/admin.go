@@ -0,0 +1,90 @@
+package server
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// setupAdminRoutes собирает служебное приложение: пробы, метрики, дамп
+// конфигурации, управление уровнем логов и (опционально) pprof. Оно слушает отдельный host:port
+// (по умолчанию 127.0.0.1) и не должно быть доступно извне кластера.
+func (s *Server) setupAdminRoutes() {
+	s.admin = fiber.New(fiber.Config{
+		ReadTimeout:           s.config.Server.ReadTimeout,
+		WriteTimeout:          s.config.Server.WriteTimeout,
+		IdleTimeout:           60 * time.Second,
+		DisableStartupMessage: true,
+	})
+
+	s.admin.Use(recover.New())
+
+	s.admin.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
+
+	debug := s.admin.Group("/debug", s.adminAuthMiddleware())
+	debug.Get("/config", s.configDump)
+	if s.logLevel != nil {
+		debug.Get("/loglevel", s.getLogLevel)
+		debug.Put("/loglevel", s.setLogLevel)
+	}
+	if s.scheduler != nil {
+		debug.Get("/scheduler", s.schedulerStatus)
+	}
+	if s.config.Admin.PprofEnabled {
+		s.setupPprofRoutes(debug)
+	}
+}
+
+// configDump отдаёт действующую конфигурацию без секретов.
+func (s *Server) configDump(c *fiber.Ctx) error {
+	return c.JSON(s.config.Redacted())
+}
+
+// schedulerStatus отдаёт расписание периодических задач и их последние запуски.
+func (s *Server) schedulerStatus(c *fiber.Ctx) error {
+	tasks, err := s.scheduler.Status(c.UserContext())
+	if err != nil {
+		s.logger.Error("Failed to get scheduler status", slog.String("error", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error: "Failed to get scheduler status",
+		})
+	}
+	return c.JSON(models.SchedulerStatusResponse{Tasks: tasks})
+}
+
+func (s *Server) getLogLevel(c *fiber.Ctx) error {
+	return c.JSON(models.LogLevelResponse{Level: strings.ToLower(s.logLevel.Level().String())})
+}
+
+// setLogLevel меняет уровень логов на лету: {"level":"debug|info|warn|error"}.
+func (s *Server) setLogLevel(c *fiber.Ctx) error {
+	var req models.LogLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid log level: " + req.Level,
+		})
+	}
+
+	previous := s.logLevel.Level()
+	s.logLevel.Set(level)
+	s.logger.Warn("Log level changed",
+		slog.String("from", previous.String()),
+		slog.String("to", level.String()),
+	)
+
+	return c.JSON(models.LogLevelResponse{Level: strings.ToLower(level.String())})
+}
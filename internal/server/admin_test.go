@@ -0,0 +1,229 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/models"
+	"go-service-template/internal/scheduler"
+	"go-service-template/internal/service"
+)
+
+func newTestAdminServer(level *slog.LevelVar) *Server {
+	return newTestAdminServerWithConfig(level, config.AdminConfig{Host: "127.0.0.1", Port: 9090})
+}
+
+func newTestAdminServerWithConfig(level *slog.LevelVar, adminCfg config.AdminConfig) *Server {
+	return newTestServer(&service.Services{}, WithLogLevel(level), withTestConfig(func(cfg *config.Config) {
+		cfg.Database.Password = "secret"
+		cfg.Server.Port = 8080
+		cfg.Admin = adminCfg
+	}))
+}
+
+func doAdminRequest(s *Server, method, path string, body any) *http.Response {
+	return doAdminRequestWithToken(s, method, path, body, "")
+}
+
+func doAdminRequestWithToken(s *Server, method, path string, body any, token string) *http.Response {
+	var reqBody io.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		reqBody = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, _ := s.admin.Test(req, -1)
+	return resp
+}
+
+func TestAdminListener_SeparatesProbesFromAPI(t *testing.T) {
+	s := newTestAdminServer(new(slog.LevelVar))
+
+	for _, path := range []string{"/livez", "/readyz", "/health"} {
+		if resp := doRequest(s, http.MethodGet, path, nil); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("public %s: expected 404, got %d", path, resp.StatusCode)
+		}
+		if resp := doAdminRequest(s, http.MethodGet, path, nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("admin %s: expected 200, got %d", path, resp.StatusCode)
+		}
+	}
+
+	if resp := doAdminRequest(s, http.MethodGet, "/api/v1/examples", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("admin API: expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminListener_PublicHostWithoutToken(t *testing.T) {
+	s := newTestAdminServerWithConfig(new(slog.LevelVar), config.AdminConfig{Host: "0.0.0.0", Port: 9090})
+
+	// Пробы и метрики доступны kubelet и сборщику без токена.
+	for _, path := range []string{"/livez", "/readyz", "/startupz", "/metrics"} {
+		if resp := doAdminRequest(s, http.MethodGet, path, nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", path, resp.StatusCode)
+		}
+	}
+	if resp := doAdminRequest(s, http.MethodPut, "/debug/loglevel", map[string]string{"level": "debug"}); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("/debug/loglevel: expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminMetrics(t *testing.T) {
+	s := newTestAdminServer(new(slog.LevelVar))
+
+	resp := doAdminRequest(s, http.MethodGet, "/metrics", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeJSON[map[string]any](t, resp)
+	if _, ok := body["http_requests_total"]; !ok {
+		t.Fatal("expected http_requests_total metric")
+	}
+}
+
+func TestAdminConfigDump_RedactsSecrets(t *testing.T) {
+	s := newTestAdminServer(new(slog.LevelVar))
+
+	resp := doAdminRequest(s, http.MethodGet, "/debug/config", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeJSON[config.Config](t, resp)
+	if body.Database.Password != "***" {
+		t.Fatalf("expected redacted password, got %q", body.Database.Password)
+	}
+}
+
+// schedulerStore отдаёт заданные последние запуски.
+type schedulerStore struct {
+	runs map[string]models.ScheduledTaskRun
+}
+
+func (s *schedulerStore) Acquire(context.Context, string, time.Time) (func(), bool, error) {
+	return nil, false, nil
+}
+
+func (s *schedulerStore) Finish(context.Context, string, time.Time, string, string) error { return nil }
+
+func (s *schedulerStore) LastRuns(context.Context) (map[string]models.ScheduledTaskRun, error) {
+	return s.runs, nil
+}
+
+func TestAdminScheduler(t *testing.T) {
+	tick := time.Date(2026, time.March, 16, 10, 0, 0, 0, time.UTC)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sched := scheduler.New(&schedulerStore{runs: map[string]models.ScheduledTaskRun{
+		"cleanup": {Tick: tick, Status: scheduler.StatusFailed, Error: "timeout"},
+	}}, logger)
+	_ = sched.Add(scheduler.Task{Name: "cleanup", Schedule: "@hourly", Run: func(context.Context) error { return nil }})
+
+	s := newTestServer(&service.Services{}, WithScheduler(sched), withTestConfig(func(cfg *config.Config) {
+		cfg.Admin = config.AdminConfig{Host: "127.0.0.1", Port: 9090}
+	}))
+
+	resp := doAdminRequest(s, http.MethodGet, "/debug/scheduler", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeJSON[models.SchedulerStatusResponse](t, resp)
+	if len(body.Tasks) != 1 || body.Tasks[0].NextRun == nil || body.Tasks[0].LastRun == nil ||
+		body.Tasks[0].LastRun.Error != "timeout" {
+		t.Fatalf("unexpected scheduler status %+v", body.Tasks)
+	}
+}
+
+func TestAdminLogLevel(t *testing.T) {
+	level := new(slog.LevelVar)
+	s := newTestAdminServer(level)
+
+	t.Run("get", func(t *testing.T) {
+		resp := doAdminRequest(s, http.MethodGet, "/debug/loglevel", nil)
+		body := decodeJSON[models.LogLevelResponse](t, resp)
+		if body.Level != "info" {
+			t.Fatalf("expected info, got %q", body.Level)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		resp := doAdminRequest(s, http.MethodPut, "/debug/loglevel", models.LogLevelRequest{Level: "debug"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if level.Level() != slog.LevelDebug {
+			t.Fatalf("expected level debug, got %v", level.Level())
+		}
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		resp := doAdminRequest(s, http.MethodPut, "/debug/loglevel", models.LogLevelRequest{Level: "loud"})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestAdminPprof(t *testing.T) {
+	pprofCfg := config.AdminConfig{
+		Host:               "127.0.0.1",
+		Port:               9090,
+		Token:              "admin-token",
+		PprofEnabled:       true,
+		ProfileMaxDuration: 5 * time.Second,
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		s := newTestAdminServer(new(slog.LevelVar))
+
+		resp := doAdminRequest(s, http.MethodGet, "/debug/pprof/", nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("requires admin token", func(t *testing.T) {
+		s := newTestAdminServerWithConfig(new(slog.LevelVar), pprofCfg)
+
+		if resp := doAdminRequest(s, http.MethodGet, "/debug/pprof/", nil); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 without token, got %d", resp.StatusCode)
+		}
+		if resp := doAdminRequestWithToken(s, http.MethodGet, "/debug/pprof/", nil, "wrong"); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 with wrong token, got %d", resp.StatusCode)
+		}
+		if resp := doAdminRequest(s, http.MethodGet, "/debug/config", nil); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected /debug/config to require token, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("serves profiles", func(t *testing.T) {
+		s := newTestAdminServerWithConfig(new(slog.LevelVar), pprofCfg)
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/goroutine?debug=1", "/debug/goroutines"} {
+			resp := doAdminRequestWithToken(s, http.MethodGet, path, nil, pprofCfg.Token)
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("%s: expected 200, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("caps trace duration", func(t *testing.T) {
+		s := newTestAdminServerWithConfig(new(slog.LevelVar), pprofCfg)
+
+		for _, path := range []string{"/debug/pprof/trace?seconds=60", "/debug/pprof/profile?seconds=abc"} {
+			resp := doAdminRequestWithToken(s, http.MethodGet, path, nil, pprofCfg.Token)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"strconv"
+
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getAuditLog отдаёт журнал аудита
+// @Summary Get audit log
+// @Description Returns audit log entries, newest first. Every create, update and delete of an example is recorded in the same transaction as the change, with the actor, request ID, client IP and the entity state before and after (null for create and delete respectively).
+// @Tags audit
+// @Produce json
+// @Param actor query string false "Filter by actor"
+// @Param entity_type query string false "Filter by entity type (e.g. example)"
+// @Param entity_id query string false "Filter by entity ID"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param limit query int false "Number of entries (max 100)" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.AuditResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Router /audit [get]
+func (s *Server) getAuditLog(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	entries, err := s.services.Audit.GetAuditLog(c.UserContext(), filter)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.AuditResponse{
+		Data: entries,
+	})
+}
+
+func parseAuditFilter(c *fiber.Ctx) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Actor:      c.Query("actor"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid limit parameter")
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid offset parameter")
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	if filter.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
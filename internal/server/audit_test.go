@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+type mockAuditService struct {
+	getFn func(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+func (m *mockAuditService) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	return m.getFn(ctx, filter)
+}
+
+func TestGetAuditLog(t *testing.T) {
+	t.Run("filters", func(t *testing.T) {
+		var got models.AuditFilter
+		mock := &mockAuditService{
+			getFn: func(_ context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+				got = filter
+				return []models.AuditEntry{{ID: 1, Actor: "alice", Action: "update"}}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Audit: mock})
+
+		resp := doRequest(s, http.MethodGet,
+			"/api/v1/audit?actor=alice&entity_type=example&entity_id=7&created_after=2026-01-01T00:00:00Z", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		body := decodeJSON[models.AuditResponse](t, resp)
+		if len(body.Data) != 1 {
+			t.Errorf("expected one entry, got %+v", body.Data)
+		}
+		if got.Actor != "alice" || got.EntityType != "example" || got.EntityID != "7" || got.CreatedAfter == nil || got.Limit != 50 {
+			t.Errorf("unexpected filter %+v", got)
+		}
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		s := newTestServer(&service.Services{Audit: &mockAuditService{}})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/audit?created_before=yesterday", nil)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestActorMiddleware(t *testing.T) {
+	var actor service.Actor
+	mock := &mockExampleService{
+		deleteFn: func(ctx context.Context, _ int) error {
+			actor = service.ActorFrom(ctx)
+			return nil
+		},
+	}
+	s := newTestServer(&service.Services{Example: mock})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/examples/1", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	resp, _ := s.app.Test(req, -1)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if actor.Principal != anonymousPrincipal || actor.RequestID != "req-42" || actor.ClientIP == "" {
+		t.Errorf("unexpected actor %+v", actor)
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// exampleETag строит сильный ETag записи из ID и updated_at: любое изменение
+// записи обновляет updated_at, поэтому одинаковый тег означает одинаковое
+// представление. Точность — микросекунды, как у timestamptz в Postgres.
+func exampleETag(example *models.Example) string {
+	return `"` + strconv.FormatInt(int64(example.ID), 36) + "-" + strconv.FormatInt(example.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// listETag строит сильный ETag страницы списка из ID и updated_at всех
+// записей по порядку. Добавление, удаление или изменение любой записи
+// страницы меняет тег.
+func listETag(examples []models.Example) string {
+	h := sha256.New()
+	var buf [16]byte
+	for i := range examples {
+		binary.BigEndian.PutUint64(buf[:8], uint64(examples[i].ID))
+		binary.BigEndian.PutUint64(buf[8:], uint64(examples[i].UpdatedAt.UnixMicro()))
+		h.Write(buf[:])
+	}
+	return `"l` + strconv.Itoa(len(examples)) + "-" + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// writeValidators выставляет ETag и (если lastModified не нулевой)
+// Last-Modified и сообщает, можно ли ответить 304 Not Modified.
+func writeValidators(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	c.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	return notModified(c.Get(fiber.HeaderIfNoneMatch), c.Get(fiber.HeaderIfModifiedSince), etag, lastModified)
+}
+
+// notModified вычисляет предусловия GET по RFC 9110 (13.2.2):
+// If-None-Match (слабое сравнение) имеет приоритет, If-Modified-Since
+// учитывается, только если If-None-Match не передан.
+func notModified(ifNoneMatch, ifModifiedSince, etag string, lastModified time.Time) bool {
+	if ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified передаётся с точностью до секунды.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches проверяет, есть ли etag в списке If-None-Match. Префикс W/
+// игнорируется: для GET спецификация требует слабого сравнения.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl выставляет Cache-Control для успешных ответов маршрута.
+// Ошибки не получают заголовок, чтобы CDN не закешировал 404 или 500.
+func cacheControl(value string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if value == "" || err != nil {
+			return err
+		}
+		if status := c.Response().StatusCode(); status == fiber.StatusOK || status == fiber.StatusNotModified {
+			c.Set(fiber.HeaderCacheControl, value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	etag := `"1-abc"`
+
+	tests := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		want            bool
+	}{
+		{name: "unconditional", want: false},
+		{name: "matching etag", ifNoneMatch: `"1-abc"`, want: true},
+		{name: "weak matching etag", ifNoneMatch: `W/"1-abc"`, want: true},
+		{name: "etag in list", ifNoneMatch: `"0-zzz", "1-abc"`, want: true},
+		{name: "wildcard", ifNoneMatch: "*", want: true},
+		{name: "other etag", ifNoneMatch: `"1-abd"`, want: false},
+		{name: "etag wins over date", ifNoneMatch: `"1-abd"`, ifModifiedSince: "Sat, 02 Jan 2027 00:00:00 GMT", want: false},
+		{name: "same second", ifModifiedSince: "Fri, 02 Jan 2026 03:04:05 GMT", want: true},
+		{name: "modified later", ifModifiedSince: "Fri, 02 Jan 2026 03:04:04 GMT", want: false},
+		{name: "invalid date", ifModifiedSince: "yesterday", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notModified(tt.ifNoneMatch, tt.ifModifiedSince, etag, modified); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListETag(t *testing.T) {
+	now := time.Now()
+	page := []models.Example{{ID: 1, UpdatedAt: now}, {ID: 2, UpdatedAt: now}}
+
+	if listETag(page) != listETag([]models.Example{{ID: 1, UpdatedAt: now}, {ID: 2, UpdatedAt: now}}) {
+		t.Error("expected equal pages to have equal ETags")
+	}
+	if listETag(page) == listETag(page[:1]) {
+		t.Error("expected removing a record to change the ETag")
+	}
+	changed := []models.Example{{ID: 1, UpdatedAt: now}, {ID: 2, UpdatedAt: now.Add(time.Millisecond)}}
+	if listETag(page) == listETag(changed) {
+		t.Error("expected updating a record to change the ETag")
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock := &mockExampleService{
+		getByIDFn: func(_ context.Context, id int) (*models.Example, error) {
+			if id != 1 {
+				return nil, service.ErrExampleNotFound
+			}
+			return &models.Example{ID: 1, Name: "a", UpdatedAt: updatedAt}, nil
+		},
+		getAllFn: func(context.Context, models.ExampleFilter) ([]models.Example, error) {
+			return []models.Example{{ID: 1, Name: "a", UpdatedAt: updatedAt}}, nil
+		},
+	}
+	s := newTestServer(&service.Services{Example: mock}, withTestConfig(func(cfg *config.Config) {
+		cfg.Server.ExampleCacheControl = "private, max-age=60"
+		cfg.Server.ListCacheControl = "no-cache"
+	}))
+
+	get := func(path string, headers map[string]string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, _ := s.app.Test(req, -1)
+		return resp
+	}
+
+	first := get("/api/v1/examples/1", nil)
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", first.StatusCode, etag)
+	}
+	if got := first.Header.Get("Last-Modified"); got != "Fri, 02 Jan 2026 03:04:05 GMT" {
+		t.Errorf("unexpected Last-Modified %q", got)
+	}
+	if got := first.Header.Get("Cache-Control"); got != "private, max-age=60" {
+		t.Errorf("unexpected Cache-Control %q", got)
+	}
+
+	for name, headers := range map[string]map[string]string{
+		"if-none-match":     {"If-None-Match": etag},
+		"if-modified-since": {"If-Modified-Since": first.Header.Get("Last-Modified")},
+	} {
+		resp := get("/api/v1/examples/1", headers)
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusNotModified || len(body) != 0 {
+			t.Errorf("%s: expected empty 304, got %d with %d bytes", name, resp.StatusCode, len(body))
+		}
+		if resp.Header.Get("ETag") != etag || resp.Header.Get("Cache-Control") != "private, max-age=60" {
+			t.Errorf("%s: expected validators on 304, got %v", name, resp.Header)
+		}
+	}
+
+	if resp := get("/api/v1/examples/1", map[string]string{"If-None-Match": `"stale"`}); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for stale ETag, got %d", resp.StatusCode)
+	}
+	if resp := get("/api/v1/examples/2", nil); resp.StatusCode != http.StatusNotFound || resp.Header.Get("Cache-Control") != "" {
+		t.Errorf("expected 404 without Cache-Control, got %d %q", resp.StatusCode, resp.Header.Get("Cache-Control"))
+	}
+
+	list := get("/api/v1/examples", nil)
+	if list.Header.Get("ETag") == "" || list.Header.Get("Last-Modified") != "" || list.Header.Get("Cache-Control") != "no-cache" {
+		t.Fatalf("unexpected list headers: %v", list.Header)
+	}
+	if resp := get("/api/v1/examples", map[string]string{"If-None-Match": list.Header.Get("ETag")}); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304 for list, got %d", resp.StatusCode)
+	}
+}
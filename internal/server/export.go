@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// exportFlushRows — через сколько строк буфер отправляется клиенту и
+	// продлевается дедлайн записи.
+	exportFlushRows = 500
+)
+
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:    "text/csv; charset=utf-8",
+	service.ExportFormatNDJSON: "application/x-ndjson",
+	service.ExportFormatJSON:   fiber.MIMEApplicationJSON,
+}
+
+// exportExamples выгружает записи потоком
+// @Summary Export examples
+// @Description Streams all examples matching the list filters as CSV, NDJSON or a JSON array. Rows are read from a database cursor and written as they arrive, so the export is not limited by SERVER_WRITE_TIMEOUT. The body is gzip-compressed when the client sends Accept-Encoding: gzip. With async=true the export runs in the background instead: the response is 202 Accepted with the operation, and the file is downloaded from /operations/{id}/output once it succeeds. A background export is limited to 16 MiB of gzip-compressed output; a larger one fails, so use the streaming mode for big exports.
+// @Tags examples
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param format query string false "Output format" Enums(csv, ndjson, json) default(csv)
+// @Param limit query int false "Maximum number of records (0 — all)" default(0)
+// @Param offset query int false "Offset" default(0)
+// @Param is_active query bool false "Filter by is_active"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param async query bool false "Run as a background operation: respond 202 Accepted with the operation and a Location header to poll"
+// @Success 200 {file} file
+// @Success 202 {object} models.Operation "Export started"
+// @Header 202 {string} Location "/api/v1/operations/{id}"
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Router /examples/export [get]
+func (s *Server) exportExamples(c *fiber.Ctx) error {
+	format := c.Query("format", service.ExportFormatCSV)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid format parameter: expected csv, ndjson or json",
+		})
+	}
+
+	filter, err := parseExampleFilter(c, "0")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if v := c.Query("async"); v != "" {
+		async, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid async parameter",
+			})
+		}
+		if async {
+			return s.exportExamplesAsync(c, filter, format)
+		}
+	}
+
+	// Запрос выполняется уже после выхода из обработчика, поэтому контекст не
+	// привязан к fiber.Ctx: он отменяется при остановке сервера или при
+	// обрыве записи клиенту.
+	ctx, cancel := context.WithCancel(s.streams)
+	rows, err := s.services.Example.ExportExamples(ctx, filter)
+	if err != nil {
+		cancel()
+		return s.handleServiceError(c, err)
+	}
+
+	compress := c.Context().Request.Header.HasAcceptEncoding("gzip")
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="examples.`+format+`"`)
+	c.Set(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+	if compress {
+		c.Set(fiber.HeaderContentEncoding, "gzip")
+	}
+
+	// fasthttp выставляет дедлайн записи один раз на весь ответ. Поток
+	// продлевает его перед каждой порцией, так что ограничение
+	// SERVER_WRITE_TIMEOUT действует на отправку одной порции, а не всей выгрузки.
+	conn := c.Context().Conn()
+	writeTimeout := s.config.Server.WriteTimeout
+	logger := s.logger
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		s.activeExports.Add(1)
+		defer s.activeExports.Add(-1)
+
+		extend := func() {
+			if writeTimeout > 0 {
+				_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			}
+		}
+
+		var out io.Writer = w
+		var gz *gzip.Writer
+		if compress {
+			gz = gzip.NewWriter(w)
+			out = gz
+		}
+		flush := func() error {
+			extend()
+			if gz != nil {
+				if err := gz.Flush(); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		}
+
+		count, err := service.WriteExport(format, out, rows, exportFlushRows, func(int) error { return flush() })
+		if err == nil && gz != nil {
+			extend()
+			err = gz.Close()
+		}
+		if err != nil {
+			// Ответ уже начат, статус поменять нельзя. Обрываем соединение,
+			// чтобы клиент не принял усечённую выгрузку за полную.
+			logger.Error("Export aborted", slog.String("format", format), slog.Int("rows", count), slog.String("error", err.Error()))
+			_ = conn.Close()
+			return
+		}
+		logger.Info("Export completed", slog.String("format", format), slog.Int("rows", count))
+	})
+
+	return nil
+}
+
+// exportExamplesAsync запускает выгрузку фоновой операцией.
+func (s *Server) exportExamplesAsync(c *fiber.Ctx, filter models.ExampleFilter, format string) error {
+	if s.services.Operations == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Asynchronous export is not available",
+		})
+	}
+
+	op, err := s.services.Operations.StartExport(c.UserContext(), filter, format)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return acceptOperation(c, op)
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func exampleRows(n int) iter.Seq2[*models.Example, error] {
+	return func(yield func(*models.Example, error) bool) {
+		created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		for i := 1; i <= n; i++ {
+			example := &models.Example{ID: i, Name: "name, with comma", Value: 1.5, IsActive: true, CreatedAt: created, UpdatedAt: created}
+			if !yield(example, nil) {
+				return
+			}
+		}
+	}
+}
+
+func newExportServer(t *testing.T, n int) *Server {
+	t.Helper()
+	mock := &mockExampleService{
+		exportFn: func(_ context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+			if filter.Limit != 0 {
+				t.Errorf("expected export without limit, got %d", filter.Limit)
+			}
+			return exampleRows(n), nil
+		},
+	}
+	return newTestServer(&service.Services{Example: mock})
+}
+
+func doExportRequest(t *testing.T, s *Server, query string, gzipped bool) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/examples/export"+query, nil)
+	if gzipped {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	resp, err := s.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp
+}
+
+func TestExportExamples_Formats(t *testing.T) {
+	const rows = exportFlushRows + 3
+
+	t.Run("csv by default", func(t *testing.T) {
+		resp := doExportRequest(t, newExportServer(t, rows), "", false)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Fatalf("unexpected content type %q", ct)
+		}
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+		if len(records) != rows+1 || records[0][0] != "id" || records[1][1] != "name, with comma" {
+			t.Fatalf("unexpected csv: %d records, first %v", len(records), records[:2])
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		resp := doExportRequest(t, newExportServer(t, rows), "?format=ndjson", false)
+		scanner := bufio.NewScanner(resp.Body)
+		count := 0
+		for scanner.Scan() {
+			var example models.Example
+			if err := json.Unmarshal(scanner.Bytes(), &example); err != nil {
+				t.Fatalf("line %d: %v", count, err)
+			}
+			count++
+		}
+		if count != rows {
+			t.Fatalf("expected %d lines, got %d", rows, count)
+		}
+	})
+
+	t.Run("json array gzipped", func(t *testing.T) {
+		resp := doExportRequest(t, newExportServer(t, rows), "?format=json", true)
+		if resp.Header.Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected gzip encoding, got %q", resp.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatalf("invalid gzip: %v", err)
+		}
+		var examples []models.Example
+		if err := json.NewDecoder(zr).Decode(&examples); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if len(examples) != rows {
+			t.Fatalf("expected %d examples, got %d", rows, len(examples))
+		}
+	})
+
+	t.Run("empty json array", func(t *testing.T) {
+		resp := doExportRequest(t, newExportServer(t, 0), "?format=json", false)
+		body, _ := io.ReadAll(resp.Body)
+		var examples []models.Example
+		if err := json.Unmarshal(body, &examples); err != nil || len(examples) != 0 {
+			t.Fatalf("expected empty array, got %q (%v)", body, err)
+		}
+	})
+}
+
+func TestExportExamples_Errors(t *testing.T) {
+	t.Run("invalid format", func(t *testing.T) {
+		resp := doExportRequest(t, newExportServer(t, 1), "?format=xml", false)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mock := &mockExampleService{
+			exportFn: func(context.Context, models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+				return nil, service.ErrInvalidTimeRange
+			},
+		}
+		resp := doExportRequest(t, newTestServer(&service.Services{Example: mock}), "", false)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("storage failure mid-stream truncates response", func(t *testing.T) {
+		mock := &mockExampleService{
+			exportFn: func(context.Context, models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+				return func(yield func(*models.Example, error) bool) {
+					for example := range exampleRows(exportFlushRows + 1) {
+						if !yield(example, nil) {
+							return
+						}
+					}
+					yield(nil, service.ErrExportExamplesFailed)
+				}, nil
+			},
+		}
+		resp := doExportRequest(t, newTestServer(&service.Services{Example: mock}), "?format=json", false)
+		var examples []models.Example
+		err := json.NewDecoder(resp.Body).Decode(&examples)
+		if err == nil {
+			t.Fatal("expected truncated JSON after a mid-stream failure")
+		}
+		if errors.Is(err, io.EOF) {
+			t.Fatal("expected partial body, got empty response")
+		}
+	})
+}
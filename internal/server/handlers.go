@@ -1,11 +1,11 @@
 package server
 
 import (
-	"context"
 	"errors"
 	"strconv"
 	"time"
 
+	"go-service-template/internal/health"
 	"go-service-template/internal/models"
 	"go-service-template/internal/service"
 
@@ -23,28 +23,63 @@ func (s *Server) liveness(c *fiber.Ctx) error {
 	return c.JSON(models.MessageResponse{Message: "alive"})
 }
 
-// readiness проверка готовности обслуживать трафик (доступность БД)
+// readiness проверка готовности обслуживать трафик (критичные зависимости)
 // @Summary Readiness probe
-// @Description Returns 200 when dependencies are reachable, 503 otherwise. Wire to k8s readinessProbe.
+// @Description Returns 200 when critical dependencies are reachable, 503 otherwise. Wire to k8s readinessProbe. With verbose=1 returns per-component status and latency.
 // @Tags health
 // @Produce json
+// @Param verbose query bool false "Return per-component report"
 // @Success 200 {object} models.MessageResponse
 // @Failure 503 {object} models.ErrorResponse "Service unavailable"
 // @Router /readyz [get]
 func (s *Server) readiness(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
-	defer cancel()
+	if s.Draining() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+			Error: "service is shutting down",
+		})
+	}
+
+	report := s.health.Check(c.UserContext())
+	failed, unhealthy := health.FirstFailure(report)
+	if unhealthy {
+		s.logger.Error("readiness check failed", "component", failed, "report", report.Components)
+	}
+
+	if c.QueryBool("verbose") {
+		status := fiber.StatusOK
+		if unhealthy {
+			status = fiber.StatusServiceUnavailable
+		}
+		return c.Status(status).JSON(report)
+	}
 
-	if err := s.services.Ping(ctx); err != nil {
-		s.logger.Error("readiness check failed", "error", err)
+	if unhealthy {
 		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
-			Error: "database is unavailable",
+			Error: failed + " is unavailable",
 		})
 	}
 
 	return c.JSON(models.MessageResponse{Message: "ready"})
 }
 
+// startup проверка завершения стартовых процедур (миграции, прогрев)
+// @Summary Startup probe
+// @Description Returns 200 once initial migrations and warm-up are done, 503 before. Wire to k8s startupProbe.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.MessageResponse
+// @Failure 503 {object} models.ErrorResponse "Still starting"
+// @Router /startupz [get]
+func (s *Server) startup(c *fiber.Ctx) error {
+	if !s.health.Started() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+			Error: "service is starting",
+		})
+	}
+
+	return c.JSON(models.MessageResponse{Message: "started"})
+}
+
 // createExample создает новый пример
 // @Summary Create example
 // @Description Creates a new example record
@@ -73,36 +108,38 @@ func (s *Server) createExample(c *fiber.Ctx) error {
 
 // getAllExamples получает список всех примеров
 // @Summary Get all examples
-// @Description Returns a list of all examples with pagination
+// @Description Returns a list of all examples with pagination and optional filters. The response carries a strong ETag of the page; send it back in If-None-Match to get 304 when nothing changed.
 // @Tags examples
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag of a previously received page"
 // @Param limit query int false "Number of records" default(10)
 // @Param offset query int false "Offset" default(0)
+// @Param is_active query bool false "Filter by is_active"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
 // @Success 200 {object} models.ExampleResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} models.ErrorResponse "Invalid parameters"
 // @Router /examples [get]
 func (s *Server) getAllExamples(c *fiber.Ctx) error {
-	limitStr := c.Query("limit", "10")
-	offsetStr := c.Query("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
+	filter, err := parseExampleFilter(c, "10")
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-			Error: "Invalid limit parameter",
+			Error: err.Error(),
 		})
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
+	examples, err := s.services.Example.GetAllExamples(c.UserContext(), filter)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-			Error: "Invalid offset parameter",
-		})
+		return s.handleServiceError(c, err)
 	}
 
-	examples, err := s.services.Example.GetAllExamples(c.UserContext(), limit, offset)
-	if err != nil {
-		return s.handleServiceError(c, err)
+	// Last-Modified у списка не выставляется: удаление записи не двигает
+	// максимальный updated_at, и If-Modified-Since вернул бы устаревшую страницу.
+	if writeValidators(c, listETag(examples), time.Time{}) {
+		c.Status(fiber.StatusNotModified)
+		return nil
 	}
 
 	return c.JSON(models.ExampleResponse{
@@ -110,14 +147,63 @@ func (s *Server) getAllExamples(c *fiber.Ctx) error {
 	})
 }
 
+// parseExampleFilter разбирает общие для списка и экспорта query-параметры.
+// defaultLimit подставляется, если limit не передан ("0" — без ограничения).
+// Текст ошибки (*fiber.Error) можно отдавать клиенту как есть.
+func parseExampleFilter(c *fiber.Ctx, defaultLimit string) (models.ExampleFilter, error) {
+	var filter models.ExampleFilter
+
+	limit, err := strconv.Atoi(c.Query("limit", defaultLimit))
+	if err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid limit parameter")
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid offset parameter")
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	if v := c.Query("is_active"); v != "" {
+		isActive, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid is_active parameter")
+		}
+		filter.IsActive = &isActive
+	}
+	if filter.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseTimeQuery(c *fiber.Ctx, name string) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid "+name+" parameter: expected RFC 3339")
+	}
+	return &t, nil
+}
+
 // getExample получает пример по ID
 // @Summary Get example by ID
-// @Description Returns an example by its ID
+// @Description Returns an example by its ID. The response carries a strong ETag and Last-Modified derived from updated_at; If-None-Match or If-Modified-Since yield 304 when the record is unchanged.
 // @Tags examples
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag of a previously received representation"
+// @Param If-Modified-Since header string false "HTTP date of a previously received representation"
 // @Param id path int true "Example ID"
+// @Param as_of query string false "Return the example as it was at this time (RFC 3339); 404 if it did not exist then"
 // @Success 200 {object} models.Example
+// @Success 304 "Not modified"
 // @Failure 400 {object} models.ErrorResponse "Invalid ID"
 // @Failure 404 {object} models.ErrorResponse "Example not found"
 // @Router /examples/{id} [get]
@@ -130,11 +216,20 @@ func (s *Server) getExample(c *fiber.Ctx) error {
 		})
 	}
 
+	if c.Query("as_of") != "" {
+		return s.getExampleAsOf(c, id)
+	}
+
 	example, err := s.services.Example.GetExampleByID(c.UserContext(), id)
 	if err != nil {
 		return s.handleServiceError(c, err)
 	}
 
+	if writeValidators(c, exampleETag(example), example.UpdatedAt) {
+		c.Status(fiber.StatusNotModified)
+		return nil
+	}
+
 	return c.JSON(example)
 }
 
@@ -203,6 +298,52 @@ func (s *Server) deleteExample(c *fiber.Ctx) error {
 	})
 }
 
+// batchExamples применяет пакет операций create/update/delete
+// @Summary Batch create, update and delete examples
+// @Description Applies up to 1000 operations. In atomic mode (default) either all operations are applied or none; in best_effort mode every valid operation is applied. Each result carries its own status and error.
+// @Tags examples
+// @Accept json
+// @Produce json
+// @Param batch body models.BatchRequest true "Batch operations"
+// @Success 200 {object} models.BatchResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid batch"
+// @Failure 422 {object} models.BatchResponse "Atomic batch rejected, nothing applied"
+// @Router /examples:batch [post]
+func (s *Server) batchExamples(c *fiber.Ctx) error {
+	var req models.BatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	resp, err := s.services.Example.BatchExamples(c.UserContext(), &req)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		switch {
+		case result.Err == nil && result.Op == service.BatchOpCreate:
+			result.Status = fiber.StatusCreated
+		case result.Err == nil:
+			result.Status = fiber.StatusOK
+		default:
+			result.Status = mapServiceErrorToHTTPStatus(result.Err)
+			result.Error = result.Err.Error()
+			if result.Status == fiber.StatusInternalServerError {
+				result.Error = "internal server error"
+			}
+		}
+	}
+
+	if !resp.Applied {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(resp)
+	}
+	return c.JSON(resp)
+}
+
 func (s *Server) handleServiceError(c *fiber.Ctx, err error) error {
 	status := mapServiceErrorToHTTPStatus(err)
 	if status == fiber.StatusInternalServerError {
@@ -219,17 +360,54 @@ func (s *Server) handleServiceError(c *fiber.Ctx, err error) error {
 
 func mapServiceErrorToHTTPStatus(err error) int {
 	switch {
-	case errors.Is(err, service.ErrExampleNotFound):
+	case errors.Is(err, service.ErrExampleNotFound),
+		errors.Is(err, service.ErrWebhookNotFound),
+		errors.Is(err, service.ErrRevisionNotFound),
+		errors.Is(err, service.ErrOperationNotFound),
+		errors.Is(err, service.ErrOperationHasNoOutput):
 		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrBatchAborted):
+		return fiber.StatusFailedDependency
 	case errors.Is(err, service.ErrInvalidExampleID),
 		errors.Is(err, service.ErrLimitMustBePositive),
+		errors.Is(err, service.ErrLimitMustBeNonNeg),
 		errors.Is(err, service.ErrOffsetMustBeNonNeg),
 		errors.Is(err, service.ErrRequestCannotBeNil),
 		errors.Is(err, service.ErrNameRequired),
 		errors.Is(err, service.ErrNameTooLong),
 		errors.Is(err, service.ErrDescriptionTooLong),
-		errors.Is(err, service.ErrValueCannotBeNeg):
+		errors.Is(err, service.ErrValueCannotBeNeg),
+		errors.Is(err, service.ErrBatchEmpty),
+		errors.Is(err, service.ErrBatchTooLarge),
+		errors.Is(err, service.ErrInvalidBatchMode),
+		errors.Is(err, service.ErrInvalidBatchOp),
+		errors.Is(err, service.ErrBatchDataRequired),
+		errors.Is(err, service.ErrBatchDuplicateID),
+		errors.Is(err, service.ErrInvalidTimeRange),
+		errors.Is(err, service.ErrInvalidStatsBucket),
+		errors.Is(err, service.ErrInvalidTimezone),
+		errors.Is(err, service.ErrSearchQueryRequired),
+		errors.Is(err, service.ErrSearchQueryTooLong),
+		errors.Is(err, service.ErrInvalidSearchMode),
+		errors.Is(err, service.ErrInvalidImportFormat),
+		errors.Is(err, service.ErrInvalidExportFormat),
+		errors.Is(err, service.ErrInvalidImportChunkSize),
+		errors.Is(err, service.ErrImportInvalidHeader),
+		errors.Is(err, service.ErrInvalidWebhookID),
+		errors.Is(err, service.ErrWebhookURLInvalid),
+		errors.Is(err, service.ErrWebhookURLForbidden),
+		errors.Is(err, service.ErrWebhookSecretTooShort),
+		errors.Is(err, service.ErrWebhookSecretTooLong),
+		errors.Is(err, service.ErrWebhookEventTypesRequired),
+		errors.Is(err, service.ErrWebhookUnknownEventType),
+		errors.Is(err, service.ErrInvalidRevision),
+		errors.Is(err, service.ErrInvalidOperationID),
+		errors.Is(err, service.ErrPurgeFilterRequired):
 		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrRestoreDeletedRevision),
+		errors.Is(err, service.ErrOperationFinished),
+		errors.Is(err, service.ErrOperationOutputNotReady):
+		return fiber.StatusConflict
 	default:
 		return fiber.StatusInternalServerError
 	}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"iter"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"go-service-template/internal/config"
+	"go-service-template/internal/health"
 	"go-service-template/internal/models"
 	"go-service-template/internal/service"
 )
@@ -20,9 +22,21 @@ import (
 type mockExampleService struct {
 	createFn  func(ctx context.Context, req *models.ExampleRequest) (*models.Example, error)
 	getByIDFn func(ctx context.Context, id int) (*models.Example, error)
-	getAllFn  func(ctx context.Context, limit, offset int) ([]models.Example, error)
+	getAllFn  func(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error)
+	exportFn  func(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error)
 	updateFn  func(ctx context.Context, id int, req *models.ExampleRequest) (*models.Example, error)
 	deleteFn  func(ctx context.Context, id int) error
+	batchFn   func(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error)
+	importFn  func(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error)
+	purgeFn   func(ctx context.Context, filter models.ExampleFilter, progress func(int)) (int, error)
+	statsFn   func(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error)
+	searchFn  func(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error)
+
+	revisionsFn func(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error)
+	revisionFn  func(ctx context.Context, id, rev int) (*models.ExampleRevision, error)
+	asOfFn      func(ctx context.Context, id int, asOf time.Time) (*models.Example, error)
+	restoreFn   func(ctx context.Context, id, rev int) (*models.Example, error)
+	diffFn      func(ctx context.Context, id, from, to int) (*models.RevisionDiff, error)
 }
 
 func (m *mockExampleService) CreateExample(ctx context.Context, req *models.ExampleRequest) (*models.Example, error) {
@@ -39,13 +53,20 @@ func (m *mockExampleService) GetExampleByID(ctx context.Context, id int) (*model
 	return nil, nil
 }
 
-func (m *mockExampleService) GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error) {
+func (m *mockExampleService) GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error) {
 	if m.getAllFn != nil {
-		return m.getAllFn(ctx, limit, offset)
+		return m.getAllFn(ctx, filter)
 	}
 	return nil, nil
 }
 
+func (m *mockExampleService) ExportExamples(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+	if m.exportFn != nil {
+		return m.exportFn(ctx, filter)
+	}
+	return func(func(*models.Example, error) bool) {}, nil
+}
+
 func (m *mockExampleService) UpdateExample(ctx context.Context, id int, req *models.ExampleRequest) (*models.Example, error) {
 	if m.updateFn != nil {
 		return m.updateFn(ctx, id, req)
@@ -60,10 +81,82 @@ func (m *mockExampleService) DeleteExample(ctx context.Context, id int) error {
 	return nil
 }
 
-func newTestServer(mock *mockExampleService, pingFn func(ctx context.Context) error) *Server {
-	services := &service.Services{
-		Example:  mock,
-		PingFunc: pingFn,
+func (m *mockExampleService) BatchExamples(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error) {
+	if m.batchFn != nil {
+		return m.batchFn(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockExampleService) ImportExamples(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	if m.importFn != nil {
+		return m.importFn(ctx, r, opts)
+	}
+	return &models.ImportReport{}, nil
+}
+
+func (m *mockExampleService) PurgeExamples(ctx context.Context, filter models.ExampleFilter, progress func(int)) (int, error) {
+	if m.purgeFn != nil {
+		return m.purgeFn(ctx, filter, progress)
+	}
+	return 0, nil
+}
+
+func (m *mockExampleService) GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error) {
+	if m.revisionsFn != nil {
+		return m.revisionsFn(ctx, id, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *mockExampleService) GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error) {
+	if m.revisionFn != nil {
+		return m.revisionFn(ctx, id, rev)
+	}
+	return &models.ExampleRevision{}, nil
+}
+
+func (m *mockExampleService) GetExampleAsOf(ctx context.Context, id int, asOf time.Time) (*models.Example, error) {
+	if m.asOfFn != nil {
+		return m.asOfFn(ctx, id, asOf)
+	}
+	return &models.Example{}, nil
+}
+
+func (m *mockExampleService) RestoreExampleRevision(ctx context.Context, id, rev int) (*models.Example, error) {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, id, rev)
+	}
+	return &models.Example{}, nil
+}
+
+func (m *mockExampleService) DiffExampleRevisions(ctx context.Context, id, from, to int) (*models.RevisionDiff, error) {
+	if m.diffFn != nil {
+		return m.diffFn(ctx, id, from, to)
+	}
+	return &models.RevisionDiff{}, nil
+}
+
+func (m *mockExampleService) GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+	if m.statsFn != nil {
+		return m.statsFn(ctx, query)
+	}
+	return &models.ExampleStats{}, nil
+}
+
+func (m *mockExampleService) SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+	if m.searchFn != nil {
+		return m.searchFn(ctx, query)
+	}
+	return []models.ExampleSearchResult{}, nil
+}
+
+// newTestServer собирает сервер с маршрутами поверх моков services.
+// Незаданный Example заменяется пустым mockExampleService; opts — опции
+// сервера (WithHealth, WithEvents, ...) и withTestConfig.
+func newTestServer(services *service.Services, opts ...Option) *Server {
+	if services.Example == nil {
+		services.Example = &mockExampleService{}
 	}
 	cfg := &config.Config{
 		Server: config.ServerConfig{
@@ -73,11 +166,18 @@ func newTestServer(mock *mockExampleService, pingFn func(ctx context.Context) er
 		},
 	}
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	s := New(services, logger, cfg)
+	s := New(services, logger, cfg, opts...)
 	s.setupRoutes()
 	return s
 }
 
+// withTestConfig меняет конфигурацию тестового сервера до сборки маршрутов.
+func withTestConfig(fn func(cfg *config.Config)) Option {
+	return func(s *Server) {
+		fn(s.config)
+	}
+}
+
 func doRequest(s *Server, method, path string, body any) *http.Response {
 	var reqBody io.Reader
 	if body != nil {
@@ -101,9 +201,9 @@ func decodeJSON[T any](t *testing.T, resp *http.Response) T {
 
 func TestLiveness(t *testing.T) {
 	// Liveness не должен зависеть от БД: падающий ping всё равно даёт 200.
-	s := newTestServer(&mockExampleService{}, func(ctx context.Context) error {
+	s := newTestServer(&service.Services{PingFunc: func(ctx context.Context) error {
 		return errors.New("db down")
-	})
+	}})
 
 	resp := doRequest(s, http.MethodGet, "/livez", nil)
 	if resp.StatusCode != http.StatusOK {
@@ -117,7 +217,7 @@ func TestLiveness(t *testing.T) {
 
 func TestReadiness(t *testing.T) {
 	t.Run("ready", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, func(ctx context.Context) error { return nil })
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodGet, "/readyz", nil)
 		if resp.StatusCode != http.StatusOK {
@@ -130,9 +230,9 @@ func TestReadiness(t *testing.T) {
 	})
 
 	t.Run("database unavailable", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, func(ctx context.Context) error {
+		s := newTestServer(&service.Services{PingFunc: func(ctx context.Context) error {
 			return errors.New("connection refused")
-		})
+		}})
 
 		resp := doRequest(s, http.MethodGet, "/readyz", nil)
 		if resp.StatusCode != http.StatusServiceUnavailable {
@@ -144,8 +244,23 @@ func TestReadiness(t *testing.T) {
 		}
 	})
 
+	t.Run("draining", func(t *testing.T) {
+		s := newTestServer(&service.Services{})
+		s.BeginDrain()
+
+		resp := doRequest(s, http.MethodGet, "/readyz", nil)
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %d", resp.StatusCode)
+		}
+
+		// Liveness при остановке не меняется, иначе k8s перезапустит под.
+		if resp := doRequest(s, http.MethodGet, "/livez", nil); resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected liveness 200 while draining, got %d", resp.StatusCode)
+		}
+	})
+
 	t.Run("alias /health maps to readiness", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, func(ctx context.Context) error { return nil })
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodGet, "/health", nil)
 		if resp.StatusCode != http.StatusOK {
@@ -154,6 +269,39 @@ func TestReadiness(t *testing.T) {
 	})
 }
 
+func TestReadinessVerbose(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register(health.Check{Name: "database", Critical: true, Func: func(context.Context) error { return nil }})
+	registry.Register(health.Check{Name: "cache", Func: func(context.Context) error { return errors.New("cold") }})
+	s := newTestServer(&service.Services{}, WithHealth(registry))
+
+	resp := doRequest(s, http.MethodGet, "/readyz?verbose=1", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeJSON[models.HealthReport](t, resp)
+	if body.Status != health.StatusUp || len(body.Components) != 2 {
+		t.Fatalf("unexpected report: %+v", body)
+	}
+	if body.Components[1].Status != health.StatusDown {
+		t.Fatalf("expected cache down, got %+v", body.Components[1])
+	}
+}
+
+func TestStartup(t *testing.T) {
+	registry := health.NewRegistry()
+	s := newTestServer(&service.Services{}, WithHealth(registry))
+
+	if resp := doRequest(s, http.MethodGet, "/startupz", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before startup, got %d", resp.StatusCode)
+	}
+
+	registry.MarkStarted()
+	if resp := doRequest(s, http.MethodGet, "/startupz", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 after startup, got %d", resp.StatusCode)
+	}
+}
+
 func TestCreateExample(t *testing.T) {
 	now := time.Now()
 
@@ -163,7 +311,7 @@ func TestCreateExample(t *testing.T) {
 				return &models.Example{ID: 1, Name: req.Name, CreatedAt: now, UpdatedAt: now}, nil
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodPost, "/api/v1/examples", models.ExampleRequest{
 			Name: "test", Value: 10,
@@ -178,7 +326,7 @@ func TestCreateExample(t *testing.T) {
 	})
 
 	t.Run("invalid body", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples", bytes.NewReader([]byte("not json")))
 		req.Header.Set("Content-Type", "application/json")
@@ -194,7 +342,7 @@ func TestCreateExample(t *testing.T) {
 				return nil, service.ErrNameRequired
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodPost, "/api/v1/examples", models.ExampleRequest{})
 		if resp.StatusCode != http.StatusBadRequest {
@@ -206,13 +354,16 @@ func TestCreateExample(t *testing.T) {
 func TestGetAllExamples(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock := &mockExampleService{
-			getAllFn: func(_ context.Context, limit, offset int) ([]models.Example, error) {
+			getAllFn: func(_ context.Context, filter models.ExampleFilter) ([]models.Example, error) {
+				if filter.Limit != 10 || filter.IsActive == nil || !*filter.IsActive || filter.CreatedAfter == nil {
+					t.Errorf("unexpected filter: %+v", filter)
+				}
 				return []models.Example{{ID: 1}, {ID: 2}}, nil
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
-		resp := doRequest(s, http.MethodGet, "/api/v1/examples?limit=10&offset=0", nil)
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples?limit=10&offset=0&is_active=true&created_after=2026-01-01T00:00:00Z", nil)
 		if resp.StatusCode != http.StatusOK {
 			t.Fatalf("expected 200, got %d", resp.StatusCode)
 		}
@@ -223,7 +374,7 @@ func TestGetAllExamples(t *testing.T) {
 	})
 
 	t.Run("invalid limit", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodGet, "/api/v1/examples?limit=abc", nil)
 		if resp.StatusCode != http.StatusBadRequest {
@@ -232,13 +383,24 @@ func TestGetAllExamples(t *testing.T) {
 	})
 
 	t.Run("invalid offset", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodGet, "/api/v1/examples?offset=abc", nil)
 		if resp.StatusCode != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", resp.StatusCode)
 		}
 	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		s := newTestServer(&service.Services{})
+
+		for _, query := range []string{"is_active=maybe", "created_after=yesterday", "created_before=2026-13-01"} {
+			resp := doRequest(s, http.MethodGet, "/api/v1/examples?"+query, nil)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+			}
+		}
+	})
 }
 
 func TestGetExample(t *testing.T) {
@@ -248,7 +410,7 @@ func TestGetExample(t *testing.T) {
 				return &models.Example{ID: id, Name: "found"}, nil
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodGet, "/api/v1/examples/5", nil)
 		if resp.StatusCode != http.StatusOK {
@@ -261,7 +423,7 @@ func TestGetExample(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodGet, "/api/v1/examples/abc", nil)
 		if resp.StatusCode != http.StatusBadRequest {
@@ -275,7 +437,7 @@ func TestGetExample(t *testing.T) {
 				return nil, service.ErrExampleNotFound
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodGet, "/api/v1/examples/99", nil)
 		if resp.StatusCode != http.StatusNotFound {
@@ -291,7 +453,7 @@ func TestUpdateExample(t *testing.T) {
 				return &models.Example{ID: id, Name: req.Name}, nil
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodPut, "/api/v1/examples/3", models.ExampleRequest{Name: "upd"})
 		if resp.StatusCode != http.StatusOK {
@@ -304,7 +466,7 @@ func TestUpdateExample(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodPut, "/api/v1/examples/abc", models.ExampleRequest{Name: "x"})
 		if resp.StatusCode != http.StatusBadRequest {
@@ -313,7 +475,7 @@ func TestUpdateExample(t *testing.T) {
 	})
 
 	t.Run("invalid body", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		req := httptest.NewRequest(http.MethodPut, "/api/v1/examples/1", bytes.NewReader([]byte("bad")))
 		req.Header.Set("Content-Type", "application/json")
@@ -329,7 +491,7 @@ func TestUpdateExample(t *testing.T) {
 				return nil, service.ErrExampleNotFound
 			},
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodPut, "/api/v1/examples/99", models.ExampleRequest{Name: "x"})
 		if resp.StatusCode != http.StatusNotFound {
@@ -343,7 +505,7 @@ func TestDeleteExample(t *testing.T) {
 		mock := &mockExampleService{
 			deleteFn: func(_ context.Context, _ int) error { return nil },
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodDelete, "/api/v1/examples/1", nil)
 		if resp.StatusCode != http.StatusOK {
@@ -352,7 +514,7 @@ func TestDeleteExample(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		s := newTestServer(&mockExampleService{}, nil)
+		s := newTestServer(&service.Services{})
 
 		resp := doRequest(s, http.MethodDelete, "/api/v1/examples/abc", nil)
 		if resp.StatusCode != http.StatusBadRequest {
@@ -364,7 +526,7 @@ func TestDeleteExample(t *testing.T) {
 		mock := &mockExampleService{
 			deleteFn: func(_ context.Context, _ int) error { return service.ErrExampleNotFound },
 		}
-		s := newTestServer(mock, nil)
+		s := newTestServer(&service.Services{Example: mock})
 
 		resp := doRequest(s, http.MethodDelete, "/api/v1/examples/99", nil)
 		if resp.StatusCode != http.StatusNotFound {
@@ -373,6 +535,68 @@ func TestDeleteExample(t *testing.T) {
 	})
 }
 
+func TestBatchExamples(t *testing.T) {
+	t.Run("escaped route and per-item statuses", func(t *testing.T) {
+		mock := &mockExampleService{
+			batchFn: func(_ context.Context, req *models.BatchRequest) (*models.BatchResponse, error) {
+				return &models.BatchResponse{
+					Mode:    req.Mode,
+					Applied: true,
+					Results: []models.BatchItemResult{
+						{Index: 0, Op: service.BatchOpCreate, Example: &models.Example{ID: 1}},
+						{Index: 1, Op: service.BatchOpDelete, Err: service.ErrExampleNotFound},
+						{Index: 2, Op: service.BatchOpUpdate, Err: errors.New("db down")},
+					},
+				}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples:batch", models.BatchRequest{Mode: service.BatchModeBestEffort})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		body := decodeJSON[models.BatchResponse](t, resp)
+		want := []int{http.StatusCreated, http.StatusNotFound, http.StatusInternalServerError}
+		for i, result := range body.Results {
+			if result.Status != want[i] {
+				t.Errorf("result %d: expected status %d, got %d", i, want[i], result.Status)
+			}
+		}
+		if body.Results[2].Error != "internal server error" {
+			t.Errorf("expected internal error to be masked, got %q", body.Results[2].Error)
+		}
+	})
+
+	t.Run("atomic batch rejected", func(t *testing.T) {
+		mock := &mockExampleService{
+			batchFn: func(_ context.Context, _ *models.BatchRequest) (*models.BatchResponse, error) {
+				return &models.BatchResponse{Mode: service.BatchModeAtomic, Applied: false}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples:batch", models.BatchRequest{})
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		mock := &mockExampleService{
+			batchFn: func(_ context.Context, _ *models.BatchRequest) (*models.BatchResponse, error) {
+				return nil, service.ErrBatchEmpty
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples:batch", models.BatchRequest{})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
 func TestMapServiceErrorToHTTPStatus(t *testing.T) {
 	tests := []struct {
 		err    error
@@ -381,12 +605,19 @@ func TestMapServiceErrorToHTTPStatus(t *testing.T) {
 		{service.ErrExampleNotFound, 404},
 		{service.ErrInvalidExampleID, 400},
 		{service.ErrLimitMustBePositive, 400},
+		{service.ErrLimitMustBeNonNeg, 400},
 		{service.ErrOffsetMustBeNonNeg, 400},
 		{service.ErrRequestCannotBeNil, 400},
 		{service.ErrNameRequired, 400},
 		{service.ErrNameTooLong, 400},
 		{service.ErrDescriptionTooLong, 400},
 		{service.ErrValueCannotBeNeg, 400},
+		{service.ErrBatchTooLarge, 400},
+		{service.ErrBatchDuplicateID, 400},
+		{service.ErrBatchAborted, 424},
+		{service.ErrWebhookNotFound, 404},
+		{service.ErrWebhookURLInvalid, 400},
+		{service.ErrWebhookUnknownEventType, 400},
 		{service.ErrCreateExampleFailed, 500},
 		{errors.New("unknown"), 500},
 	}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-service-template/internal/idempotency"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	headerIdempotencyKey      = "Idempotency-Key"
+	headerIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyPollInterval   = 100 * time.Millisecond
+	idempotencyStoreOpTimeout = 5 * time.Second
+)
+
+// idempotencySkippedHeaders не сохраняются вместе с ответом: они описывают
+// конкретную передачу, а не результат запроса.
+var idempotencySkippedHeaders = map[string]struct{}{
+	fiber.HeaderContentLength: {},
+	fiber.HeaderDate:          {},
+	fiber.HeaderXRequestID:    {},
+	fiber.HeaderConnection:    {},
+}
+
+// idempotencyMiddleware обрабатывает заголовок Idempotency-Key у POST-запросов.
+// Первый запрос с ключом выполняется, его ответ (статус, заголовки, тело)
+// сохраняется на IDEMPOTENCY_TTL; повтор с тем же телом получает сохранённый
+// ответ с заголовком Idempotent-Replayed: true. Повтор, пока первый ещё
+// выполняется, ждёт до IDEMPOTENCY_WAIT_TIMEOUT и затем получает 409; тот же
+// ключ с другим телом — 422. Ответы 5xx не сохраняются: такой запрос можно
+// повторить. Ключи изолированы по principal из authMiddleware.
+func (s *Server) idempotencyMiddleware() fiber.Handler {
+	cfg := s.config.Idempotency
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(headerIdempotencyKey)
+		if s.idempotency == nil || c.Method() != fiber.MethodPost || key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Idempotency-Key cannot exceed 255 characters",
+			})
+		}
+
+		principal := principalFrom(c)
+		hash := idempotencyRequestHash(c)
+		ctx := c.UserContext()
+
+		record, acquired, err := s.idempotency.Acquire(ctx, principal, key, hash, cfg.LockTimeout, cfg.TTL)
+		if err == nil && !acquired && record.Response == nil && bytes.Equal(record.RequestHash, hash) {
+			record, acquired, err = s.waitIdempotent(ctx, principal, key, hash)
+		}
+		if err != nil {
+			s.logger.Error("Idempotency store failed", slog.String("error", err.Error()))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+				Error: "idempotency store is unavailable",
+			})
+		}
+
+		if !acquired {
+			switch {
+			case !bytes.Equal(record.RequestHash, hash):
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(models.ErrorResponse{
+					Error: "Idempotency-Key was already used with a different request",
+				})
+			case record.Response == nil:
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+					Error: "a request with this Idempotency-Key is still in progress",
+				})
+			default:
+				metrics.IdempotencyReplays.Add(1)
+				return replayIdempotent(c, record.Response)
+			}
+		}
+
+		return s.runIdempotent(c, principal, key)
+	}
+}
+
+// runIdempotent выполняет запрос, закрепивший ключ, и сохраняет ответ. Если
+// обработчик вернул ошибку, ответил 5xx или запаниковал, ключ освобождается.
+func (s *Server) runIdempotent(c *fiber.Ctx, principal, key string) (err error) {
+	// Запись в хранилище не должна прерываться вместе с запросом клиента,
+	// иначе ключ останется заблокированным до LockTimeout.
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), idempotencyStoreOpTimeout)
+	defer cancel()
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if releaseErr := s.idempotency.Release(storeCtx, principal, key); releaseErr != nil {
+			s.logger.Error("Failed to release idempotency key", slog.String("error", releaseErr.Error()))
+		}
+	}()
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	status := c.Response().StatusCode()
+	if status >= fiber.StatusInternalServerError {
+		return nil
+	}
+
+	resp := &idempotency.Response{
+		Status:  status,
+		Headers: make(map[string]string),
+		Body:    bytes.Clone(c.Response().Body()),
+	}
+	c.Response().Header.VisitAll(func(k, v []byte) {
+		name := string(k)
+		if _, skip := idempotencySkippedHeaders[name]; !skip {
+			resp.Headers[name] = string(v)
+		}
+	})
+
+	if err := s.idempotency.Complete(storeCtx, principal, key, resp); err != nil {
+		// Ответ клиенту уже готов; без сохранения повтор просто выполнится заново.
+		s.logger.Error("Failed to store idempotent response", slog.String("error", err.Error()))
+		return nil
+	}
+	completed = true
+
+	return nil
+}
+
+// waitIdempotent опрашивает ключ, пока первый запрос не завершится или не
+// истечёт IDEMPOTENCY_WAIT_TIMEOUT.
+func (s *Server) waitIdempotent(ctx context.Context, principal, key string, hash []byte) (*idempotency.Record, bool, error) {
+	cfg := s.config.Idempotency
+	deadline := time.NewTimer(cfg.WaitTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(idempotencyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-deadline.C:
+			return &idempotency.Record{RequestHash: hash}, false, nil
+		case <-ticker.C:
+		}
+
+		record, acquired, err := s.idempotency.Acquire(ctx, principal, key, hash, cfg.LockTimeout, cfg.TTL)
+		if err != nil || acquired || record.Response != nil {
+			return record, acquired, err
+		}
+	}
+}
+
+func replayIdempotent(c *fiber.Ctx, resp *idempotency.Response) error {
+	for name, value := range resp.Headers {
+		c.Set(name, value)
+	}
+	c.Set(headerIdempotentReplayed, "true")
+	return c.Status(resp.Status).Send(resp.Body)
+}
+
+// idempotencyRequestHash — отпечаток запроса: метод, путь с query и тело.
+func idempotencyRequestHash(c *fiber.Ctx) []byte {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.TrimSpace(c.OriginalURL())))
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return h.Sum(nil)
+}
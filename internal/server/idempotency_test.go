@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/idempotency"
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func newIdempotentTestServer(mock *mockExampleService, waitTimeout time.Duration) *Server {
+	return newTestServer(&service.Services{Example: mock},
+		WithIdempotency(idempotency.NewMemoryStore()),
+		withTestConfig(func(cfg *config.Config) {
+			cfg.Idempotency = config.IdempotencyConfig{TTL: time.Hour, WaitTimeout: waitTimeout, LockTimeout: time.Minute}
+		}))
+}
+
+func doIdempotentRequest(s *Server, key, body string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/examples", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	resp, _ := s.app.Test(req, -1)
+	return resp
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	var calls atomic.Int32
+	mock := &mockExampleService{
+		createFn: func(_ context.Context, req *models.ExampleRequest) (*models.Example, error) {
+			return &models.Example{ID: int(calls.Add(1)), Name: req.Name}, nil
+		},
+	}
+	s := newIdempotentTestServer(mock, 0)
+
+	first := doIdempotentRequest(s, "key-1", `{"name":"a"}`)
+	replay := doIdempotentRequest(s, "key-1", `{"name":"a"}`)
+
+	if first.StatusCode != http.StatusCreated || replay.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 twice, got %d and %d", first.StatusCode, replay.StatusCode)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls.Load())
+	}
+	if replay.Header.Get("Idempotent-Replayed") != "true" || replay.Header.Get("Content-Type") != first.Header.Get("Content-Type") {
+		t.Fatalf("unexpected replay headers: %v", replay.Header)
+	}
+	if body := decodeJSON[models.Example](t, replay); body.ID != 1 {
+		t.Fatalf("expected replayed example 1, got %+v", body)
+	}
+
+	if resp := doIdempotentRequest(s, "key-2", `{"name":"a"}`); resp.StatusCode != http.StatusCreated || calls.Load() != 2 {
+		t.Fatalf("expected a new key to run the handler, status %d calls %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	mock := &mockExampleService{
+		createFn: func(_ context.Context, req *models.ExampleRequest) (*models.Example, error) {
+			return &models.Example{ID: 1, Name: req.Name}, nil
+		},
+	}
+	s := newIdempotentTestServer(mock, 0)
+
+	doIdempotentRequest(s, "key", `{"name":"a"}`)
+	resp := doIdempotentRequest(s, "key", `{"name":"b"}`)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	mock := &mockExampleService{
+		createFn: func(context.Context, *models.ExampleRequest) (*models.Example, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New("db down")
+			}
+			return &models.Example{ID: 1}, nil
+		},
+	}
+	s := newIdempotentTestServer(mock, 0)
+
+	if resp := doIdempotentRequest(s, "key", `{"name":"a"}`); resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+	if resp := doIdempotentRequest(s, "key", `{"name":"a"}`); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected retry after 500 to run again, got %d", resp.StatusCode)
+	}
+}
+
+func TestIdempotency_ConcurrentDuplicate(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mock := &mockExampleService{
+		createFn: func(context.Context, *models.ExampleRequest) (*models.Example, error) {
+			close(started)
+			<-release
+			return &models.Example{ID: 1}, nil
+		},
+	}
+
+	t.Run("conflict without waiting", func(t *testing.T) {
+		s := newIdempotentTestServer(mock, 0)
+
+		var wg sync.WaitGroup
+		wg.Go(func() { doIdempotentRequest(s, "key", `{"name":"a"}`) })
+		<-started
+
+		resp := doIdempotentRequest(s, "key", `{"name":"a"}`)
+		close(release)
+		wg.Wait()
+
+		if resp.StatusCode != http.StatusConflict || resp.Header.Get("Retry-After") == "" {
+			t.Fatalf("expected 409 with Retry-After, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("waits for the first request", func(t *testing.T) {
+		started = make(chan struct{})
+		release = make(chan struct{})
+		s := newIdempotentTestServer(mock, 5*time.Second)
+
+		var wg sync.WaitGroup
+		wg.Go(func() { doIdempotentRequest(s, "key", `{"name":"a"}`) })
+		<-started
+		time.AfterFunc(150*time.Millisecond, func() { close(release) })
+
+		resp := doIdempotentRequest(s, "key", `{"name":"a"}`)
+		wg.Wait()
+
+		if resp.StatusCode != http.StatusCreated || resp.Header.Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("expected replayed 201, got %d", resp.StatusCode)
+		}
+	})
+}
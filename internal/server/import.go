@@ -0,0 +1,102 @@
+package server
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// importExamples загружает записи из CSV или NDJSON
+// @Summary Import examples
+// @Description Validates each CSV or NDJSON row with the same rules as create and upserts it by external_key, committing in chunks. Invalid rows are skipped and listed in the report. With dry_run=true only validation is performed. The format is taken from the format parameter or the Content-Type header. The body is limited by SERVER_BODY_LIMIT; use the CLI subcommand for larger files. With async=true the import runs in the background and the report becomes the operation result.
+// @Tags examples
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Input format" Enums(csv, ndjson)
+// @Param dry_run query bool false "Validate only, do not write"
+// @Param chunk_size query int false "Rows per transaction" default(500)
+// @Param async query bool false "Run as a background operation: respond 202 Accepted with the operation and a Location header to poll"
+// @Success 200 {object} models.ImportReport
+// @Success 202 {object} models.Operation "Import started"
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters or CSV header"
+// @Router /examples/import [post]
+func (s *Server) importExamples(c *fiber.Ctx) error {
+	format := c.Query("format")
+	if format == "" {
+		format = importFormatFromContentType(c.Get(fiber.HeaderContentType))
+	}
+
+	opts := models.ImportOptions{Format: format}
+	if v := c.Query("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid dry_run parameter",
+			})
+		}
+		opts.DryRun = dryRun
+	}
+	if v := c.Query("chunk_size"); v != "" {
+		chunkSize, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid chunk_size parameter",
+			})
+		}
+		opts.ChunkSize = chunkSize
+	}
+
+	if v := c.Query("async"); v != "" {
+		async, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid async parameter",
+			})
+		}
+		if async {
+			return s.importExamplesAsync(c, opts)
+		}
+	}
+
+	report, err := s.services.Example.ImportExamples(c.UserContext(), bytes.NewReader(c.Body()), opts)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(report)
+}
+
+// importExamplesAsync запускает импорт фоновой операцией. Тело копируется:
+// fasthttp переиспользует буфер запроса.
+func (s *Server) importExamplesAsync(c *fiber.Ctx, opts models.ImportOptions) error {
+	if s.services.Operations == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Asynchronous import is not available",
+		})
+	}
+
+	op, err := s.services.Operations.StartImport(c.UserContext(), bytes.Clone(c.Body()), opts)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return acceptOperation(c, op)
+}
+
+// importFormatFromContentType определяет формат по Content-Type; по
+// умолчанию — CSV.
+func importFormatFromContentType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return service.ImportFormatNDJSON
+	default:
+		return service.ImportFormatCSV
+	}
+}
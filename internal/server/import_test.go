@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestImportExamples(t *testing.T) {
+	t.Run("format from content type and options", func(t *testing.T) {
+		mock := &mockExampleService{
+			importFn: func(_ context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+				body, _ := io.ReadAll(r)
+				if opts.Format != service.ImportFormatNDJSON || !opts.DryRun || opts.ChunkSize != 10 || len(body) == 0 {
+					t.Errorf("unexpected options %+v, body %q", opts, body)
+				}
+				return &models.ImportReport{DryRun: true, Total: 1, Valid: 1}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/import?dry_run=true&chunk_size=10",
+			strings.NewReader(`{"external_key":"a","name":"x"}`))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		resp, _ := s.app.Test(req, -1)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if report := decodeJSON[models.ImportReport](t, resp); report.Valid != 1 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		mock := &mockExampleService{
+			importFn: func(context.Context, io.Reader, models.ImportOptions) (*models.ImportReport, error) {
+				return nil, service.ErrImportInvalidHeader
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/import", strings.NewReader("a,b"))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, _ := s.app.Test(req, -1)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid dry_run", func(t *testing.T) {
+		s := newTestServer(&service.Services{})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/import?dry_run=maybe", strings.NewReader(""))
+		resp, _ := s.app.Test(req, -1)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
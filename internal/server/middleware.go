@@ -1,8 +1,14 @@
 package server
 
 import (
+	"crypto/subtle"
+	"errors"
 	"time"
 
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,9 +21,67 @@ import (
 //	if !valid(token) {
 //	    return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Error: "unauthorized"})
 //	}
+//
+// Аутентифицированный субъект кладётся в Locals под localsPrincipal: по нему
+// изолируются ключи Idempotency-Key и другие данные клиента.
 func (s *Server) authMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// TODO: аутентифицировать запрос до попадания в обработчики.
+		c.Locals(localsPrincipal, anonymousPrincipal)
+		return c.Next()
+	}
+}
+
+const (
+	localsPrincipal    = "principal"
+	anonymousPrincipal = "anonymous"
+)
+
+// principalFrom возвращает субъект, установленный authMiddleware.
+func principalFrom(c *fiber.Ctx) string {
+	if principal, ok := c.Locals(localsPrincipal).(string); ok && principal != "" {
+		return principal
+	}
+	return anonymousPrincipal
+}
+
+// actorMiddleware передаёт сервисам, кто выполняет запрос: субъект из
+// authMiddleware, X-Request-ID и IP клиента попадают в журнал аудита.
+func (s *Server) actorMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+		c.SetUserContext(service.WithActor(c.UserContext(), service.Actor{
+			Principal: principalFrom(c),
+			RequestID: requestID,
+			ClientIP:  c.IP(),
+		}))
+		return c.Next()
+	}
+}
+
+// adminAuthMiddleware закрывает /debug/* на admin-листенере bearer-токеном
+// ADMIN_TOKEN. Без токена пропускает запросы: конфигурация не даст включить
+// pprof без него, а остальное защищено привязкой листенера к 127.0.0.1.
+func (s *Server) adminAuthMiddleware() fiber.Handler {
+	expected := []byte("Bearer " + s.config.Admin.Token)
+
+	return func(c *fiber.Ctx) error {
+		if s.config.Admin.Token == "" {
+			// Без токена /debug/* открыты только на loopback: листенер на
+			// внешнем адресе нужен пробам, но не должен давать менять уровень
+			// логов и читать конфигурацию кому угодно.
+			if s.config.AdminLoopback() {
+				return c.Next()
+			}
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error: "ADMIN_TOKEN is required for /debug on a non-loopback ADMIN_HOST",
+			})
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Error: "unauthorized"})
+		}
+
 		return c.Next()
 	}
 }
@@ -42,3 +106,22 @@ func (s *Server) accessLogMiddleware() fiber.Handler {
 		return err
 	}
 }
+
+// metricsMiddleware считает запросы публичного API и число запросов в обработке.
+func (s *Server) metricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		metrics.HTTPInFlight.Add(1)
+		defer metrics.HTTPInFlight.Add(-1)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+		metrics.ObserveHTTPRequest(status)
+
+		return err
+	}
+}
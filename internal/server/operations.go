@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"log/slog"
+	"strconv"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getOperation отдаёт состояние асинхронной операции
+// @Summary Get operation
+// @Description Returns the status of an asynchronous operation started with 202 Accepted: pending, running, succeeded, failed or cancelled. progress counts processed units (import rows, deleted examples); result is set when the operation finishes (partial for a cancelled one), error when it fails.
+// @Tags operations
+// @Produce json
+// @Param id path int true "Operation ID"
+// @Success 200 {object} models.Operation
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Operation not found"
+// @Router /operations/{id} [get]
+func (s *Server) getOperation(c *fiber.Ctx) error {
+	id, err := parseOperationID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	op, err := s.services.Operations.GetOperation(c.UserContext(), id)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(op)
+}
+
+// getOperationOutput отдаёт файл, созданный операцией
+// @Summary Download operation output
+// @Description Downloads the file produced by a succeeded operation — the result of an asynchronous export (GET /examples/export?async=true). The file is stored gzip-compressed (at most 16 MiB) and sent as is to clients with Accept-Encoding: gzip, decompressed otherwise. It is kept until the operation is deleted after OPERATIONS_RETENTION.
+// @Tags operations
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param id path int true "Operation ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Operation not found or produces no file"
+// @Failure 409 {object} models.ErrorResponse "Operation has not succeeded yet"
+// @Router /operations/{id}/output [get]
+func (s *Server) getOperationOutput(c *fiber.Ctx) error {
+	id, err := parseOperationID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	output, err := s.services.Operations.GetOperationOutput(c.UserContext(), id)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, exportContentTypes[output.Format])
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="examples-`+strconv.FormatInt(id, 10)+`.`+output.Format+`"`)
+	c.Set(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+	if c.Context().Request.Header.HasAcceptEncoding("gzip") {
+		c.Set(fiber.HeaderContentEncoding, "gzip")
+		return c.Send(output.Data)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(output.Data))
+	if err != nil {
+		s.logger.Error("Failed to read operation output", slog.Int64("id", id), slog.String("error", err.Error()))
+		return s.handleServiceError(c, service.ErrGetOperationFailed)
+	}
+	return c.SendStream(gz)
+}
+
+// cancelOperation отменяет асинхронную операцию
+// @Summary Cancel operation
+// @Description Cancels an operation. A pending operation is cancelled at once (200). For a running one cancellation is requested (202): the worker observes it through its context within a second or two and stops; poll the operation until its status becomes cancelled. Work already committed is not rolled back.
+// @Tags operations
+// @Produce json
+// @Param id path int true "Operation ID"
+// @Success 200 {object} models.Operation "Cancelled"
+// @Success 202 {object} models.Operation "Cancellation requested"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Operation not found"
+// @Failure 409 {object} models.ErrorResponse "Operation has already finished"
+// @Router /operations/{id} [delete]
+func (s *Server) cancelOperation(c *fiber.Ctx) error {
+	id, err := parseOperationID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	op, err := s.services.Operations.CancelOperation(c.UserContext(), id)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	status := fiber.StatusOK
+	if op.CancelRequested && op.FinishedAt == nil {
+		status = fiber.StatusAccepted
+	}
+	return c.Status(status).JSON(op)
+}
+
+// purgeExamples запускает удаление записей по условию
+// @Summary Purge examples
+// @Description Starts an asynchronous deletion of all examples matching the conditions (at least one is required). Examples are deleted in chunks, each recorded in the audit log and the outbox like a single delete. Returns 202 Accepted with the operation; poll the Location header URL for progress and the number of deleted examples.
+// @Tags examples
+// @Accept json
+// @Produce json
+// @Param purge body models.PurgeRequest true "Conditions"
+// @Success 202 {object} models.Operation
+// @Header 202 {string} Location "/api/v1/operations/{id}"
+// @Failure 400 {object} models.ErrorResponse "Invalid conditions"
+// @Router /examples:purge [post]
+func (s *Server) purgeExamples(c *fiber.Ctx) error {
+	var req models.PurgeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	op, err := s.services.Operations.StartPurge(c.UserContext(), models.ExampleFilter{
+		IsActive:      req.IsActive,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	})
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return acceptOperation(c, op)
+}
+
+// acceptOperation отвечает 202 Accepted со ссылкой на запущенную операцию.
+func acceptOperation(c *fiber.Ctx, op *models.Operation) error {
+	c.Location("/api/v1/operations/" + strconv.FormatInt(op.ID, 10))
+	return c.Status(fiber.StatusAccepted).JSON(op)
+}
+
+func parseOperationID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid operation ID")
+	}
+	return id, nil
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+type mockOperationService struct {
+	getFn    func(ctx context.Context, id int64) (*models.Operation, error)
+	cancelFn func(ctx context.Context, id int64) (*models.Operation, error)
+	importFn func(ctx context.Context, body []byte, opts models.ImportOptions) (*models.Operation, error)
+	purgeFn  func(ctx context.Context, filter models.ExampleFilter) (*models.Operation, error)
+	exportFn func(ctx context.Context, filter models.ExampleFilter, format string) (*models.Operation, error)
+	outputFn func(ctx context.Context, id int64) (*models.OperationOutput, error)
+}
+
+func (m *mockOperationService) StartExport(ctx context.Context, filter models.ExampleFilter, format string) (*models.Operation, error) {
+	return m.exportFn(ctx, filter, format)
+}
+
+func (m *mockOperationService) GetOperationOutput(ctx context.Context, id int64) (*models.OperationOutput, error) {
+	return m.outputFn(ctx, id)
+}
+
+func (m *mockOperationService) GetOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	return m.getFn(ctx, id)
+}
+
+func (m *mockOperationService) CancelOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	return m.cancelFn(ctx, id)
+}
+
+func (m *mockOperationService) StartImport(ctx context.Context, body []byte, opts models.ImportOptions) (*models.Operation, error) {
+	return m.importFn(ctx, body, opts)
+}
+
+func (m *mockOperationService) StartPurge(ctx context.Context, filter models.ExampleFilter) (*models.Operation, error) {
+	return m.purgeFn(ctx, filter)
+}
+
+func (m *mockOperationService) RunOperation(context.Context, int64) error { return nil }
+
+func TestStartOperations(t *testing.T) {
+	t.Run("purge", func(t *testing.T) {
+		var got models.ExampleFilter
+		s := newTestServer(&service.Services{Operations: &mockOperationService{
+			purgeFn: func(_ context.Context, filter models.ExampleFilter) (*models.Operation, error) {
+				got = filter
+				return &models.Operation{ID: 7, Kind: service.OperationPurgeExamples, Status: service.OperationPending}, nil
+			},
+		}})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples:purge", map[string]any{"is_active": false})
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", resp.StatusCode)
+		}
+		if loc := resp.Header.Get("Location"); loc != "/api/v1/operations/7" {
+			t.Errorf("unexpected Location %q", loc)
+		}
+		if got.IsActive == nil || *got.IsActive {
+			t.Errorf("unexpected filter %+v", got)
+		}
+	})
+
+	t.Run("purge without conditions", func(t *testing.T) {
+		s := newTestServer(&service.Services{Operations: &mockOperationService{
+			purgeFn: func(context.Context, models.ExampleFilter) (*models.Operation, error) {
+				return nil, service.ErrPurgeFilterRequired
+			},
+		}})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples:purge", map[string]any{})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("async import", func(t *testing.T) {
+		var body string
+		var opts models.ImportOptions
+		s := newTestServer(&service.Services{Operations: &mockOperationService{
+			importFn: func(_ context.Context, b []byte, o models.ImportOptions) (*models.Operation, error) {
+				body, opts = string(b), o
+				return &models.Operation{ID: 3, Kind: service.OperationImportExamples, Status: service.OperationPending}, nil
+			},
+		}})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/import?async=true&dry_run=true",
+			strings.NewReader("name,value\na,1\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, _ := s.app.Test(req, -1)
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", resp.StatusCode)
+		}
+		if loc := resp.Header.Get("Location"); loc != "/api/v1/operations/3" {
+			t.Errorf("unexpected Location %q", loc)
+		}
+		if body != "name,value\na,1\n" || opts.Format != service.ImportFormatCSV || !opts.DryRun {
+			t.Errorf("unexpected import %q with options %+v", body, opts)
+		}
+	})
+}
+
+func TestExportExamplesAsync(t *testing.T) {
+	var gotFilter models.ExampleFilter
+	var gotFormat string
+	s := newTestServer(&service.Services{Operations: &mockOperationService{
+		exportFn: func(_ context.Context, filter models.ExampleFilter, format string) (*models.Operation, error) {
+			gotFilter, gotFormat = filter, format
+			return &models.Operation{ID: 5, Kind: service.OperationExportExamples, Status: service.OperationPending}, nil
+		},
+	}})
+
+	resp := doRequest(s, http.MethodGet, "/api/v1/examples/export?format=ndjson&is_active=true&async=true", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/api/v1/operations/5" {
+		t.Errorf("unexpected Location %q", loc)
+	}
+	if gotFormat != service.ExportFormatNDJSON || gotFilter.IsActive == nil || !*gotFilter.IsActive {
+		t.Errorf("unexpected export %s with filter %+v", gotFormat, gotFilter)
+	}
+
+	if resp := doRequest(s, http.MethodGet, "/api/v1/examples/export?async=maybe", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid async, got %d", resp.StatusCode)
+	}
+}
+
+func TestGetOperationOutput(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte("id,name\n1,a\n"))
+	_ = gz.Close()
+
+	s := newTestServer(&service.Services{Operations: &mockOperationService{
+		outputFn: func(_ context.Context, id int64) (*models.OperationOutput, error) {
+			switch id {
+			case 1:
+				return &models.OperationOutput{Format: service.ExportFormatCSV, Data: buf.Bytes()}, nil
+			case 2:
+				return nil, service.ErrOperationOutputNotReady
+			default:
+				return nil, service.ErrOperationHasNoOutput
+			}
+		},
+	}})
+
+	t.Run("plain", func(t *testing.T) {
+		resp := doRequest(s, http.MethodGet, "/api/v1/operations/1/output", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Errorf("unexpected Content-Type %q", ct)
+		}
+		if cd := resp.Header.Get("Content-Disposition"); !strings.Contains(cd, "examples-1.csv") {
+			t.Errorf("unexpected Content-Disposition %q", cd)
+		}
+		if body, _ := io.ReadAll(resp.Body); string(body) != "id,name\n1,a\n" {
+			t.Errorf("unexpected body %q", body)
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/operations/1/output", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, _ := s.app.Test(req, -1)
+		if resp.Header.Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected gzip encoding, got %q", resp.Header.Get("Content-Encoding"))
+		}
+		if body, _ := io.ReadAll(resp.Body); !bytes.Equal(body, buf.Bytes()) {
+			t.Errorf("expected stored gzip bytes to be sent as-is")
+		}
+	})
+
+	if resp := doRequest(s, http.MethodGet, "/api/v1/operations/2/output", nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(s, http.MethodGet, "/api/v1/operations/3/output", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestGetOperation(t *testing.T) {
+	s := newTestServer(&service.Services{Operations: &mockOperationService{
+		getFn: func(_ context.Context, id int64) (*models.Operation, error) {
+			if id != 7 {
+				return nil, service.ErrOperationNotFound
+			}
+			return &models.Operation{ID: 7, Status: service.OperationRunning, Progress: 1500}, nil
+		},
+	}})
+
+	resp := doRequest(s, http.MethodGet, "/api/v1/operations/7", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if op := decodeJSON[models.Operation](t, resp); op.Progress != 1500 {
+		t.Errorf("unexpected operation %+v", op)
+	}
+
+	if resp := doRequest(s, http.MethodGet, "/api/v1/operations/8", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(s, http.MethodGet, "/api/v1/operations/abc", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestCancelOperation(t *testing.T) {
+	finishedAt := time.Now()
+	tests := []struct {
+		name   string
+		op     *models.Operation
+		err    error
+		status int
+	}{
+		{"pending", &models.Operation{ID: 1, Status: service.OperationCancelled, FinishedAt: &finishedAt}, nil, http.StatusOK},
+		{"running", &models.Operation{ID: 1, Status: service.OperationRunning, CancelRequested: true}, nil, http.StatusAccepted},
+		{"finished", nil, service.ErrOperationFinished, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(&service.Services{Operations: &mockOperationService{
+				cancelFn: func(context.Context, int64) (*models.Operation, error) {
+					return tt.op, tt.err
+				},
+			}})
+
+			resp := doRequest(s, http.MethodDelete, "/api/v1/operations/1", nil)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// setupPprofRoutes монтирует net/http/pprof и диагностику рантайма в группу
+// /debug admin-приложения. Группа уже закрыта adminAuthMiddleware.
+//
+//	/debug/pprof/                  индекс профилей
+//	/debug/pprof/{heap,goroutine,block,mutex,allocs,threadcreate}
+//	/debug/pprof/profile?seconds=N CPU-профиль
+//	/debug/pprof/trace?seconds=N   execution trace
+//	/debug/goroutines              текстовый дамп стеков всех горутин
+func (s *Server) setupPprofRoutes(debug fiber.Router) {
+	debug.Get("/goroutines", s.goroutineDump)
+
+	pp := debug.Group("/pprof")
+	pp.Get("/cmdline", adaptor.HTTPHandlerFunc(pprof.Cmdline))
+	pp.Get("/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	pp.Post("/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	pp.Get("/profile", s.limitProfileDuration, adaptor.HTTPHandlerFunc(pprof.Profile))
+	pp.Get("/trace", s.limitProfileDuration, adaptor.HTTPHandlerFunc(pprof.Trace))
+	// Index сам отдаёт именованные профили по пути /debug/pprof/<name>.
+	pp.Get("/*", adaptor.HTTPHandlerFunc(pprof.Index))
+}
+
+// limitProfileDuration не даёт запросить CPU-профиль или trace дольше
+// ADMIN_PROFILE_MAX_DURATION: такие запросы держат соединение и грузят процесс.
+func (s *Server) limitProfileDuration(c *fiber.Ctx) error {
+	secondsStr := c.Query("seconds")
+	if secondsStr == "" {
+		return c.Next()
+	}
+
+	seconds, err := strconv.ParseFloat(secondsStr, 64)
+	if err != nil || seconds <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid seconds parameter",
+		})
+	}
+	if seconds > s.config.Admin.ProfileMaxDuration.Seconds() {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "seconds exceeds " + s.config.Admin.ProfileMaxDuration.String(),
+		})
+	}
+
+	return c.Next()
+}
+
+func (s *Server) goroutineDump(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	c.Set("X-Goroutine-Count", strconv.Itoa(runtime.NumGoroutine()))
+	return rpprof.Lookup("goroutine").WriteTo(c, 2)
+}
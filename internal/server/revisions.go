@@ -0,0 +1,171 @@
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getExampleAsOf отдаёт запись в состоянии на момент as_of (см. getExample).
+// Валидаторы кеша не выставляются: историческая версия не сравнима с
+// текущей по ETag.
+func (s *Server) getExampleAsOf(c *fiber.Ctx, id int) error {
+	asOf, err := time.Parse(time.RFC3339, c.Query("as_of"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid as_of parameter: expected RFC 3339",
+		})
+	}
+
+	example, err := s.services.Example.GetExampleAsOf(c.UserContext(), id, asOf)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(example)
+}
+
+// getExampleRevisions отдаёт историю версий записи
+// @Summary Get example revisions
+// @Description Returns the revision history of an example, newest first. A revision is recorded for every create, update and delete, including batch operations and imports; the history outlives deletion of the example.
+// @Tags examples
+// @Produce json
+// @Param id path int true "Example ID"
+// @Param limit query int false "Number of revisions (max 100)" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.ExampleRevisionResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} models.ErrorResponse "Example never existed"
+// @Router /examples/{id}/revisions [get]
+func (s *Server) getExampleRevisions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid example ID",
+		})
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid limit parameter",
+		})
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid offset parameter",
+		})
+	}
+
+	revisions, err := s.services.Example.GetExampleRevisions(c.UserContext(), id, limit, offset)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.ExampleRevisionResponse{
+		Data: revisions,
+	})
+}
+
+// getExampleRevision отдаёт одну версию записи
+// @Summary Get example revision
+// @Description Returns a single revision of an example.
+// @Tags examples
+// @Produce json
+// @Param id path int true "Example ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.ExampleRevision
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} models.ErrorResponse "Revision not found"
+// @Router /examples/{id}/revisions/{rev} [get]
+func (s *Server) getExampleRevision(c *fiber.Ctx) error {
+	id, rev, err := parseRevisionParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	revision, err := s.services.Example.GetExampleRevision(c.UserContext(), id, rev)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(revision)
+}
+
+// restoreExampleRevision восстанавливает запись из версии
+// @Summary Restore example revision
+// @Description Overwrites name, description, value and is_active of an existing example with the values from the given revision. The restore is an ordinary update: it is audited, published to the outbox and recorded as a new revision. Deleted examples cannot be restored; restoring a "deleted" revision returns 409.
+// @Tags examples
+// @Produce json
+// @Param id path int true "Example ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.Example
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} models.ErrorResponse "Example or revision not found"
+// @Failure 409 {object} models.ErrorResponse "Revision records a deletion"
+// @Router /examples/{id}/revisions/{rev}/restore [post]
+func (s *Server) restoreExampleRevision(c *fiber.Ctx) error {
+	id, rev, err := parseRevisionParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	example, err := s.services.Example.RestoreExampleRevision(c.UserContext(), id, rev)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(example)
+}
+
+// diffExampleRevisions сравнивает две версии записи
+// @Summary Diff example revisions
+// @Description Returns the fields whose values differ between two revisions of an example (id, created_at and updated_at are not compared). A missing field is reported as null.
+// @Tags examples
+// @Produce json
+// @Param id path int true "Example ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Compared revision"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} models.ErrorResponse "Revision not found"
+// @Router /examples/{id}/revisions/diff [get]
+func (s *Server) diffExampleRevisions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid example ID",
+		})
+	}
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "from and to must be revision numbers",
+		})
+	}
+
+	diff, err := s.services.Example.DiffExampleRevisions(c.UserContext(), id, from, to)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(diff)
+}
+
+func parseRevisionParams(c *fiber.Ctx) (id, rev int, err error) {
+	if id, err = strconv.Atoi(c.Params("id")); err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "Invalid example ID")
+	}
+	if rev, err = strconv.Atoi(c.Params("rev")); err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "Invalid revision")
+	}
+	return id, rev, nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestRevisionRoutes(t *testing.T) {
+	t.Run("diff is not a revision number", func(t *testing.T) {
+		var from, to int
+		mock := &mockExampleService{
+			diffFn: func(_ context.Context, _ int, f, tt int) (*models.RevisionDiff, error) {
+				from, to = f, tt
+				return &models.RevisionDiff{ExampleID: 1, From: f, To: tt}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/1/revisions/diff?from=1&to=3", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if from != 1 || to != 3 {
+			t.Errorf("expected diff 1..3, got %d..%d", from, to)
+		}
+	})
+
+	t.Run("single revision", func(t *testing.T) {
+		mock := &mockExampleService{
+			revisionFn: func(_ context.Context, id, rev int) (*models.ExampleRevision, error) {
+				return &models.ExampleRevision{ExampleID: id, Revision: rev, Op: service.RevisionUpdated}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/1/revisions/2", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if got := decodeJSON[models.ExampleRevision](t, resp); got.Revision != 2 {
+			t.Errorf("expected revision 2, got %+v", got)
+		}
+	})
+
+	t.Run("restore deleted revision", func(t *testing.T) {
+		mock := &mockExampleService{
+			restoreFn: func(context.Context, int, int) (*models.Example, error) {
+				return nil, service.ErrRestoreDeletedRevision
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/examples/1/revisions/3/restore", nil)
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestGetExampleAsOf(t *testing.T) {
+	t.Run("reads history", func(t *testing.T) {
+		var got time.Time
+		mock := &mockExampleService{
+			asOfFn: func(_ context.Context, id int, asOf time.Time) (*models.Example, error) {
+				got = asOf
+				return &models.Example{ID: id}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/1?as_of=2026-01-02T03:04:05Z", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if !got.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("unexpected as_of %v", got)
+		}
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		s := newTestServer(&service.Services{})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/1?as_of=yesterday", nil)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// searchExamples ищет записи по name и description
+// @Summary Search examples
+// @Description Full-text search over name and description, most relevant first. In fulltext mode q uses web search syntax: quoted phrases, "or" and "-" to exclude a word; words are matched by their stems in the DB_SEARCH_LANGUAGE configuration, and name matches rank above description matches. In fuzzy mode examples are matched by trigram similarity, which tolerates typos. name_highlight and snippet wrap matched words in <mark></mark>; the rest of the text is not HTML-escaped.
+// @Tags examples
+// @Produce json
+// @Param q query string true "Search query"
+// @Param mode query string false "Search mode" Enums(fulltext, fuzzy) default(fulltext)
+// @Param limit query int false "Number of records (max 100)" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Param is_active query bool false "Filter by is_active"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Success 200 {object} models.ExampleSearchResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Router /examples/search [get]
+func (s *Server) searchExamples(c *fiber.Ctx) error {
+	filter, err := parseExampleFilter(c, "10")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	results, err := s.services.Example.SearchExamples(c.UserContext(), models.ExampleSearchQuery{
+		Filter: filter,
+		Query:  c.Query("q"),
+		Mode:   c.Query("mode"),
+	})
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.ExampleSearchResponse{
+		Data: results,
+	})
+}
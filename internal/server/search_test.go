@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestSearchExamples(t *testing.T) {
+	t.Run("query parameters", func(t *testing.T) {
+		var got models.ExampleSearchQuery
+		mock := &mockExampleService{
+			searchFn: func(_ context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+				got = query
+				return []models.ExampleSearchResult{{
+					Example:       models.Example{ID: 7, Name: "Red apple"},
+					Rank:          0.6,
+					NameHighlight: "Red <mark>apple</mark>",
+				}}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/search?q=apple%20-green&mode=fuzzy&is_active=true", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if got.Query != "apple -green" || got.Mode != "fuzzy" || got.Filter.Limit != 10 || got.Filter.IsActive == nil {
+			t.Errorf("unexpected query %+v", got)
+		}
+		body := decodeJSON[models.ExampleSearchResponse](t, resp)
+		if len(body.Data) != 1 || body.Data[0].ID != 7 || body.Data[0].NameHighlight != "Red <mark>apple</mark>" {
+			t.Errorf("unexpected response %+v", body)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		mock := &mockExampleService{
+			searchFn: func(context.Context, models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+				return nil, service.ErrSearchQueryRequired
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		for _, path := range []string{
+			"/api/v1/examples/search?q=apple&limit=ten",
+			"/api/v1/examples/search",
+		} {
+			if resp := doRequest(s, http.MethodGet, path, nil); resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+}
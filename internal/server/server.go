@@ -2,13 +2,23 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"go-service-template/internal/config"
+	"go-service-template/internal/events"
+	"go-service-template/internal/health"
+	"go-service-template/internal/idempotency"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/scheduler"
 	"go-service-template/internal/service"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
@@ -23,14 +33,95 @@ type Server struct {
 	logger   *slog.Logger
 	config   *config.Config
 	app      *fiber.App
+	// admin — отдельное приложение для служебных эндпоинтов; nil, если
+	// admin-листенер отключён (ADMIN_PORT=0).
+	admin    *fiber.App
+	logLevel *slog.LevelVar
+	health   *health.Registry
+	// idempotency — хранилище ответов для Idempotency-Key; nil отключает
+	// поддержку заголовка.
+	idempotency idempotency.Store
+	// draining выставляется в начале остановки: /readyz сразу отвечает 503,
+	// пока листенеры ещё принимают трафик.
+	draining atomic.Bool
+	// events — источник потока /examples/stream; nil отключает поток.
+	events *events.Feed
+	// scheduler — планировщик периодических задач для admin-поверхности;
+	// nil убирает /debug/scheduler.
+	scheduler *scheduler.Scheduler
+	// streams — базовый контекст для ответов, которые пишутся после выхода из
+	// обработчика (экспорт); отменяется в Shutdown.
+	streams     context.Context
+	stopStreams context.CancelFunc
+	// subscriptions — базовый контекст бесконечных потоков (SSE, WebSocket). Отменяется
+	// в начале Shutdown: такие ответы сами не завершаются, и клиенты
+	// переподключаются к другой реплике с Last-Event-ID.
+	subscriptions     context.Context
+	stopSubscriptions context.CancelFunc
+	// activeExports, activeStreams и activeWebSockets — открытые потоковые
+	// ответы. Они пишутся после выхода из обработчика, поэтому в
+	// http_requests_in_flight не попадают и считаются здесь для итога Shutdown.
+	activeExports    atomic.Int64
+	activeStreams    atomic.Int64
+	activeWebSockets atomic.Int64
 }
 
-func New(services *service.Services, slogger *slog.Logger, cfg *config.Config) *Server {
-	return &Server{
+// Option настраивает необязательные зависимости сервера.
+type Option func(*Server)
+
+// WithLogLevel включает управление уровнем логов на admin-поверхности.
+func WithLogLevel(level *slog.LevelVar) Option {
+	return func(s *Server) {
+		s.logLevel = level
+	}
+}
+
+// WithHealth подключает реестр проверок для /readyz и /startupz. Без него
+// сервер проверяет только доступность БД и считается запущенным сразу.
+func WithHealth(registry *health.Registry) Option {
+	return func(s *Server) {
+		s.health = registry
+	}
+}
+
+// WithIdempotency включает поддержку заголовка Idempotency-Key для POST-запросов API.
+func WithIdempotency(store idempotency.Store) Option {
+	return func(s *Server) {
+		s.idempotency = store
+	}
+}
+
+// WithEvents включает поток изменений GET /api/v1/examples/stream.
+func WithEvents(feed *events.Feed) Option {
+	return func(s *Server) {
+		s.events = feed
+	}
+}
+
+// WithScheduler показывает состояние периодических задач на admin-поверхности.
+func WithScheduler(sched *scheduler.Scheduler) Option {
+	return func(s *Server) {
+		s.scheduler = sched
+	}
+}
+
+func New(services *service.Services, slogger *slog.Logger, cfg *config.Config, opts ...Option) *Server {
+	s := &Server{
 		services: services,
 		logger:   slogger,
 		config:   cfg,
 	}
+	s.streams, s.stopStreams = context.WithCancel(context.Background())
+	s.subscriptions, s.stopSubscriptions = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.health == nil {
+		s.health = health.NewRegistry()
+		s.health.Register(health.Check{Name: "database", Critical: true, Func: services.Ping})
+		s.health.MarkStarted()
+	}
+	return s
 }
 
 func (s *Server) setupRoutes() {
@@ -41,6 +132,14 @@ func (s *Server) setupRoutes() {
 		BodyLimit:    s.config.Server.BodyLimit,
 	})
 
+	// Служебные эндпоинты живут либо на отдельном admin-листенере, либо (если
+	// он отключён) на публичном приложении — как раньше.
+	probes := s.app
+	if s.config.AdminEnabled() {
+		s.setupAdminRoutes()
+		probes = s.admin
+	}
+
 	s.app.Use(recover.New())
 	s.app.Use(requestid.New(requestid.Config{
 		Header: "X-Request-ID",
@@ -56,54 +155,166 @@ func (s *Server) setupRoutes() {
 		}))
 	}
 	s.app.Use(s.accessLogMiddleware())
+	s.app.Use(s.metricsMiddleware())
 
-	// Swagger раскрывает всю поверхность API, поэтому закрыт флагом ENABLE_SWAGGER
-	// (по умолчанию выключен). Каталог docs/ генерируется на этапе сборки.
-	if s.config.App.EnableSwagger {
-		s.app.Static("/swagger/docs", "./docs")
-		s.app.Get("/swagger/*", swagger.New(swagger.Config{
-			URL: "/swagger/docs/swagger.json",
-		}))
-	}
-
-	// Пробы: /livez без зависимостей (для k8s livenessProbe); /readyz пингует
-	// базу (для k8s readinessProbe). /health оставлен как обратно совместимый
-	// алиас readiness.
-	s.app.Get("/livez", s.liveness)
-	s.app.Get("/readyz", s.readiness)
-	s.app.Get("/health", s.readiness)
+	s.setupProbeRoutes(probes)
 
 	api := s.app.Group("/api/v1")
 	// authMiddleware пока пропускает все запросы — замените на реальную аутентификацию.
 	api.Use(s.authMiddleware())
+	api.Use(s.actorMiddleware())
+	api.Use(s.idempotencyMiddleware())
+
+	// Двоеточие экранировано: ":batch" — часть пути, а не параметр.
+	api.Post("/examples\\:batch", s.batchExamples)
+	api.Get("/ws", s.websocketUpgrade, websocket.New(s.handleWebSocket, s.websocketConfig()))
 
 	examples := api.Group("/examples")
 	examples.Post("/", s.createExample)
-	examples.Get("/", s.getAllExamples)
-	examples.Get("/:id", s.getExample)
+	examples.Get("/", cacheControl(s.config.Server.ListCacheControl), s.getAllExamples)
+	// /export регистрируется до /:id, иначе "export" разберётся как ID.
+	examples.Get("/export", s.exportExamples)
+	examples.Get("/stats", s.getExampleStats)
+	examples.Get("/search", s.searchExamples)
+	examples.Get("/stream", s.streamExamples)
+	examples.Post("/import", s.importExamples)
+	examples.Get("/:id", cacheControl(s.config.Server.ExampleCacheControl), s.getExample)
+	examples.Get("/:id/revisions", s.getExampleRevisions)
+	// /diff регистрируется до /:rev по той же причине, что и /export.
+	examples.Get("/:id/revisions/diff", s.diffExampleRevisions)
+	examples.Get("/:id/revisions/:rev", s.getExampleRevision)
+	examples.Post("/:id/revisions/:rev/restore", s.restoreExampleRevision)
 	examples.Put("/:id", s.updateExample)
 	examples.Delete("/:id", s.deleteExample)
+
+	if s.services.Audit != nil {
+		api.Get("/audit", s.getAuditLog)
+	}
+
+	if s.services.Operations != nil {
+		api.Post("/examples\\:purge", s.purgeExamples)
+		operations := api.Group("/operations")
+		operations.Get("/:id", s.getOperation)
+		operations.Get("/:id/output", s.getOperationOutput)
+		operations.Delete("/:id", s.cancelOperation)
+	}
+
+	if s.services.Webhooks != nil {
+		webhooks := api.Group("/webhooks")
+		webhooks.Post("/", s.createWebhook)
+		webhooks.Get("/", s.getAllWebhooks)
+		webhooks.Get("/:id", s.getWebhook)
+		webhooks.Put("/:id", s.updateWebhook)
+		webhooks.Delete("/:id", s.deleteWebhook)
+		webhooks.Get("/:id/deliveries", s.getWebhookDeliveries)
+	}
 }
 
+// setupProbeRoutes регистрирует пробы и Swagger на переданном приложении.
+func (s *Server) setupProbeRoutes(app *fiber.App) {
+	// Swagger раскрывает всю поверхность API, поэтому закрыт флагом ENABLE_SWAGGER
+	// (по умолчанию выключен). Каталог docs/ генерируется на этапе сборки.
+	if s.config.App.EnableSwagger {
+		app.Static("/swagger/docs", "./docs")
+		app.Get("/swagger/*", swagger.New(swagger.Config{
+			URL: "/swagger/docs/swagger.json",
+		}))
+	}
+
+	// Пробы: /livez без зависимостей (для k8s livenessProbe); /readyz выполняет
+	// проверки из health-реестра (для k8s readinessProbe); /startupz ждёт
+	// миграций и прогрева (для k8s startupProbe). /health оставлен как обратно
+	// совместимый алиас readiness.
+	app.Get("/livez", s.liveness)
+	app.Get("/readyz", s.readiness)
+	app.Get("/startupz", s.startup)
+	app.Get("/health", s.readiness)
+}
+
+// Start поднимает публичный и (если включён) admin-листенеры и блокируется до
+// остановки публичного или ошибки любого из них. Штатная остановка admin не
+// завершает Start: публичный листенер в этот момент ещё может обслуживать
+// запросы.
 func (s *Server) Start(port string) error {
 	s.setupRoutes()
 
+	errCh := make(chan error, 2)
+
+	if s.admin != nil {
+		adminAddr := net.JoinHostPort(s.config.Admin.Host, strconv.Itoa(s.config.Admin.Port))
+		s.logger.Info("Starting admin server", slog.String("addr", adminAddr))
+		go func() {
+			if err := s.admin.Listen(adminAddr); err != nil {
+				errCh <- fmt.Errorf("admin listener: %w", err)
+			}
+		}()
+	}
+
 	addr := net.JoinHostPort(s.config.Server.Host, port)
 	s.logger.Info("Starting server",
 		slog.String("host", s.config.Server.Host),
 		slog.String("port", port),
 		slog.String("addr", addr),
 	)
+	go func() {
+		errCh <- s.app.Listen(addr)
+	}()
+
+	return <-errCh
+}
 
-	return s.app.Listen(addr)
+// BeginDrain переводит сервер в режим остановки: readiness начинает отвечать
+// 503, но запросы продолжают обслуживаться до вызова Shutdown.
+func (s *Server) BeginDrain() {
+	s.draining.Store(true)
 }
 
+// Draining сообщает, идёт ли остановка сервера.
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// Shutdown дренирует оба листенера: сначала публичный, затем admin, чтобы
+// пробы отвечали до последнего. Запросы, не завершившиеся до истечения ctx,
+// учитываются в метрике http_requests_dropped_on_shutdown_total. Потоковые
+// выгрузки дописываются в пределах того же бюджета, затем прерываются и
+// тоже считаются отброшенными; SSE- и WebSocket-подписки закрываются сразу,
+// их число пишется в итог отдельно.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down server...")
+	s.BeginDrain()
+	closedStreams, closedWebSockets := s.activeStreams.Load(), s.activeWebSockets.Load()
+	s.stopSubscriptions()
+
+	var errs []error
+	var dropped int64
+	if s.app != nil {
+		if err := s.app.ShutdownWithContext(ctx); err != nil {
+			dropped = metrics.HTTPInFlight.Value()
+			errs = append(errs, fmt.Errorf("public listener: %w", err))
+		}
+	}
+	dropped += s.activeExports.Load()
+	metrics.HTTPDroppedOnShutdown.Add(dropped)
+	s.stopStreams()
+	if s.admin != nil {
+		if err := s.admin.ShutdownWithContext(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("admin listener: %w", err))
+		}
+	}
 
-	if s.app == nil {
-		return nil
+	// Итог пишется всегда, в том числе с нулём: по нему видно, уложилась ли
+	// остановка в бюджет, без обращения к метрикам.
+	attrs := []any{
+		slog.Int64("dropped_requests", dropped),
+		slog.Int64("closed_streams", closedStreams),
+		slog.Int64("closed_websockets", closedWebSockets),
+	}
+	if dropped > 0 {
+		s.logger.Warn("Server stopped, requests dropped", attrs...)
+	} else {
+		s.logger.Info("Server stopped", attrs...)
 	}
 
-	return s.app.ShutdownWithContext(ctx)
+	return errors.Join(errs...)
 }
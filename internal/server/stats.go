@@ -0,0 +1,40 @@
+package server
+
+import (
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getExampleStats отдаёт агрегаты по value
+// @Summary Get example statistics
+// @Description Returns count, sum, average, minimum and maximum of value over examples matching the list filters: in total, per is_active and, when bucket is set, per time interval of created_at. Intervals are aligned to the given IANA time zone (weeks start on Monday); empty intervals are omitted.
+// @Tags examples
+// @Produce json
+// @Param is_active query bool false "Filter by is_active"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param bucket query string false "Group by interval of created_at" Enums(hour, day, week, month)
+// @Param timezone query string false "IANA time zone of the intervals" default(UTC)
+// @Success 200 {object} models.ExampleStats
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Router /examples/stats [get]
+func (s *Server) getExampleStats(c *fiber.Ctx) error {
+	filter, err := parseExampleFilter(c, "0")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	stats, err := s.services.Example.GetExampleStats(c.UserContext(), models.ExampleStatsQuery{
+		Filter:   filter,
+		Bucket:   c.Query("bucket"),
+		Timezone: c.Query("timezone"),
+	})
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(stats)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestGetExampleStats(t *testing.T) {
+	t.Run("query parameters", func(t *testing.T) {
+		var got models.ExampleStatsQuery
+		mock := &mockExampleService{
+			statsFn: func(_ context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+				got = query
+				return &models.ExampleStats{Total: models.ValueStats{Count: 3, Sum: 6}}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/examples/stats?is_active=true&bucket=week&timezone=Europe/Moscow", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if got.Filter.IsActive == nil || !*got.Filter.IsActive || got.Bucket != "week" || got.Timezone != "Europe/Moscow" {
+			t.Errorf("unexpected query %+v", got)
+		}
+		if stats := decodeJSON[models.ExampleStats](t, resp); stats.Total.Count != 3 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		mock := &mockExampleService{
+			statsFn: func(context.Context, models.ExampleStatsQuery) (*models.ExampleStats, error) {
+				return nil, service.ErrInvalidStatsBucket
+			},
+		}
+		s := newTestServer(&service.Services{Example: mock})
+
+		for _, path := range []string{
+			"/api/v1/examples/stats?is_active=maybe",
+			"/api/v1/examples/stats?bucket=year",
+		} {
+			if resp := doRequest(s, http.MethodGet, path, nil); resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+}
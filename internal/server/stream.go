@@ -0,0 +1,222 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go-service-template/internal/events"
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// streamReplayBatch — сколько событий журнала читается за раз при
+	// досылке истории после Last-Event-ID.
+	streamReplayBatch = 500
+	// streamRetryMillis — через сколько EventSource переподключается после обрыва.
+	streamRetryMillis = 3000
+)
+
+// streamStartTimeout — сколько запрос ждёт готовности журнала (Feed ещё не
+// прочитал начальную позицию или база недоступна), прежде чем ответить 503.
+// Переменная, чтобы тесты не ждали по пять секунд.
+var streamStartTimeout = 5 * time.Second
+
+// streamExamples отдаёт поток изменений записей
+// @Summary Stream example changes
+// @Description Server-Sent Events feed of created, updated and deleted examples sourced from the database change log, so it includes changes made through any replica. Event IDs increase monotonically; reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume without gaps. A "reset" event means the requested position is no longer retained and the client should reload its state. Comment lines are sent as heartbeats. A client that cannot keep up is disconnected and should resume with Last-Event-ID.
+// @Tags examples
+// @Produce text/event-stream
+// @Param Last-Event-ID header int false "Resume after this event ID"
+// @Param last_event_id query int false "Resume after this event ID (for clients that cannot set headers)"
+// @Success 200 {object} models.ExampleEvent "Event stream"
+// @Failure 400 {object} models.ErrorResponse "Invalid Last-Event-ID"
+// @Failure 503 {object} models.ErrorResponse "Change feed unavailable"
+// @Router /examples/stream [get]
+func (s *Server) streamExamples(c *fiber.Ctx) error {
+	if s.events == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+			Error: "change feed is disabled",
+		})
+	}
+
+	lastID, resume, err := parseLastEventID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	// Поток живёт после выхода из обработчика; контекст отменяется при
+	// остановке сервера или обрыве соединения.
+	ctx, cancel := context.WithCancel(s.subscriptions)
+	// Подписка раньше чтения истории: события, записанные между ними,
+	// придут дважды (дубликаты отбрасываются по ID), но не потеряются.
+	sub := s.events.Subscribe()
+
+	if !resume {
+		startCtx, cancelStart := context.WithTimeout(ctx, streamStartTimeout)
+		lastID, err = s.events.Latest(startCtx)
+		cancelStart()
+		if err != nil {
+			s.events.Unsubscribe(sub)
+			cancel()
+			s.logger.Error("Failed to start change stream", slog.String("error", err.Error()))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+				Error: "change feed is unavailable",
+			})
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// Отключает буферизацию ответа в nginx.
+	c.Set("X-Accel-Buffering", "no")
+
+	conn := c.Context().Conn()
+	writeTimeout := s.config.Server.WriteTimeout
+	heartbeat := s.config.Events.HeartbeatInterval
+	feed := s.events
+	logger := s.logger
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer feed.Unsubscribe(sub)
+		s.activeStreams.Add(1)
+		defer s.activeStreams.Add(-1)
+
+		stream := &sseWriter{w: w, extend: func() {
+			if writeTimeout > 0 {
+				_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			}
+		}}
+		if err := stream.retry(streamRetryMillis); err != nil {
+			return
+		}
+
+		lastID, err := replayEvents(ctx, feed, stream, lastID)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Warn("Change stream closed during replay", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Done():
+				logger.Warn("Change stream subscriber is too slow, disconnecting", slog.Int64("last_event_id", lastID))
+				return
+			case event := <-sub.Events():
+				if event.ID <= lastID {
+					continue
+				}
+				if err := stream.event(event); err != nil {
+					return
+				}
+				lastID = event.ID
+			case <-ticker.C:
+				if err := stream.comment("heartbeat"); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// replayEvents досылает события журнала после lastID и возвращает ID
+// последнего отправленного. Если продолжить с lastID нельзя, клиент получает
+// событие reset, а поток продолжается с текущей позиции журнала.
+func replayEvents(ctx context.Context, feed *events.Feed, stream *sseWriter, lastID int64) (int64, error) {
+	for {
+		batch, err := feed.Replay(ctx, lastID, streamReplayBatch)
+		if errors.Is(err, events.ErrCannotResume) {
+			if lastID, err = feed.Latest(ctx); err != nil {
+				return lastID, err
+			}
+			if err := stream.reset(lastID); err != nil {
+				return lastID, err
+			}
+			continue
+		}
+		if err != nil {
+			return lastID, err
+		}
+
+		for _, event := range batch {
+			if err := stream.event(event); err != nil {
+				return lastID, err
+			}
+			lastID = event.ID
+		}
+		if len(batch) < streamReplayBatch {
+			return lastID, nil
+		}
+	}
+}
+
+// parseLastEventID читает позицию возобновления из заголовка Last-Event-ID
+// или параметра last_event_id. resume = false, если ни один не передан.
+func parseLastEventID(c *fiber.Ctx) (id int64, resume bool, err error) {
+	raw := c.Get("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, false, nil
+	}
+	id, err = strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, fiber.NewError(fiber.StatusBadRequest, "Invalid Last-Event-ID: expected a non-negative integer")
+	}
+	return id, true, nil
+}
+
+// sseWriter пишет сообщения в формате text/event-stream. Каждое сообщение
+// сразу отправляется клиенту, дедлайн записи продлевается на каждое.
+type sseWriter struct {
+	w      *bufio.Writer
+	extend func()
+}
+
+func (s *sseWriter) event(event models.ExampleEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.write("id: " + strconv.FormatInt(event.ID, 10) + "\nevent: " + event.Type + "\ndata: " + string(data) + "\n\n")
+}
+
+// reset сообщает клиенту, что история с его позиции потеряна. id ставится,
+// чтобы следующее переподключение продолжило уже с новой позиции.
+func (s *sseWriter) reset(id int64) error {
+	return s.write("id: " + strconv.FormatInt(id, 10) + "\nevent: reset\ndata: {}\n\n")
+}
+
+func (s *sseWriter) comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+func (s *sseWriter) retry(millis int) error {
+	return s.write("retry: " + strconv.Itoa(millis) + "\n\n")
+}
+
+func (s *sseWriter) write(message string) error {
+	s.extend()
+	if _, err := s.w.WriteString(message); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
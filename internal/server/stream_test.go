@@ -0,0 +1,211 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/events"
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []models.ExampleEvent
+}
+
+func (s *fakeEventStore) append(id int64, typ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, models.ExampleEvent{ID: id, Type: typ, ExampleID: int(id), Example: &models.Example{ID: int(id)}})
+}
+
+func (s *fakeEventStore) EventsSince(_ context.Context, afterID int64, limit int) ([]models.ExampleEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []models.ExampleEvent
+	for _, event := range s.events {
+		if event.ID > afterID && len(out) < limit {
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeEventStore) EventBounds(context.Context) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return 0, 0, nil
+	}
+	return s.events[0].ID, s.events[len(s.events)-1].ID, nil
+}
+
+// newStreamTestServer поднимает сервер с потоком изменений на реальном
+// листенере: app.Test не умеет читать бесконечный ответ и апгрейдить соединение.
+func newStreamTestServer(t *testing.T, store *fakeEventStore) (*Server, *events.Feed, string) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	feed := events.NewFeed(store, 16, time.Hour, logger)
+	s := newTestServer(&service.Services{}, WithEvents(feed), withTestConfig(func(cfg *config.Config) {
+		cfg.Events = config.EventsConfig{HeartbeatInterval: 50 * time.Millisecond}
+		cfg.WebSocket = config.WebSocketConfig{MaxSubscriptions: 2, MaxExampleIDs: 3, PingInterval: time.Minute}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	feedDone := make(chan struct{})
+	go func() {
+		defer close(feedDone)
+		_ = feed.Run(ctx)
+	}()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = s.app.Listener(ln) }()
+
+	t.Cleanup(func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer shutdownCancel()
+		_ = s.Shutdown(shutdownCtx)
+		cancel()
+		<-feedDone
+	})
+	return s, feed, "http://" + ln.Addr().String()
+}
+
+// readSSE читает сообщения потока (поля до пустой строки) и отдаёт их в канал.
+func readSSE(body io.Reader) <-chan map[string]string {
+	out := make(chan map[string]string, 16)
+	go func() {
+		defer close(out)
+		scanner := bufio.NewScanner(body)
+		msg := map[string]string{}
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				out <- msg
+				msg = map[string]string{}
+				continue
+			}
+			field, value, _ := strings.Cut(line, ":")
+			msg[field] = strings.TrimPrefix(value, " ")
+		}
+	}()
+	return out
+}
+
+func nextEvent(t *testing.T, messages <-chan map[string]string) map[string]string {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			if msg["event"] != "" {
+				return msg
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
+
+func TestStreamExamples_ResumeAndLive(t *testing.T) {
+	store := &fakeEventStore{}
+	store.append(1, "created")
+	store.append(2, "updated")
+	store.append(3, "deleted")
+	_, feed, baseURL := newStreamTestServer(t, store)
+
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/api/v1/examples/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("unexpected response: %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	messages := readSSE(resp.Body)
+	for _, want := range []struct{ id, typ string }{{"2", "updated"}, {"3", "deleted"}} {
+		msg := nextEvent(t, messages)
+		if msg["id"] != want.id || msg["event"] != want.typ || !strings.Contains(msg["data"], `"example_id":`+want.id) {
+			t.Fatalf("expected replayed event %s %s, got %v", want.id, want.typ, msg)
+		}
+	}
+
+	store.append(4, "created")
+	feed.Notify()
+	if msg := nextEvent(t, messages); msg["id"] != "4" {
+		t.Fatalf("expected live event 4, got %v", msg)
+	}
+
+	// Heartbeat — комментарий, поле с пустым именем.
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case msg := <-messages:
+			if _, ok := msg[""]; ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected heartbeat comment")
+		}
+	}
+}
+
+func TestStreamExamples_ResetWhenHistoryIsGone(t *testing.T) {
+	store := &fakeEventStore{}
+	store.append(10, "created")
+	_, _, baseURL := newStreamTestServer(t, store)
+
+	resp, err := http.Get(baseURL + "/api/v1/examples/stream?last_event_id=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if msg := nextEvent(t, readSSE(resp.Body)); msg["event"] != "reset" || msg["id"] != "10" {
+		t.Fatalf("expected reset to 10, got %v", msg)
+	}
+}
+
+func TestStreamExamples_Errors(t *testing.T) {
+	s := newTestServer(&service.Services{})
+	if resp := doRequest(s, http.MethodGet, "/api/v1/examples/stream", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without feed, got %d", resp.StatusCode)
+	}
+
+	s = newTestServer(&service.Services{},
+		WithEvents(events.NewFeed(&fakeEventStore{}, 1, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))))
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/examples/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	if resp, _ := s.app.Test(req, -1); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid Last-Event-ID, got %d", resp.StatusCode)
+	}
+
+	// Feed не запущен и начальную позицию не прочитает: запрос не должен
+	// висеть до остановки сервера.
+	defer func(timeout time.Duration) { streamStartTimeout = timeout }(streamStartTimeout)
+	streamStartTimeout = 50 * time.Millisecond
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/examples/stream", nil)
+	if resp, _ := s.app.Test(req, -1); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while feed is not ready, got %d", resp.StatusCode)
+	}
+}
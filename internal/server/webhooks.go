@@ -0,0 +1,187 @@
+package server
+
+import (
+	"strconv"
+
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultWebhookAttemptsLimit — сколько попыток доставки отдаётся без limit.
+const defaultWebhookAttemptsLimit = 50
+
+// createWebhook создаёт подписку на webhooks
+// @Summary Create webhook subscription
+// @Description Subscribes a URL to example change events. Deliveries are POSTed as JSON and signed with HMAC-SHA256 of "<timestamp>.<body>" using the subscription secret; the signature is sent in the Webhook-Signature header as "t=<unix seconds>,v1=<hex>". Use "*" in event_types to receive every event. The secret is never returned.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookRequest true "Subscription"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse "Invalid input data"
+// @Router /webhooks [post]
+func (s *Server) createWebhook(c *fiber.Ctx) error {
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	sub, err := s.services.Webhooks.CreateWebhook(c.UserContext(), &req)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sub)
+}
+
+// getAllWebhooks получает список подписок
+// @Summary Get webhook subscriptions
+// @Description Returns all webhook subscriptions including disabled ones.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} models.WebhookResponse
+// @Router /webhooks [get]
+func (s *Server) getAllWebhooks(c *fiber.Ctx) error {
+	subs, err := s.services.Webhooks.GetAllWebhooks(c.UserContext())
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.WebhookResponse{
+		Data: subs,
+	})
+}
+
+// getWebhook получает подписку по ID
+// @Summary Get webhook subscription by ID
+// @Description Returns a webhook subscription. A subscription disabled after repeated delivery failures has active=false and disabled_at set.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Subscription not found"
+// @Router /webhooks/{id} [get]
+func (s *Server) getWebhook(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	sub, err := s.services.Webhooks.GetWebhook(c.UserContext(), id)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(sub)
+}
+
+// updateWebhook изменяет подписку
+// @Summary Update webhook subscription
+// @Description Replaces the URL and event types. An empty secret keeps the current one. Setting active=true re-enables a disabled subscription and resets its failure counter; active=false pauses deliveries.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param webhook body models.WebhookRequest true "Subscription"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse "Invalid input data"
+// @Failure 404 {object} models.ErrorResponse "Subscription not found"
+// @Router /webhooks/{id} [put]
+func (s *Server) updateWebhook(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	sub, err := s.services.Webhooks.UpdateWebhook(c.UserContext(), id, &req)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(sub)
+}
+
+// deleteWebhook удаляет подписку
+// @Summary Delete webhook subscription
+// @Description Deletes a subscription together with its pending deliveries and delivery log.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid ID"
+// @Failure 404 {object} models.ErrorResponse "Subscription not found"
+// @Router /webhooks/{id} [delete]
+func (s *Server) deleteWebhook(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if err := s.services.Webhooks.DeleteWebhook(c.UserContext(), id); err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.MessageResponse{
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// getWebhookDeliveries отдаёт журнал попыток доставки
+// @Summary Get recent webhook delivery attempts
+// @Description Returns the most recent delivery attempts of a subscription, newest first. status_code is omitted when no HTTP response was received; error describes the failure.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param limit query int false "Number of attempts (max 100)" default(50)
+// @Success 200 {object} models.WebhookAttemptResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} models.ErrorResponse "Subscription not found"
+// @Router /webhooks/{id}/deliveries [get]
+func (s *Server) getWebhookDeliveries(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultWebhookAttemptsLimit)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid limit parameter",
+		})
+	}
+
+	attempts, err := s.services.Webhooks.GetWebhookAttempts(c.UserContext(), id, limit)
+	if err != nil {
+		return s.handleServiceError(c, err)
+	}
+
+	return c.JSON(models.WebhookAttemptResponse{
+		Data: attempts,
+	})
+}
+
+func parseWebhookID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+	return id, nil
+}
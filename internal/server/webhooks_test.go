@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+type mockWebhookService struct {
+	createFn   func(ctx context.Context, req *models.WebhookRequest) (*models.WebhookSubscription, error)
+	getFn      func(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	attemptsFn func(ctx context.Context, id int64, limit int) ([]models.WebhookAttempt, error)
+}
+
+func (m *mockWebhookService) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.WebhookSubscription, error) {
+	return m.createFn(ctx, req)
+}
+
+func (m *mockWebhookService) GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return m.getFn(ctx, id)
+}
+
+func (m *mockWebhookService) GetAllWebhooks(context.Context) ([]models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookService) UpdateWebhook(context.Context, int64, *models.WebhookRequest) (*models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookService) DeleteWebhook(context.Context, int64) error {
+	return nil
+}
+
+func (m *mockWebhookService) GetWebhookAttempts(ctx context.Context, id int64, limit int) ([]models.WebhookAttempt, error) {
+	return m.attemptsFn(ctx, id, limit)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("secret is not returned", func(t *testing.T) {
+		mock := &mockWebhookService{
+			createFn: func(_ context.Context, req *models.WebhookRequest) (*models.WebhookSubscription, error) {
+				return &models.WebhookSubscription{ID: 1, URL: req.URL, Secret: req.Secret, EventTypes: req.EventTypes, Active: true}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Webhooks: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/webhooks", models.WebhookRequest{
+			URL:        "https://partner.example.com/hooks",
+			Secret:     "0123456789abcdef",
+			EventTypes: []string{"*"},
+		})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if strings.Contains(string(body), "0123456789abcdef") {
+			t.Errorf("response leaks the secret: %s", body)
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		mock := &mockWebhookService{
+			createFn: func(context.Context, *models.WebhookRequest) (*models.WebhookSubscription, error) {
+				return nil, service.ErrWebhookURLInvalid
+			},
+		}
+		s := newTestServer(&service.Services{Webhooks: mock})
+
+		resp := doRequest(s, http.MethodPost, "/api/v1/webhooks", models.WebhookRequest{URL: "nope"})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	t.Run("default limit", func(t *testing.T) {
+		var gotLimit int
+		mock := &mockWebhookService{
+			attemptsFn: func(_ context.Context, id int64, limit int) ([]models.WebhookAttempt, error) {
+				gotLimit = limit
+				return []models.WebhookAttempt{{ID: 2, Attempt: 2, StatusCode: 200}, {ID: 1, Attempt: 1, Error: "timeout"}}, nil
+			},
+		}
+		s := newTestServer(&service.Services{Webhooks: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/webhooks/3/deliveries", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		got := decodeJSON[models.WebhookAttemptResponse](t, resp)
+		if len(got.Data) != 2 || gotLimit != defaultWebhookAttemptsLimit {
+			t.Errorf("unexpected response %+v with limit %d", got, gotLimit)
+		}
+	})
+
+	t.Run("unknown subscription", func(t *testing.T) {
+		mock := &mockWebhookService{
+			attemptsFn: func(context.Context, int64, int) ([]models.WebhookAttempt, error) {
+				return nil, service.ErrWebhookNotFound
+			},
+		}
+		s := newTestServer(&service.Services{Webhooks: mock})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/webhooks/3/deliveries", nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		s := newTestServer(&service.Services{Webhooks: &mockWebhookService{}})
+
+		resp := doRequest(s, http.MethodGet, "/api/v1/webhooks/3/deliveries?limit=x", nil)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestWebhookRoutesDisabled(t *testing.T) {
+	s := newTestServer(&service.Services{})
+
+	resp := doRequest(s, http.MethodGet, "/api/v1/webhooks", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 without webhook service, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,326 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-service-template/internal/events"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// wsMaxMessageSize — максимальный размер сообщения клиента.
+	wsMaxMessageSize = 64 << 10
+	// wsWriteTimeout ограничивает отправку одного сообщения клиенту.
+	wsWriteTimeout = 10 * time.Second
+	// wsCloseTimeout — сколько ждать ответного close-фрейма от клиента.
+	wsCloseTimeout = time.Second
+	// wsMaxSubscriptionIDLength — максимальная длина ID подписки.
+	wsMaxSubscriptionIDLength = 64
+)
+
+var (
+	errWSSubscriptionIDRequired = errors.New("subscription id is required")
+	errWSSubscriptionIDTooLong  = errors.New("subscription id is too long")
+	errWSSubscriptionExists     = errors.New("subscription already exists")
+	errWSSubscriptionNotFound   = errors.New("subscription not found")
+	errWSSubscriptionTarget     = errors.New("exactly one of example_ids or filter is required")
+	errWSSubscriptionLimit      = errors.New("subscription limit reached")
+	errWSExampleIDLimit         = errors.New("example id limit reached")
+	errWSInvalidExampleID       = errors.New("example ids must be positive")
+	errWSInvalidMessage         = errors.New("invalid message")
+	errWSUnknownType            = errors.New("unknown message type")
+)
+
+// websocketUpgrade проверяет запрос до апгрейда: ответить обычным HTTP-кодом
+// после переключения протокола уже нельзя.
+// @Summary Subscribe to example changes over WebSocket
+// @Description Upgrades to a WebSocket. The client sends JSON messages {"type":"subscribe","id":"sub-1","example_ids":[1,2]} or {"type":"subscribe","id":"sub-2","filter":{"is_active":true}}, {"type":"unsubscribe","id":"sub-1"} and {"type":"ping"}. The server replies with welcome, subscribed, unsubscribed, pong and error messages and sends {"type":"event","subscriptions":[...],"event":{...}} for every change matching at least one subscription. Changes come from the database change log, so they include writes made through any replica. The number of subscriptions and example IDs per connection is limited. The connection is closed with code 1001 when the server shuts down and 1013 when the client cannot keep up.
+// @Tags examples
+// @Success 101 {object} models.WSMessage "Switching protocols"
+// @Failure 426 {object} models.ErrorResponse "WebSocket upgrade required"
+// @Failure 503 {object} models.ErrorResponse "Change feed unavailable"
+// @Router /ws [get]
+func (s *Server) websocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(models.ErrorResponse{
+			Error: "WebSocket upgrade required",
+		})
+	}
+	if s.events == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+			Error: "change feed is disabled",
+		})
+	}
+	return c.Next()
+}
+
+// websocketConfig разрешает апгрейд с тех же источников, что и CORS.
+func (s *Server) websocketConfig() websocket.Config {
+	var origins []string
+	for origin := range strings.SplitSeq(s.config.Server.CORSAllowOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return websocket.Config{Origins: origins}
+}
+
+func (s *Server) handleWebSocket(conn *websocket.Conn) {
+	principal, _ := conn.Locals(localsPrincipal).(string)
+	session := &wsSession{
+		conn:             conn,
+		feed:             s.events,
+		logger:           s.logger.With(slog.String("principal", principal)),
+		principal:        principal,
+		maxSubscriptions: s.config.WebSocket.MaxSubscriptions,
+		maxExampleIDs:    s.config.WebSocket.MaxExampleIDs,
+		pingInterval:     s.config.WebSocket.PingInterval,
+		subscriptions:    make(map[string]*wsSubscription),
+	}
+
+	metrics.WebSocketConnections.Add(1)
+	defer metrics.WebSocketConnections.Add(-1)
+	s.activeWebSockets.Add(1)
+	defer s.activeWebSockets.Add(-1)
+	session.run(s.subscriptions)
+}
+
+// wsSession — одно WebSocket-соединение. Все записи в сокет идут из run;
+// чтение — в отдельной горутине.
+type wsSession struct {
+	conn      *websocket.Conn
+	feed      *events.Feed
+	logger    *slog.Logger
+	principal string
+
+	maxSubscriptions int
+	maxExampleIDs    int
+	pingInterval     time.Duration
+
+	subscriptions map[string]*wsSubscription
+	exampleIDs    int
+}
+
+// wsSubscription — подписка на список ID или на фильтр.
+type wsSubscription struct {
+	ids    map[int]struct{}
+	filter *models.SubscriptionFilter
+}
+
+// wsIncoming — разобранное сообщение клиента или ошибка его разбора.
+type wsIncoming struct {
+	req models.WSRequest
+	err error
+}
+
+func (s *wsSession) run(ctx context.Context) {
+	sub := s.feed.Subscribe()
+	defer s.feed.Unsubscribe(sub)
+
+	done := make(chan struct{})
+	defer close(done)
+	incoming := make(chan wsIncoming)
+	readErr := make(chan error, 1)
+	go s.read(done, incoming, readErr)
+
+	if err := s.write(models.WSMessage{Type: "welcome", Principal: s.principal}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			s.close(websocket.CloseGoingAway, "server is shutting down", readErr)
+			return
+		case <-sub.Done():
+			s.close(websocket.CloseTryAgainLater, "client is too slow", readErr)
+			return
+		case err = <-readErr:
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.logger.Debug("WebSocket read failed", slog.String("error", err.Error()))
+			}
+			return
+		case in := <-incoming:
+			err = s.write(s.handle(in))
+		case event := <-sub.Events():
+			if matched := s.match(event); len(matched) > 0 {
+				err = s.write(models.WSMessage{Type: "event", Subscriptions: matched, Event: &event})
+			}
+		case <-ticker.C:
+			err = s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+		}
+		if err != nil {
+			s.logger.Debug("WebSocket write failed", slog.String("error", err.Error()))
+			return
+		}
+	}
+}
+
+// read читает сообщения клиента. Соединение без сообщений и pong дольше двух
+// интервалов ping считается потерянным.
+func (s *wsSession) read(done <-chan struct{}, incoming chan<- wsIncoming, readErr chan<- error) {
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	extend := func() {
+		_ = s.conn.SetReadDeadline(time.Now().Add(2 * s.pingInterval))
+	}
+	extend()
+	s.conn.SetPongHandler(func(string) error {
+		extend()
+		return nil
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			readErr <- err
+			return
+		}
+		extend()
+
+		var in wsIncoming
+		if err := json.Unmarshal(data, &in.req); err != nil {
+			in.err = errWSInvalidMessage
+		}
+		select {
+		case incoming <- in:
+		case <-done:
+			return
+		}
+	}
+}
+
+func (s *wsSession) handle(in wsIncoming) models.WSMessage {
+	if in.err != nil {
+		return models.WSMessage{Type: "error", Error: in.err.Error()}
+	}
+
+	req := in.req
+	var err error
+	switch req.Type {
+	case "subscribe":
+		if err = s.subscribe(req); err == nil {
+			return models.WSMessage{Type: "subscribed", ID: req.ID}
+		}
+	case "unsubscribe":
+		if err = s.unsubscribe(req.ID); err == nil {
+			return models.WSMessage{Type: "unsubscribed", ID: req.ID}
+		}
+	case "ping":
+		return models.WSMessage{Type: "pong", ID: req.ID}
+	default:
+		err = errWSUnknownType
+	}
+	return models.WSMessage{Type: "error", ID: req.ID, Error: err.Error()}
+}
+
+func (s *wsSession) subscribe(req models.WSRequest) error {
+	switch {
+	case req.ID == "":
+		return errWSSubscriptionIDRequired
+	case len(req.ID) > wsMaxSubscriptionIDLength:
+		return errWSSubscriptionIDTooLong
+	case (len(req.ExampleIDs) == 0) == (req.Filter == nil):
+		return errWSSubscriptionTarget
+	}
+	if _, ok := s.subscriptions[req.ID]; ok {
+		return errWSSubscriptionExists
+	}
+	if len(s.subscriptions) >= s.maxSubscriptions {
+		return errWSSubscriptionLimit
+	}
+
+	if req.Filter != nil {
+		s.subscriptions[req.ID] = &wsSubscription{filter: req.Filter}
+		return nil
+	}
+
+	ids := make(map[int]struct{}, len(req.ExampleIDs))
+	for _, id := range req.ExampleIDs {
+		if id <= 0 {
+			return errWSInvalidExampleID
+		}
+		ids[id] = struct{}{}
+	}
+	if s.exampleIDs+len(ids) > s.maxExampleIDs {
+		return errWSExampleIDLimit
+	}
+	s.subscriptions[req.ID] = &wsSubscription{ids: ids}
+	s.exampleIDs += len(ids)
+	return nil
+}
+
+func (s *wsSession) unsubscribe(id string) error {
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return errWSSubscriptionNotFound
+	}
+	delete(s.subscriptions, id)
+	s.exampleIDs -= len(sub.ids)
+	return nil
+}
+
+// match возвращает ID подписок, под которые подходит событие.
+func (s *wsSession) match(event models.ExampleEvent) []string {
+	var matched []string
+	for id, sub := range s.subscriptions {
+		if sub.matches(event) {
+			matched = append(matched, id)
+		}
+	}
+	return matched
+}
+
+// matches сверяет подписку с событием. Фильтр применяется к состоянию
+// записи после изменения (для deleted — до удаления).
+func (sub *wsSubscription) matches(event models.ExampleEvent) bool {
+	if sub.ids != nil {
+		_, ok := sub.ids[event.ExampleID]
+		return ok
+	}
+
+	example := event.Example
+	if example == nil {
+		return false
+	}
+	f := sub.filter
+	if f.IsActive != nil && example.IsActive != *f.IsActive {
+		return false
+	}
+	if f.CreatedAfter != nil && example.CreatedAt.Before(*f.CreatedAfter) {
+		return false
+	}
+	if f.CreatedBefore != nil && !example.CreatedAt.Before(*f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+func (s *wsSession) write(msg models.WSMessage) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return s.conn.WriteJSON(msg)
+}
+
+// close отправляет close-фрейм и ждёт ответного от клиента, чтобы закрытие
+// прошло по протоколу, а не обрывом TCP.
+func (s *wsSession) close(code int, reason string, readErr <-chan error) {
+	deadline := time.Now().Add(wsWriteTimeout)
+	if err := s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline); err != nil {
+		return
+	}
+	select {
+	case <-readErr:
+	case <-time.After(wsCloseTimeout):
+	}
+}
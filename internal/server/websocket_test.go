@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/fasthttp/websocket"
+)
+
+func (s *fakeEventStore) appendExample(id int64, typ string, active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, models.ExampleEvent{
+		ID: id, Type: typ, ExampleID: int(id),
+		Example: &models.Example{ID: int(id), IsActive: active},
+	})
+}
+
+func dialWS(t *testing.T, baseURL string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(baseURL, "http")+"/api/v1/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = resp.Body.Close()
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) models.WSMessage {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg models.WSMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func TestWebSocket_Subscriptions(t *testing.T) {
+	store := &fakeEventStore{}
+	_, feed, baseURL := newStreamTestServer(t, store)
+	conn := dialWS(t, baseURL)
+
+	if msg := readWS(t, conn); msg.Type != "welcome" || msg.Principal != anonymousPrincipal {
+		t.Fatalf("expected welcome for %q, got %+v", anonymousPrincipal, msg)
+	}
+
+	active := true
+	requests := []struct {
+		req  models.WSRequest
+		want models.WSMessage
+	}{
+		{models.WSRequest{Type: "subscribe", ID: "ids", ExampleIDs: []int{1, 2}}, models.WSMessage{Type: "subscribed", ID: "ids"}},
+		{models.WSRequest{Type: "subscribe", ID: "ids", ExampleIDs: []int{3}}, models.WSMessage{Type: "error", ID: "ids", Error: errWSSubscriptionExists.Error()}},
+		{models.WSRequest{Type: "subscribe", ID: "many", ExampleIDs: []int{3, 4}}, models.WSMessage{Type: "error", ID: "many", Error: errWSExampleIDLimit.Error()}},
+		{models.WSRequest{Type: "subscribe", ID: "both"}, models.WSMessage{Type: "error", ID: "both", Error: errWSSubscriptionTarget.Error()}},
+		{models.WSRequest{Type: "subscribe", ID: "active", Filter: &models.SubscriptionFilter{IsActive: &active}}, models.WSMessage{Type: "subscribed", ID: "active"}},
+		{models.WSRequest{Type: "subscribe", ID: "third", ExampleIDs: []int{3}}, models.WSMessage{Type: "error", ID: "third", Error: errWSSubscriptionLimit.Error()}},
+		{models.WSRequest{Type: "ping", ID: "p"}, models.WSMessage{Type: "pong", ID: "p"}},
+		{models.WSRequest{Type: "bogus"}, models.WSMessage{Type: "error", Error: errWSUnknownType.Error()}},
+	}
+	for _, tt := range requests {
+		if err := conn.WriteJSON(tt.req); err != nil {
+			t.Fatal(err)
+		}
+		if got := readWS(t, conn); got.Type != tt.want.Type || got.ID != tt.want.ID || got.Error != tt.want.Error {
+			t.Fatalf("request %+v: got %+v, want %+v", tt.req, got, tt.want)
+		}
+	}
+
+	store.appendExample(1, "updated", false) // только ids
+	store.appendExample(5, "created", true)  // только фильтр is_active
+	store.appendExample(7, "updated", false) // ни одна подписка
+	feed.Notify()
+
+	if msg := readWS(t, conn); msg.Type != "event" || msg.Event.ID != 1 || len(msg.Subscriptions) != 1 || msg.Subscriptions[0] != "ids" {
+		t.Fatalf("expected event 1 for ids, got %+v", msg)
+	}
+	if msg := readWS(t, conn); msg.Type != "event" || msg.Event.ID != 5 || len(msg.Subscriptions) != 1 || msg.Subscriptions[0] != "active" {
+		t.Fatalf("expected event 5 for active, got %+v", msg)
+	}
+
+	if err := conn.WriteJSON(models.WSRequest{Type: "unsubscribe", ID: "ids"}); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readWS(t, conn); msg.Type != "unsubscribed" {
+		t.Fatalf("expected unsubscribed, got %+v", msg)
+	}
+}
+
+func TestWebSocket_ClosesOnShutdown(t *testing.T) {
+	s, _, baseURL := newStreamTestServer(t, &fakeEventStore{})
+	conn := dialWS(t, baseURL)
+	readWS(t, conn) // welcome
+	if n := s.activeWebSockets.Load(); n != 1 {
+		t.Fatalf("expected 1 active websocket, got %d", n)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("expected close 1001 on shutdown, got %v", err)
+	}
+}
+
+func TestWebSocket_RequiresUpgrade(t *testing.T) {
+	s := newTestServer(&service.Services{})
+	if resp := doRequest(s, http.MethodGet, "/api/v1/ws", nil); resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("expected 426, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"go-service-template/internal/models"
+)
+
+// Действия журнала аудита.
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	AuditEntityExample = "example"
+
+	// SystemActor — субъект изменений, сделанных не по запросу клиента
+	// (фоновые задачи, миграции данных).
+	SystemActor = "system"
+)
+
+// Actor — кто выполняет запрос. Сервер кладёт его в контекст после
+// аутентификации, сервис записывает в журнал аудита.
+type Actor struct {
+	Principal string
+	RequestID string
+	ClientIP  string
+}
+
+type actorKey struct{}
+
+// WithActor возвращает контекст с субъектом запроса.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom возвращает субъект из контекста или SystemActor, если его нет.
+func ActorFrom(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok && actor.Principal != "" {
+		return actor
+	}
+	return Actor{Principal: SystemActor}
+}
+
+type AuditService interface {
+	GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+// AuditStorage читает журнал аудита; записи добавляются через
+// TxStorage.AppendAudit.
+type AuditStorage interface {
+	GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+type auditService struct {
+	storage AuditStorage
+	logger  *slog.Logger
+}
+
+func NewAuditService(storage AuditStorage, logger *slog.Logger) AuditService {
+	return &auditService{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (s *auditService) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		return nil, ErrLimitMustBePositive
+	}
+	if filter.Offset < 0 {
+		return nil, ErrOffsetMustBeNonNeg
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, ErrInvalidTimeRange
+	}
+	filter.Limit = min(filter.Limit, 100)
+
+	entries, err := s.storage.GetAuditLog(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to get audit log", slog.String("error", err.Error()))
+		return nil, ErrGetAuditLogFailed
+	}
+
+	return entries, nil
+}
+
+// appendExampleAudit пишет в журнал аудита изменение записи id субъектом из
+// ctx: строку в audit_log с действием action, before и after — состояние
+// до и после (nil пишется как null), плюс request id и IP клиента.
+func appendExampleAudit(ctx context.Context, tx TxStorage, action string, id int, before, after *models.Example) error {
+	entry := &models.AuditEntry{
+		Action:     action,
+		EntityType: AuditEntityExample,
+		EntityID:   strconv.Itoa(id),
+	}
+
+	var err error
+	if entry.Before, err = auditState(before); err != nil {
+		return err
+	}
+	if entry.After, err = auditState(after); err != nil {
+		return err
+	}
+
+	actor := ActorFrom(ctx)
+	entry.Actor, entry.RequestID, entry.ClientIP = actor.Principal, actor.RequestID, actor.ClientIP
+	return tx.AppendAudit(ctx, entry)
+}
+
+func auditState(example *models.Example) (json.RawMessage, error) {
+	if example == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(example)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	return data, nil
+}
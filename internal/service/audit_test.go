@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+func TestExampleChanges_WriteAudit(t *testing.T) {
+	var entries []models.AuditEntry
+	st := &mockStorage{
+		createExampleFn: func(_ context.Context, example *models.Example) error {
+			example.ID = 7
+			return nil
+		},
+		getForUpdateFn: func(_ context.Context, id int) (*models.Example, error) {
+			return &models.Example{ID: id, Name: "old"}, nil
+		},
+		appendAuditFn: func(_ context.Context, entry *models.AuditEntry) error {
+			entries = append(entries, *entry)
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+	ctx := WithActor(context.Background(), Actor{Principal: "alice", RequestID: "req-1", ClientIP: "10.0.0.1"})
+	req := &models.ExampleRequest{Name: "new"}
+
+	if _, err := svc.CreateExample(ctx, req); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.UpdateExample(ctx, 7, req); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := svc.DeleteExample(ctx, 7); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []struct {
+		action        string
+		before, after string
+	}{
+		{AuditActionCreate, "", "new"},
+		{AuditActionUpdate, "old", "new"},
+		{AuditActionDelete, "old", ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d audit entries, got %+v", len(want), entries)
+	}
+	for i, entry := range entries {
+		if entry.Action != want[i].action || entry.EntityType != AuditEntityExample || entry.EntityID != "7" {
+			t.Errorf("entry %d: unexpected %+v", i, entry)
+		}
+		if entry.Actor != "alice" || entry.RequestID != "req-1" || entry.ClientIP != "10.0.0.1" {
+			t.Errorf("entry %d: unexpected actor %+v", i, entry)
+		}
+		if got := auditName(t, entry.Before); got != want[i].before {
+			t.Errorf("entry %d: expected before %q, got %q", i, want[i].before, got)
+		}
+		if got := auditName(t, entry.After); got != want[i].after {
+			t.Errorf("entry %d: expected after %q, got %q", i, want[i].after, got)
+		}
+	}
+}
+
+func TestBatchExamples_WriteAudit(t *testing.T) {
+	var (
+		entries []models.AuditEntry
+		locked  []int
+	)
+	st := &mockStorage{
+		createManyFn: func(_ context.Context, examples []*models.Example) error {
+			for i, example := range examples {
+				example.ID = 10 + i
+			}
+			return nil
+		},
+		getForUpdateFn: func(_ context.Context, id int) (*models.Example, error) {
+			locked = append(locked, id)
+			return &models.Example{ID: id, Name: "old"}, nil
+		},
+		appendAuditFn: func(_ context.Context, entry *models.AuditEntry) error {
+			entries = append(entries, *entry)
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	for _, mode := range []string{BatchModeAtomic, BatchModeBestEffort} {
+		entries, locked = nil, nil
+		resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+			Mode: mode,
+			Operations: []models.BatchOperation{
+				{Op: BatchOpDelete, ID: 5},
+				{Op: BatchOpUpdate, ID: 3, Data: &models.ExampleRequest{Name: "new"}},
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "new"}},
+			},
+		})
+		if err != nil || resp.Failed != 0 {
+			t.Fatalf("%s: unexpected result %+v, err %v", mode, resp, err)
+		}
+
+		want := []struct {
+			action, id    string
+			before, after string
+		}{
+			{AuditActionCreate, "10", "", "new"},
+			{AuditActionUpdate, "3", "old", "new"},
+			{AuditActionDelete, "5", "old", ""},
+		}
+		if len(entries) != len(want) {
+			t.Fatalf("%s: expected %d audit entries, got %+v", mode, len(want), entries)
+		}
+		for i, entry := range entries {
+			if entry.Action != want[i].action || entry.EntityID != want[i].id ||
+				auditName(t, entry.Before) != want[i].before || auditName(t, entry.After) != want[i].after {
+				t.Errorf("%s: entry %d: unexpected %+v", mode, i, entry)
+			}
+		}
+		if mode == BatchModeAtomic && (len(locked) != 2 || locked[0] != 3 || locked[1] != 5) {
+			t.Errorf("expected rows locked in ID order, got %v", locked)
+		}
+	}
+}
+
+// auditName возвращает имя записи из состояния аудита или "" для null.
+func auditName(t *testing.T, state json.RawMessage) string {
+	t.Helper()
+	if state == nil {
+		return ""
+	}
+	var example models.Example
+	if err := json.Unmarshal(state, &example); err != nil {
+		t.Fatalf("invalid audit state %s: %v", state, err)
+	}
+	return example.Name
+}
+
+func TestExampleChanges_AuditDefaultsToSystemActor(t *testing.T) {
+	var actor string
+	st := &mockStorage{
+		appendAuditFn: func(_ context.Context, entry *models.AuditEntry) error {
+			actor = entry.Actor
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	if _, err := svc.CreateExample(context.Background(), &models.ExampleRequest{Name: "name"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if actor != SystemActor {
+		t.Errorf("expected actor %q, got %q", SystemActor, actor)
+	}
+}
+
+func TestExampleChanges_AuditFailureFailsOperation(t *testing.T) {
+	st := &mockStorage{
+		appendAuditFn: func(context.Context, *models.AuditEntry) error {
+			return errors.New("audit unavailable")
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	if err := svc.DeleteExample(context.Background(), 1); !errors.Is(err, ErrDeleteExampleFailed) {
+		t.Fatalf("expected ErrDeleteExampleFailed, got %v", err)
+	}
+}
+
+type mockAuditStorage struct {
+	getFn func(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+func (m *mockAuditStorage) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	return m.getFn(ctx, filter)
+}
+
+func TestGetAuditLog(t *testing.T) {
+	now := time.Now()
+	var got models.AuditFilter
+	st := &mockAuditStorage{
+		getFn: func(_ context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+			got = filter
+			return nil, nil
+		},
+	}
+	svc := NewAuditService(st, testLogger())
+
+	tests := []struct {
+		name   string
+		filter models.AuditFilter
+		want   error
+	}{
+		{"zero limit", models.AuditFilter{}, ErrLimitMustBePositive},
+		{"negative offset", models.AuditFilter{Limit: 10, Offset: -1}, ErrOffsetMustBeNonNeg},
+		{"empty time range", models.AuditFilter{Limit: 10, CreatedAfter: &now, CreatedBefore: &now}, ErrInvalidTimeRange},
+		{"valid", models.AuditFilter{Limit: 1000, Actor: "alice"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetAuditLog(context.Background(), tt.filter); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if got.Limit != 100 || got.Actor != "alice" {
+		t.Errorf("expected capped limit and actor filter, got %+v", got)
+	}
+}
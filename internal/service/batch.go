@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+
+	// MaxBatchOperations — максимум операций в одном пакетном запросе.
+	MaxBatchOperations = 1000
+)
+
+// ErrBatchAborted — операция была валидна, но откачена, потому что в
+// атомарном пакете упала другая операция.
+var ErrBatchAborted = errors.New("operation rolled back: another operation in the batch failed")
+
+// batchPlan группирует валидные операции по типу: в атомарном пакете вставки
+// идут одной пачкой через COPY, обновления и удаления — через pipeline.
+// Порядок применения:
+// create, update, delete; один ID не может встречаться в пакете дважды, поэтому
+// перестановка не меняет результат.
+type batchPlan struct {
+	creates       []*models.Example
+	createIndexes []int
+	updates       []*models.Example
+	updateIndexes []int
+	deletes       []int
+	deleteIndexes []int
+}
+
+// BatchExamples применяет пакет операций. Ошибки отдельных операций
+// возвращаются в BatchItemResult.Err; ошибка функции означает, что пакет
+// отклонён целиком (невалидный запрос или сбой хранилища).
+func (s *service) BatchExamples(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error) {
+	if req == nil {
+		return nil, ErrRequestCannotBeNil
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		return nil, ErrInvalidBatchMode
+	}
+	if len(req.Operations) == 0 {
+		return nil, ErrBatchEmpty
+	}
+	if len(req.Operations) > MaxBatchOperations {
+		return nil, ErrBatchTooLarge
+	}
+
+	resp := &models.BatchResponse{
+		Mode:    mode,
+		Results: make([]models.BatchItemResult, len(req.Operations)),
+	}
+	plan := s.planBatch(req.Operations, resp.Results)
+
+	if mode == BatchModeAtomic {
+		if countFailed(resp.Results) > 0 {
+			markAborted(resp.Results)
+			return finishBatch(resp, false), nil
+		}
+
+		err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+			return s.applyBatch(ctx, tx, plan, resp.Results)
+		})
+		if err != nil {
+			if countFailed(resp.Results) == 0 {
+				s.logger.Error("Failed to apply batch", slog.String("error", err.Error()))
+				return nil, ErrBatchFailed
+			}
+			markAborted(resp.Results)
+			return finishBatch(resp, false), nil
+		}
+
+		s.logger.Info("Batch applied", slog.String("mode", mode), slog.Int("operations", len(req.Operations)))
+		return finishBatch(resp, true), nil
+	}
+
+	s.applyBestEffort(ctx, plan, resp.Results)
+	s.logger.Info("Batch applied",
+		slog.String("mode", mode),
+		slog.Int("operations", len(req.Operations)),
+		slog.Int("failed", countFailed(resp.Results)),
+	)
+	return finishBatch(resp, true), nil
+}
+
+func (s *service) planBatch(ops []models.BatchOperation, results []models.BatchItemResult) batchPlan {
+	var plan batchPlan
+	seen := make(map[int]struct{})
+	now := time.Now()
+
+	for i, op := range ops {
+		results[i] = models.BatchItemResult{Index: i, Op: op.Op}
+
+		switch op.Op {
+		case BatchOpCreate:
+			if op.Data == nil {
+				results[i].Err = ErrBatchDataRequired
+				continue
+			}
+			if err := s.validateExampleRequest(op.Data); err != nil {
+				results[i].Err = err
+				continue
+			}
+			plan.creates = append(plan.creates, &models.Example{
+				Name:        strings.TrimSpace(op.Data.Name),
+				Description: strings.TrimSpace(op.Data.Description),
+				Value:       op.Data.Value,
+				IsActive:    op.Data.IsActive,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+			plan.createIndexes = append(plan.createIndexes, i)
+
+		case BatchOpUpdate, BatchOpDelete:
+			if op.ID <= 0 {
+				results[i].Err = ErrInvalidExampleID
+				continue
+			}
+			if _, dup := seen[op.ID]; dup {
+				results[i].Err = ErrBatchDuplicateID
+				continue
+			}
+
+			if op.Op == BatchOpDelete {
+				seen[op.ID] = struct{}{}
+				plan.deletes = append(plan.deletes, op.ID)
+				plan.deleteIndexes = append(plan.deleteIndexes, i)
+				continue
+			}
+
+			if op.Data == nil {
+				results[i].Err = ErrBatchDataRequired
+				continue
+			}
+			if err := s.validateExampleRequest(op.Data); err != nil {
+				results[i].Err = err
+				continue
+			}
+			seen[op.ID] = struct{}{}
+			plan.updates = append(plan.updates, &models.Example{
+				ID:          op.ID,
+				Name:        strings.TrimSpace(op.Data.Name),
+				Description: strings.TrimSpace(op.Data.Description),
+				Value:       op.Data.Value,
+				IsActive:    op.Data.IsActive,
+				UpdatedAt:   now,
+			})
+			plan.updateIndexes = append(plan.updateIndexes, i)
+
+		default:
+			results[i].Err = ErrInvalidBatchOp
+		}
+	}
+
+	return plan
+}
+
+// applyBestEffort применяет пакет одной транзакцией. Сначала весь план
+// выполняется в savepoint пачками, как в атомарном режиме; если какая-то
+// операция упала, savepoint откатывается и операции повторяются по одной,
+// каждая в своём savepoint, — сбой одной откатывает только её.
+func (s *service) applyBestEffort(ctx context.Context, plan batchPlan, results []models.BatchItemResult) {
+	items := plan.items()
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		err := withinSavepoint(ctx, tx, func(sp TxStorage) error {
+			return s.applyBatch(ctx, sp, plan, results)
+		})
+		if err == nil {
+			return nil
+		}
+
+		for _, item := range items {
+			err := withinSavepoint(ctx, tx, func(sp TxStorage) error {
+				return s.applyBatch(ctx, sp, item.plan, results)
+			})
+			// Сбой вне самой операции (аудит, outbox, release savepoint)
+			// не виден в её результате, но она откачена.
+			if err != nil && results[item.index].Err == nil {
+				results[item.index].Example, results[item.index].Err = nil, item.failed
+			}
+		}
+		return nil
+	})
+	// Транзакция не зафиксирована (например, упал commit): не применилась
+	// ни одна операция.
+	if err != nil {
+		s.logger.Error("Failed to apply batch", slog.String("error", err.Error()))
+		for _, item := range items {
+			if results[item.index].Err == nil {
+				results[item.index].Example, results[item.index].Err = nil, item.failed
+			}
+		}
+	}
+}
+
+// withinSavepoint выполняет fn во вложенной транзакции (savepoint) на tx,
+// если её поддерживает реализация хранилища, иначе — прямо на tx.
+func withinSavepoint(ctx context.Context, tx TxStorage, fn func(sp TxStorage) error) error {
+	nested, ok := tx.(interface {
+		WithinTx(ctx context.Context, fn func(tx TxStorage) error) error
+	})
+	if !ok {
+		return fn(tx)
+	}
+	return nested.WithinTx(ctx, fn)
+}
+
+type batchItem struct {
+	index  int
+	plan   batchPlan
+	failed error
+}
+
+// items разбивает план на планы из одной операции в порядке применения.
+func (p batchPlan) items() []batchItem {
+	var items []batchItem
+	for j, example := range p.creates {
+		items = append(items, batchItem{
+			index:  p.createIndexes[j],
+			plan:   batchPlan{creates: []*models.Example{example}, createIndexes: []int{p.createIndexes[j]}},
+			failed: ErrCreateExampleFailed,
+		})
+	}
+	for j, example := range p.updates {
+		items = append(items, batchItem{
+			index:  p.updateIndexes[j],
+			plan:   batchPlan{updates: []*models.Example{example}, updateIndexes: []int{p.updateIndexes[j]}},
+			failed: ErrUpdateExampleFailed,
+		})
+	}
+	for j, id := range p.deletes {
+		items = append(items, batchItem{
+			index:  p.deleteIndexes[j],
+			plan:   batchPlan{deletes: []int{id}, deleteIndexes: []int{p.deleteIndexes[j]}},
+			failed: ErrDeleteExampleFailed,
+		})
+	}
+	return items
+}
+
+// applyBatch выполняет план внутри транзакции st и проставляет результат
+// каждой операции; каждое изменение попадает в журнал аудита и outbox той же
+// транзакцией. Может вызываться повторно (ретрай транзакции), поэтому
+// перезаписывает результаты целиком. Возвращает ошибку, если хотя бы одна
+// операция не применилась.
+func (s *service) applyBatch(ctx context.Context, st TxStorage, plan batchPlan, results []models.BatchItemResult) error {
+	var errs []error
+
+	before, err := lockBatch(ctx, st, plan)
+	if err != nil {
+		s.logger.Error("Failed to lock examples in batch", slog.String("error", err.Error()))
+		return err
+	}
+
+	if len(plan.creates) > 0 {
+		err := st.CreateExamples(ctx, plan.creates)
+		if err != nil {
+			s.logger.Error("Failed to create examples in batch", slog.String("error", err.Error()))
+			errs = append(errs, err)
+		}
+		for j, idx := range plan.createIndexes {
+			results[idx].Example, results[idx].Err = nil, nil
+			if err != nil {
+				results[idx].Err = ErrCreateExampleFailed
+				continue
+			}
+			example := plan.creates[j]
+			if err := appendExampleAudit(ctx, st, AuditActionCreate, example.ID, nil, example); err != nil {
+				return err
+			}
+			if err := appendExampleMessage(ctx, st, OutboxExampleCreated, example.ID, example); err != nil {
+				return err
+			}
+			results[idx].Example = example
+		}
+	}
+
+	if len(plan.updates) > 0 {
+		itemErrs, err := st.UpdateExamples(ctx, plan.updates)
+		if err != nil {
+			s.logger.Error("Failed to update examples in batch", slog.String("error", err.Error()))
+			errs = append(errs, err)
+		}
+		for j, idx := range plan.updateIndexes {
+			results[idx].Example, results[idx].Err = nil, nil
+			itemErr := batchItemError(err, itemErrs, j, ErrUpdateExampleFailed)
+			if itemErr != nil {
+				results[idx].Err = itemErr
+				errs = append(errs, itemErr)
+				continue
+			}
+			example := plan.updates[j]
+			if err := appendExampleAudit(ctx, st, AuditActionUpdate, example.ID, before[example.ID], example); err != nil {
+				return err
+			}
+			if err := appendExampleMessage(ctx, st, OutboxExampleUpdated, example.ID, example); err != nil {
+				return err
+			}
+			results[idx].Example = example
+		}
+	}
+
+	if len(plan.deletes) > 0 {
+		itemErrs, err := st.DeleteExamples(ctx, plan.deletes)
+		if err != nil {
+			s.logger.Error("Failed to delete examples in batch", slog.String("error", err.Error()))
+			errs = append(errs, err)
+		}
+		for j, idx := range plan.deleteIndexes {
+			results[idx].Err = batchItemError(err, itemErrs, j, ErrDeleteExampleFailed)
+			if results[idx].Err != nil {
+				errs = append(errs, results[idx].Err)
+				continue
+			}
+			id := plan.deletes[j]
+			if err := appendExampleAudit(ctx, st, AuditActionDelete, id, before[id], nil); err != nil {
+				return err
+			}
+			if err := appendExampleMessage(ctx, st, OutboxExampleDeleted, id, models.ExampleDeleted{ID: id}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// lockBatch читает прежнее состояние обновляемых и удаляемых записей для
+// аудита, блокируя строки до конца транзакции. Строки блокируются по
+// возрастанию ID, чтобы встречные пакеты не взаимоблокировались.
+// Отсутствующих записей в результате нет: их операции завершатся
+// ErrExampleNotFound.
+func lockBatch(ctx context.Context, st TxStorage, plan batchPlan) (map[int]*models.Example, error) {
+	ids := make([]int, 0, len(plan.updates)+len(plan.deletes))
+	for _, example := range plan.updates {
+		ids = append(ids, example.ID)
+	}
+	ids = append(ids, plan.deletes...)
+	slices.Sort(ids)
+
+	before := make(map[int]*models.Example, len(ids))
+	for _, id := range ids {
+		example, err := st.GetExampleForUpdate(ctx, id)
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		before[id] = example
+	}
+	return before, nil
+}
+
+func batchItemError(batchErr error, itemErrs []error, i int, failed error) error {
+	if batchErr != nil || i >= len(itemErrs) {
+		return failed
+	}
+	switch {
+	case itemErrs[i] == nil:
+		return nil
+	case errors.Is(itemErrs[i], storageerrors.ErrNotFound):
+		return ErrExampleNotFound
+	default:
+		return failed
+	}
+}
+
+// markAborted помечает откаченными операции атомарного пакета, которые сами по
+// себе прошли бы успешно.
+func markAborted(results []models.BatchItemResult) {
+	for i := range results {
+		results[i].Example = nil
+		if results[i].Err == nil {
+			results[i].Err = ErrBatchAborted
+		}
+	}
+}
+
+func countFailed(results []models.BatchItemResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+func finishBatch(resp *models.BatchResponse, applied bool) *models.BatchResponse {
+	resp.Applied = applied
+	resp.Failed = countFailed(resp.Results)
+	resp.Succeeded = len(resp.Results) - resp.Failed
+	return resp
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+func TestBatchExamples_Validation(t *testing.T) {
+	svc := NewService(&mockStorage{}, testLogger())
+
+	tests := []struct {
+		name string
+		req  *models.BatchRequest
+		err  error
+	}{
+		{"nil request", nil, ErrRequestCannotBeNil},
+		{"empty", &models.BatchRequest{}, ErrBatchEmpty},
+		{"invalid mode", &models.BatchRequest{Mode: "maybe", Operations: []models.BatchOperation{{Op: BatchOpDelete, ID: 1}}}, ErrInvalidBatchMode},
+		{"too large", &models.BatchRequest{Operations: make([]models.BatchOperation, MaxBatchOperations+1)}, ErrBatchTooLarge},
+	}
+
+	for _, tt := range tests {
+		if _, err := svc.BatchExamples(context.Background(), tt.req); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestBatchExamples_Atomic(t *testing.T) {
+	t.Run("invalid item rejects whole batch without touching storage", func(t *testing.T) {
+		st := &mockStorage{
+			withinTxFn: func(context.Context, func(tx TxStorage) error) error {
+				t.Fatal("storage must not be called for an invalid atomic batch")
+				return nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+			Operations: []models.BatchOperation{
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "ok"}},
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: ""}},
+				{Op: "upsert"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Applied || resp.Failed != 3 {
+			t.Fatalf("expected rejected batch with 3 failures, got %+v", resp)
+		}
+		if !errors.Is(resp.Results[0].Err, ErrBatchAborted) ||
+			!errors.Is(resp.Results[1].Err, ErrNameRequired) ||
+			!errors.Is(resp.Results[2].Err, ErrInvalidBatchOp) {
+			t.Fatalf("unexpected item errors: %+v", resp.Results)
+		}
+	})
+
+	t.Run("item failure in storage rolls back", func(t *testing.T) {
+		errRollback := errors.New("rolled back")
+		st := &mockStorage{
+			deleteManyFn: func(_ context.Context, ids []int) ([]error, error) {
+				return []error{nil, storageerrors.ErrNotFound}, nil
+			},
+		}
+		st.withinTxFn = func(_ context.Context, fn func(tx TxStorage) error) error {
+			if err := fn(st); err != nil {
+				return errors.Join(err, errRollback)
+			}
+			return nil
+		}
+		svc := NewService(st, testLogger())
+
+		resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+			Mode: BatchModeAtomic,
+			Operations: []models.BatchOperation{
+				{Op: BatchOpDelete, ID: 1},
+				{Op: BatchOpDelete, ID: 2},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Applied {
+			t.Fatal("expected batch not to be applied")
+		}
+		if !errors.Is(resp.Results[0].Err, ErrBatchAborted) || !errors.Is(resp.Results[1].Err, ErrExampleNotFound) {
+			t.Fatalf("unexpected item errors: %+v", resp.Results)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		st := &mockStorage{
+			createManyFn: func(_ context.Context, examples []*models.Example) error {
+				for i, example := range examples {
+					example.ID = 10 + i
+				}
+				return nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+			Operations: []models.BatchOperation{
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: " a "}},
+				{Op: BatchOpUpdate, ID: 3, Data: &models.ExampleRequest{Name: "b"}},
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "c"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.Applied || resp.Succeeded != 3 {
+			t.Fatalf("expected applied batch, got %+v", resp)
+		}
+		if resp.Results[0].Example.ID != 10 || resp.Results[0].Example.Name != "a" || resp.Results[2].Example.ID != 11 {
+			t.Fatalf("unexpected created examples: %+v, %+v", resp.Results[0].Example, resp.Results[2].Example)
+		}
+		if resp.Results[1].Example.ID != 3 {
+			t.Fatalf("unexpected updated example: %+v", resp.Results[1].Example)
+		}
+	})
+}
+
+func TestBatchExamples_BestEffort(t *testing.T) {
+	var txs, savepoints, depth int
+	st := &mockStorage{
+		updateManyFn: func(_ context.Context, examples []*models.Example) ([]error, error) {
+			return []error{storageerrors.ErrNotFound}, nil
+		},
+	}
+	st.withinTxFn = func(_ context.Context, fn func(tx TxStorage) error) error {
+		if depth == 0 {
+			txs++
+		} else {
+			savepoints++
+		}
+		depth++
+		defer func() { depth-- }()
+		return fn(st)
+	}
+	svc := NewService(st, testLogger())
+
+	resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+		Mode: BatchModeBestEffort,
+		Operations: []models.BatchOperation{
+			{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "ok"}},
+			{Op: BatchOpUpdate, ID: 7, Data: &models.ExampleRequest{Name: "missing"}},
+			{Op: BatchOpDelete, ID: 7},
+			{Op: BatchOpDelete, ID: 0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Applied || resp.Succeeded != 1 || resp.Failed != 3 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if !errors.Is(resp.Results[1].Err, ErrExampleNotFound) ||
+		!errors.Is(resp.Results[2].Err, ErrBatchDuplicateID) ||
+		!errors.Is(resp.Results[3].Err, ErrInvalidExampleID) {
+		t.Fatalf("unexpected item errors: %+v", resp.Results)
+	}
+	// Пачка целиком откатилась до savepoint, затем каждая из двух валидных
+	// операций — в своём savepoint той же транзакции.
+	if txs != 1 || savepoints != 3 {
+		t.Fatalf("expected 1 transaction with 3 savepoints, got %d transactions, %d savepoints", txs, savepoints)
+	}
+}
+
+func TestBatchExamples_BestEffortBulk(t *testing.T) {
+	var calls, created int
+	st := &mockStorage{
+		createManyFn: func(_ context.Context, examples []*models.Example) error {
+			calls++
+			created += len(examples)
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+		Mode: BatchModeBestEffort,
+		Operations: []models.BatchOperation{
+			{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "a"}},
+			{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "b"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Succeeded != 2 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if calls != 1 || created != 2 {
+		t.Fatalf("expected one bulk insert of 2 examples, got %d calls with %d examples", calls, created)
+	}
+}
+
+func TestBatchExamples_BestEffortCommitFailure(t *testing.T) {
+	st := &mockStorage{}
+	st.withinTxFn = func(_ context.Context, fn func(tx TxStorage) error) error {
+		if err := fn(st); err != nil {
+			return err
+		}
+		return errors.New("commit failed")
+	}
+	svc := NewService(st, testLogger())
+
+	resp, err := svc.BatchExamples(context.Background(), &models.BatchRequest{
+		Mode:       BatchModeBestEffort,
+		Operations: []models.BatchOperation{{Op: BatchOpDelete, ID: 3}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(resp.Results[0].Err, ErrDeleteExampleFailed) || resp.Succeeded != 0 {
+		t.Fatalf("expected failed delete, got %+v", resp)
+	}
+}
@@ -3,18 +3,80 @@ package service
 import "errors"
 
 var (
-	ErrExampleNotFound     = errors.New("example not found")
-	ErrInvalidExampleID    = errors.New("example ID must be positive")
-	ErrCreateExampleFailed = errors.New("failed to create example")
-	ErrGetExampleFailed    = errors.New("failed to get example")
-	ErrGetExamplesFailed   = errors.New("failed to get examples")
-	ErrUpdateExampleFailed = errors.New("failed to update example")
-	ErrDeleteExampleFailed = errors.New("failed to delete example")
-	ErrLimitMustBePositive = errors.New("limit must be positive")
-	ErrOffsetMustBeNonNeg  = errors.New("offset must be non-negative")
-	ErrRequestCannotBeNil  = errors.New("request cannot be nil")
-	ErrNameRequired        = errors.New("name is required")
-	ErrNameTooLong         = errors.New("name cannot exceed 255 characters")
-	ErrDescriptionTooLong  = errors.New("description cannot exceed 1000 characters")
-	ErrValueCannotBeNeg    = errors.New("value cannot be negative")
+	ErrExampleNotFound        = errors.New("example not found")
+	ErrInvalidExampleID       = errors.New("example ID must be positive")
+	ErrCreateExampleFailed    = errors.New("failed to create example")
+	ErrGetExampleFailed       = errors.New("failed to get example")
+	ErrGetExamplesFailed      = errors.New("failed to get examples")
+	ErrUpdateExampleFailed    = errors.New("failed to update example")
+	ErrDeleteExampleFailed    = errors.New("failed to delete example")
+	ErrLimitMustBePositive    = errors.New("limit must be positive")
+	ErrOffsetMustBeNonNeg     = errors.New("offset must be non-negative")
+	ErrLimitMustBeNonNeg      = errors.New("limit must be non-negative")
+	ErrRequestCannotBeNil     = errors.New("request cannot be nil")
+	ErrNameRequired           = errors.New("name is required")
+	ErrNameTooLong            = errors.New("name cannot exceed 255 characters")
+	ErrDescriptionTooLong     = errors.New("description cannot exceed 1000 characters")
+	ErrValueCannotBeNeg       = errors.New("value cannot be negative")
+	ErrBatchEmpty             = errors.New("batch must contain at least one operation")
+	ErrBatchTooLarge          = errors.New("batch exceeds maximum number of operations")
+	ErrInvalidBatchMode       = errors.New("batch mode must be atomic or best_effort")
+	ErrInvalidBatchOp         = errors.New("operation must be create, update or delete")
+	ErrBatchDataRequired      = errors.New("data is required for create and update")
+	ErrBatchDuplicateID       = errors.New("example ID appears more than once in batch")
+	ErrBatchFailed            = errors.New("failed to apply batch")
+	ErrInvalidTimeRange       = errors.New("created_after must be earlier than created_before")
+	ErrExportExamplesFailed   = errors.New("failed to export examples")
+	ErrInvalidImportFormat    = errors.New("import format must be csv or ndjson")
+	ErrInvalidImportChunkSize = errors.New("chunk_size must be between 1 and 5000")
+	ErrImportInvalidHeader    = errors.New("csv header must contain external_key and name columns")
+	ErrImportMalformedLine    = errors.New("malformed line")
+	ErrImportInvalidValue     = errors.New("value must be a number")
+	ErrImportInvalidIsActive  = errors.New("is_active must be a boolean")
+	ErrExternalKeyRequired    = errors.New("external_key is required")
+	ErrExternalKeyTooLong     = errors.New("external_key cannot exceed 255 characters")
+	ErrImportChunkFailed      = errors.New("failed to write chunk, rows were not imported")
+	ErrInvalidStatsBucket     = errors.New("bucket must be hour, day, week or month")
+	ErrInvalidTimezone        = errors.New("timezone must be an IANA time zone name")
+	ErrGetExampleStatsFailed  = errors.New("failed to get example stats")
+	ErrSearchQueryRequired    = errors.New("q is required")
+	ErrSearchQueryTooLong     = errors.New("q cannot exceed 256 characters")
+	ErrInvalidSearchMode      = errors.New("mode must be fulltext or fuzzy")
+	ErrSearchExamplesFailed   = errors.New("failed to search examples")
+
+	ErrWebhookNotFound           = errors.New("webhook not found")
+	ErrInvalidWebhookID          = errors.New("webhook ID must be positive")
+	ErrWebhookURLInvalid         = errors.New("url must be an absolute http or https URL")
+	ErrWebhookURLForbidden       = errors.New("url must not point to a loopback, private or link-local address")
+	ErrWebhookSecretTooShort     = errors.New("secret must be at least 16 characters")
+	ErrWebhookSecretTooLong      = errors.New("secret cannot exceed 256 characters")
+	ErrWebhookEventTypesRequired = errors.New("event_types must not be empty")
+	ErrWebhookUnknownEventType   = errors.New("unknown event type")
+	ErrCreateWebhookFailed       = errors.New("failed to create webhook")
+	ErrGetWebhookFailed          = errors.New("failed to get webhook")
+	ErrGetWebhooksFailed         = errors.New("failed to get webhooks")
+	ErrUpdateWebhookFailed       = errors.New("failed to update webhook")
+	ErrDeleteWebhookFailed       = errors.New("failed to delete webhook")
+	ErrGetWebhookAttemptsFailed  = errors.New("failed to get webhook deliveries")
+
+	ErrGetAuditLogFailed = errors.New("failed to get audit log")
+
+	ErrRevisionNotFound       = errors.New("revision not found")
+	ErrInvalidRevision        = errors.New("revision must be positive")
+	ErrRestoreDeletedRevision = errors.New("cannot restore a deleted revision")
+	ErrGetRevisionsFailed     = errors.New("failed to get revisions")
+	ErrGetExampleAsOfFailed   = errors.New("failed to get example as of the given time")
+
+	ErrOperationNotFound       = errors.New("operation not found")
+	ErrInvalidOperationID      = errors.New("operation ID must be positive")
+	ErrOperationFinished       = errors.New("operation has already finished")
+	ErrStartOperationFailed    = errors.New("failed to start operation")
+	ErrGetOperationFailed      = errors.New("failed to get operation")
+	ErrCancelOperationFailed   = errors.New("failed to cancel operation")
+	ErrOperationHasNoOutput    = errors.New("operation has no output file")
+	ErrOperationOutputNotReady = errors.New("operation output is available only after the operation succeeds")
+	ErrInvalidExportFormat     = errors.New("export format must be one of csv, ndjson, json")
+	ErrExportTooLarge          = errors.New("export exceeds the 16 MiB limit of an asynchronous export")
+	ErrPurgeFilterRequired     = errors.New("at least one of is_active, created_after, created_before is required")
+	ErrPurgeExamplesFailed     = errors.New("failed to purge examples")
 )
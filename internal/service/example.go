@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"iter"
 	"log/slog"
 	"strings"
 	"time"
@@ -37,7 +38,16 @@ func (s *service) CreateExample(ctx context.Context, req *models.ExampleRequest)
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := s.storage.CreateExample(ctx, example); err != nil {
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		if err := tx.CreateExample(ctx, example); err != nil {
+			return err
+		}
+		if err := appendExampleAudit(ctx, tx, AuditActionCreate, example.ID, nil, example); err != nil {
+			return err
+		}
+		return appendExampleMessage(ctx, tx, OutboxExampleCreated, example.ID, example)
+	})
+	if err != nil {
 		s.logger.Error("Failed to create example", slog.String("error", err.Error()))
 		return nil, ErrCreateExampleFailed
 	}
@@ -63,18 +73,18 @@ func (s *service) GetExampleByID(ctx context.Context, id int) (*models.Example,
 	return example, nil
 }
 
-func (s *service) GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error) {
-	if limit <= 0 {
+func (s *service) GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error) {
+	if filter.Limit <= 0 {
 		return nil, ErrLimitMustBePositive
 	}
-	if offset < 0 {
-		return nil, ErrOffsetMustBeNonNeg
+	if err := validateExampleFilter(filter); err != nil {
+		return nil, err
 	}
-	if limit > 100 {
-		limit = 100
+	if filter.Limit > 100 {
+		filter.Limit = 100
 	}
 
-	examples, err := s.storage.GetAllExamples(ctx, limit, offset)
+	examples, err := s.storage.GetAllExamples(ctx, filter)
 	if err != nil {
 		s.logger.Error("Failed to get examples", slog.String("error", err.Error()))
 		return nil, ErrGetExamplesFailed
@@ -83,6 +93,54 @@ func (s *service) GetAllExamples(ctx context.Context, limit, offset int) ([]mode
 	return examples, nil
 }
 
+// ExportExamples проверяет фильтр и возвращает итератор по всем подходящим
+// записям без ограничения на размер выборки. Запрос к хранилищу выполняется
+// при обходе; если потребитель прекращает обход (например, клиент
+// отключился), чтение останавливается. Сбой хранилища приходит последним
+// элементом с ошибкой ErrExportExamplesFailed.
+func (s *service) ExportExamples(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+	if err := validateExportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return func(yield func(*models.Example, error) bool) {
+		stopped := false
+		err := s.storage.StreamExamples(ctx, filter, func(example *models.Example) error {
+			if !yield(example, nil) {
+				stopped = true
+				return errExportStopped
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			s.logger.Error("Failed to export examples", slog.String("error", err.Error()))
+			yield(nil, ErrExportExamplesFailed)
+		}
+	}, nil
+}
+
+// validateExportFilter проверяет фильтр выгрузки: limit 0 — без ограничения.
+func validateExportFilter(filter models.ExampleFilter) error {
+	if filter.Limit < 0 {
+		return ErrLimitMustBeNonNeg
+	}
+	return validateExampleFilter(filter)
+}
+
+// errExportStopped прерывает чтение из хранилища, когда потребитель
+// итератора вышел из цикла.
+var errExportStopped = errors.New("export stopped by consumer")
+
+func validateExampleFilter(filter models.ExampleFilter) error {
+	if filter.Offset < 0 {
+		return ErrOffsetMustBeNonNeg
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
 func (s *service) UpdateExample(ctx context.Context, id int, req *models.ExampleRequest) (*models.Example, error) {
 	if id <= 0 {
 		return nil, ErrInvalidExampleID
@@ -101,7 +159,24 @@ func (s *service) UpdateExample(ctx context.Context, id int, req *models.Example
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := s.storage.UpdateExample(ctx, example); err != nil {
+	// Хранилище возвращает итоговую строку тем же запросом, поэтому повторное
+	// чтение не нужно и не может вернуть результат чужой конкурентной записи.
+	// Прежнее состояние для аудита читается с блокировкой строки, чтобы
+	// конкурентное изменение не попало между чтением и обновлением.
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		before, err := tx.GetExampleForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.UpdateExample(ctx, example); err != nil {
+			return err
+		}
+		if err := appendExampleAudit(ctx, tx, AuditActionUpdate, id, before, example); err != nil {
+			return err
+		}
+		return appendExampleMessage(ctx, tx, OutboxExampleUpdated, id, example)
+	})
+	if err != nil {
 		if errors.Is(err, storageerrors.ErrNotFound) {
 			return nil, ErrExampleNotFound
 		}
@@ -110,15 +185,7 @@ func (s *service) UpdateExample(ctx context.Context, id int, req *models.Example
 	}
 
 	s.logger.Info("Example updated successfully", slog.Int("id", id))
-	updatedExample, err := s.storage.GetExampleByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, storageerrors.ErrNotFound) {
-			return nil, ErrExampleNotFound
-		}
-		s.logger.Error("Failed to load updated example", slog.Int("id", id), slog.String("error", err.Error()))
-		return nil, ErrGetExampleFailed
-	}
-	return updatedExample, nil
+	return example, nil
 }
 
 func (s *service) DeleteExample(ctx context.Context, id int) error {
@@ -126,7 +193,20 @@ func (s *service) DeleteExample(ctx context.Context, id int) error {
 		return ErrInvalidExampleID
 	}
 
-	if err := s.storage.DeleteExample(ctx, id); err != nil {
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		before, err := tx.GetExampleForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.DeleteExample(ctx, id); err != nil {
+			return err
+		}
+		if err := appendExampleAudit(ctx, tx, AuditActionDelete, id, before, nil); err != nil {
+			return err
+		}
+		return appendExampleMessage(ctx, tx, OutboxExampleDeleted, id, models.ExampleDeleted{ID: id})
+	})
+	if err != nil {
 		if errors.Is(err, storageerrors.ErrNotFound) {
 			return ErrExampleNotFound
 		}
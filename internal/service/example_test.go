@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"strings"
@@ -17,9 +18,23 @@ type mockStorage struct {
 	pingFn          func(ctx context.Context) error
 	createExampleFn func(ctx context.Context, example *models.Example) error
 	getByIDFn       func(ctx context.Context, id int) (*models.Example, error)
-	getAllFn        func(ctx context.Context, limit, offset int) ([]models.Example, error)
+	getAllFn        func(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error)
+	streamFn        func(ctx context.Context, filter models.ExampleFilter, fn func(*models.Example) error) error
 	updateFn        func(ctx context.Context, example *models.Example) error
 	deleteFn        func(ctx context.Context, id int) error
+	createManyFn    func(ctx context.Context, examples []*models.Example) error
+	updateManyFn    func(ctx context.Context, examples []*models.Example) ([]error, error)
+	deleteManyFn    func(ctx context.Context, ids []int) ([]error, error)
+	upsertFn        func(ctx context.Context, examples []*models.Example) ([]*models.Example, error)
+	withinTxFn      func(ctx context.Context, fn func(tx TxStorage) error) error
+	appendOutboxFn  func(ctx context.Context, msg *models.OutboxMessage) error
+	getForUpdateFn  func(ctx context.Context, id int) (*models.Example, error)
+	appendAuditFn   func(ctx context.Context, entry *models.AuditEntry) error
+	revisionsFn     func(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error)
+	revisionFn      func(ctx context.Context, id, rev int) (*models.ExampleRevision, error)
+	revisionAtFn    func(ctx context.Context, id int, asOf time.Time) (*models.ExampleRevision, error)
+	statsFn         func(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error)
+	searchFn        func(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error)
 }
 
 func (m *mockStorage) Ping(ctx context.Context) error {
@@ -45,11 +60,25 @@ func (m *mockStorage) GetExampleByID(ctx context.Context, id int) (*models.Examp
 	return m.getByIDFn(ctx, id)
 }
 
-func (m *mockStorage) GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error) {
+func (m *mockStorage) GetExampleForUpdate(ctx context.Context, id int) (*models.Example, error) {
+	if m.getForUpdateFn == nil {
+		return nil, nil
+	}
+	return m.getForUpdateFn(ctx, id)
+}
+
+func (m *mockStorage) GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error) {
 	if m.getAllFn == nil {
 		return nil, nil
 	}
-	return m.getAllFn(ctx, limit, offset)
+	return m.getAllFn(ctx, filter)
+}
+
+func (m *mockStorage) StreamExamples(ctx context.Context, filter models.ExampleFilter, fn func(*models.Example) error) error {
+	if m.streamFn == nil {
+		return nil
+	}
+	return m.streamFn(ctx, filter, fn)
 }
 
 func (m *mockStorage) UpdateExample(ctx context.Context, example *models.Example) error {
@@ -66,6 +95,90 @@ func (m *mockStorage) DeleteExample(ctx context.Context, id int) error {
 	return m.deleteFn(ctx, id)
 }
 
+func (m *mockStorage) CreateExamples(ctx context.Context, examples []*models.Example) error {
+	if m.createManyFn == nil {
+		return nil
+	}
+	return m.createManyFn(ctx, examples)
+}
+
+func (m *mockStorage) UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error) {
+	if m.updateManyFn == nil {
+		return make([]error, len(examples)), nil
+	}
+	return m.updateManyFn(ctx, examples)
+}
+
+func (m *mockStorage) DeleteExamples(ctx context.Context, ids []int) ([]error, error) {
+	if m.deleteManyFn == nil {
+		return make([]error, len(ids)), nil
+	}
+	return m.deleteManyFn(ctx, ids)
+}
+
+func (m *mockStorage) UpsertExamples(ctx context.Context, examples []*models.Example) ([]*models.Example, error) {
+	if m.upsertFn == nil {
+		return make([]*models.Example, len(examples)), nil
+	}
+	return m.upsertFn(ctx, examples)
+}
+
+func (m *mockStorage) WithinTx(ctx context.Context, fn func(tx TxStorage) error) error {
+	if m.withinTxFn == nil {
+		return fn(m)
+	}
+	return m.withinTxFn(ctx, fn)
+}
+
+func (m *mockStorage) AppendOutbox(ctx context.Context, msg *models.OutboxMessage) error {
+	if m.appendOutboxFn == nil {
+		return nil
+	}
+	return m.appendOutboxFn(ctx, msg)
+}
+
+func (m *mockStorage) AppendAudit(ctx context.Context, entry *models.AuditEntry) error {
+	if m.appendAuditFn == nil {
+		return nil
+	}
+	return m.appendAuditFn(ctx, entry)
+}
+
+func (m *mockStorage) GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error) {
+	if m.revisionsFn == nil {
+		return nil, nil
+	}
+	return m.revisionsFn(ctx, id, limit, offset)
+}
+
+func (m *mockStorage) GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error) {
+	if m.revisionFn == nil {
+		return nil, storageerrors.ErrNotFound
+	}
+	return m.revisionFn(ctx, id, rev)
+}
+
+func (m *mockStorage) GetExampleRevisionAt(ctx context.Context, id int, asOf time.Time) (*models.ExampleRevision, error) {
+	if m.revisionAtFn == nil {
+		return nil, storageerrors.ErrNotFound
+	}
+	return m.revisionAtFn(ctx, id, asOf)
+}
+
+func (m *mockStorage) GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+	if m.statsFn == nil {
+		return &models.ExampleStats{}, nil
+	}
+	return m.statsFn(ctx, query)
+}
+
+func (m *mockStorage) SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+	if m.searchFn == nil {
+		return []models.ExampleSearchResult{}, nil
+	}
+	return m.searchFn(ctx, query)
+}
+
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
@@ -167,27 +280,32 @@ func TestGetAllExamples_ValidationAndLimitCap(t *testing.T) {
 	t.Run("limit and offset validation", func(t *testing.T) {
 		svc := NewService(&mockStorage{}, testLogger())
 
-		if _, err := svc.GetAllExamples(context.Background(), 0, 0); !errors.Is(err, ErrLimitMustBePositive) {
+		if _, err := svc.GetAllExamples(context.Background(), models.ExampleFilter{}); !errors.Is(err, ErrLimitMustBePositive) {
 			t.Fatalf("expected ErrLimitMustBePositive, got: %v", err)
 		}
-		if _, err := svc.GetAllExamples(context.Background(), 1, -1); !errors.Is(err, ErrOffsetMustBeNonNeg) {
+		if _, err := svc.GetAllExamples(context.Background(), models.ExampleFilter{Limit: 1, Offset: -1}); !errors.Is(err, ErrOffsetMustBeNonNeg) {
 			t.Fatalf("expected ErrOffsetMustBeNonNeg, got: %v", err)
 		}
+		now := time.Now()
+		filter := models.ExampleFilter{Limit: 1, CreatedAfter: &now, CreatedBefore: &now}
+		if _, err := svc.GetAllExamples(context.Background(), filter); !errors.Is(err, ErrInvalidTimeRange) {
+			t.Fatalf("expected ErrInvalidTimeRange, got: %v", err)
+		}
 	})
 
 	t.Run("caps limit to 100", func(t *testing.T) {
 		calledLimit := -1
 		calledOffset := -1
 		st := &mockStorage{
-			getAllFn: func(_ context.Context, limit, offset int) ([]models.Example, error) {
-				calledLimit = limit
-				calledOffset = offset
+			getAllFn: func(_ context.Context, filter models.ExampleFilter) ([]models.Example, error) {
+				calledLimit = filter.Limit
+				calledOffset = filter.Offset
 				return []models.Example{{ID: 1}}, nil
 			},
 		}
 		svc := NewService(st, testLogger())
 
-		got, err := svc.GetAllExamples(context.Background(), 1000, 5)
+		got, err := svc.GetAllExamples(context.Background(), models.ExampleFilter{Limit: 1000, Offset: 5})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -200,6 +318,88 @@ func TestGetAllExamples_ValidationAndLimitCap(t *testing.T) {
 	})
 }
 
+func TestExportExamples(t *testing.T) {
+	rows := func(_ context.Context, _ models.ExampleFilter, fn func(*models.Example) error) error {
+		for i := 1; i <= 3; i++ {
+			if err := fn(&models.Example{ID: i}); err != nil {
+				return fmt.Errorf("wrapped: %w", err)
+			}
+		}
+		return nil
+	}
+
+	t.Run("invalid filter is rejected before streaming", func(t *testing.T) {
+		st := &mockStorage{
+			streamFn: func(context.Context, models.ExampleFilter, func(*models.Example) error) error {
+				t.Fatal("storage must not be queried for an invalid filter")
+				return nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		if _, err := svc.ExportExamples(context.Background(), models.ExampleFilter{Offset: -1}); !errors.Is(err, ErrOffsetMustBeNonNeg) {
+			t.Fatalf("expected ErrOffsetMustBeNonNeg, got %v", err)
+		}
+		if _, err := svc.ExportExamples(context.Background(), models.ExampleFilter{Limit: -1}); !errors.Is(err, ErrLimitMustBeNonNeg) {
+			t.Fatalf("expected ErrLimitMustBeNonNeg, got %v", err)
+		}
+	})
+
+	t.Run("streams all rows", func(t *testing.T) {
+		svc := NewService(&mockStorage{streamFn: rows}, testLogger())
+
+		seq, err := svc.ExportExamples(context.Background(), models.ExampleFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []int
+		for example, err := range seq {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, example.ID)
+		}
+		if len(ids) != 3 {
+			t.Fatalf("expected 3 rows, got %v", ids)
+		}
+	})
+
+	t.Run("consumer stops early", func(t *testing.T) {
+		svc := NewService(&mockStorage{streamFn: rows}, testLogger())
+
+		seq, _ := svc.ExportExamples(context.Background(), models.ExampleFilter{})
+		count := 0
+		for _, err := range seq {
+			if err != nil {
+				t.Fatalf("stopping early must not report an error, got %v", err)
+			}
+			count++
+			break
+		}
+		if count != 1 {
+			t.Fatalf("expected 1 row, got %d", count)
+		}
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		st := &mockStorage{
+			streamFn: func(context.Context, models.ExampleFilter, func(*models.Example) error) error {
+				return errors.New("connection reset")
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		seq, _ := svc.ExportExamples(context.Background(), models.ExampleFilter{})
+		var lastErr error
+		for _, err := range seq {
+			lastErr = err
+		}
+		if !errors.Is(lastErr, ErrExportExamplesFailed) {
+			t.Fatalf("expected ErrExportExamplesFailed, got %v", lastErr)
+		}
+	})
+}
+
 func TestUpdateExample_ErrorPaths(t *testing.T) {
 	t.Run("invalid request data", func(t *testing.T) {
 		svc := NewService(&mockStorage{}, testLogger())
@@ -230,14 +430,33 @@ func TestUpdateExample_ErrorPaths(t *testing.T) {
 		}
 	})
 
-	t.Run("successful update returns loaded record", func(t *testing.T) {
-		now := time.Now()
+	t.Run("unexpected storage error", func(t *testing.T) {
 		st := &mockStorage{
 			updateFn: func(_ context.Context, _ *models.Example) error {
+				return errors.New("connection reset")
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		got, err := svc.UpdateExample(context.Background(), 2, &models.ExampleRequest{Name: "ok"})
+		if !errors.Is(err, ErrUpdateExampleFailed) {
+			t.Fatalf("expected ErrUpdateExampleFailed, got: %v", err)
+		}
+		if got != nil {
+			t.Fatalf("expected nil example, got: %#v", got)
+		}
+	})
+
+	t.Run("successful update returns stored row without re-reading", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Hour)
+		st := &mockStorage{
+			updateFn: func(_ context.Context, example *models.Example) error {
+				example.CreatedAt = createdAt
 				return nil
 			},
-			getByIDFn: func(_ context.Context, id int) (*models.Example, error) {
-				return &models.Example{ID: id, Name: "n", UpdatedAt: now}, nil
+			getByIDFn: func(_ context.Context, _ int) (*models.Example, error) {
+				t.Fatal("UpdateExample must not re-read the row")
+				return nil, nil
 			},
 		}
 		svc := NewService(st, testLogger())
@@ -246,7 +465,7 @@ func TestUpdateExample_ErrorPaths(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if got == nil || got.ID != 2 || got.Name != "n" {
+		if got == nil || got.ID != 2 || got.Name != "n" || !got.CreatedAt.Equal(createdAt) {
 			t.Fatalf("unexpected updated example: %#v", got)
 		}
 	})
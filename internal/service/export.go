@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// Форматы выгрузки.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// ValidExportFormat сообщает, поддерживается ли формат выгрузки.
+func ValidExportFormat(format string) bool {
+	switch format {
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatJSON:
+		return true
+	}
+	return false
+}
+
+// WriteExport пишет все строки в формате format, вызывая flush каждые
+// flushEvery строк с числом уже записанных. Ошибка итератора, записи или
+// flush прерывает выгрузку. Используется и потоковой выгрузкой, и операцией
+// examples.export.
+func WriteExport(format string, w io.Writer, rows iter.Seq2[*models.Example, error], flushEvery int, flush func(rows int) error) (int, error) {
+	enc := newExportEncoder(format, w)
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var err error
+	for example, rowErr := range rows {
+		if rowErr != nil {
+			err = rowErr
+			break
+		}
+		if err = enc.write(example); err != nil {
+			break
+		}
+		count++
+		if count%flushEvery == 0 {
+			if err = flush(count); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return count, err
+	}
+
+	if err := enc.end(); err != nil {
+		return count, err
+	}
+	return count, flush(count)
+}
+
+// exportEncoder сериализует поток записей в один из форматов выгрузки.
+type exportEncoder interface {
+	begin() error
+	write(example *models.Example) error
+	end() error
+}
+
+func newExportEncoder(format string, w io.Writer) exportEncoder {
+	switch format {
+	case ExportFormatNDJSON:
+		return &jsonExportEncoder{w: w, enc: json.NewEncoder(w)}
+	case ExportFormatJSON:
+		return &jsonExportEncoder{w: w, enc: json.NewEncoder(w), array: true}
+	default:
+		return &csvExportEncoder{w: csv.NewWriter(w)}
+	}
+}
+
+var csvExportHeader = []string{"id", "name", "description", "value", "is_active", "created_at", "updated_at", "external_key"}
+
+type csvExportEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvExportEncoder) begin() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvExportEncoder) write(example *models.Example) error {
+	err := e.w.Write([]string{
+		strconv.Itoa(example.ID),
+		example.Name,
+		example.Description,
+		strconv.FormatFloat(example.Value, 'f', -1, 64),
+		strconv.FormatBool(example.IsActive),
+		example.CreatedAt.Format(time.RFC3339Nano),
+		example.UpdatedAt.Format(time.RFC3339Nano),
+		example.ExternalKey,
+	})
+	if err != nil {
+		return err
+	}
+	// csv.Writer буферизует сам; сбрасываем в нижележащий writer сразу,
+	// чтобы размер порции определял только flushEvery.
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExportEncoder пишет NDJSON (объект на строку) или, при array, один
+// JSON-массив, элементы которого разделены переводом строки.
+type jsonExportEncoder struct {
+	w       io.Writer
+	enc     *json.Encoder
+	array   bool
+	started bool
+}
+
+func (e *jsonExportEncoder) begin() error {
+	if !e.array {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "[\n")
+	return err
+}
+
+func (e *jsonExportEncoder) write(example *models.Example) error {
+	if e.array && e.started {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.started = true
+	return e.enc.Encode(example)
+}
+
+func (e *jsonExportEncoder) end() error {
+	if !e.array {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	// DefaultImportChunkSize — сколько строк коммитится одной транзакцией.
+	DefaultImportChunkSize = 500
+	MaxImportChunkSize     = 5000
+
+	// maxImportErrors ограничивает размер отчёта: при массовой ошибке (не тот
+	// файл) отчёт не должен расти вместе с входом.
+	maxImportErrors = 1000
+	// maxImportLineSize — максимальная длина строки NDJSON.
+	maxImportLineSize = 1 << 20
+)
+
+// importLine — разобранная строка входа. err — ошибка разбора этой строки;
+// она попадает в отчёт, а импорт продолжается.
+type importLine struct {
+	line int
+	row  models.ImportRow
+	err  error
+}
+
+// importDecoder читает вход построчно; io.EOF означает конец входа. Другая
+// ошибка next означает, что читать дальше невозможно.
+type importDecoder interface {
+	next() (importLine, error)
+}
+
+type pendingImport struct {
+	line    int
+	example *models.Example
+}
+
+// ImportExamples читает CSV или NDJSON потоково, проверяет каждую строку по
+// тем же правилам, что и CreateExample, и делает upsert по external_key
+// порциями по opts.ChunkSize строк — каждая порция в своей транзакции.
+// Невалидные строки пропускаются и попадают в отчёт. В режиме DryRun
+// выполняется только проверка, хранилище не вызывается. Если один ключ
+// встречается несколько раз, применяется последняя строка.
+func (s *service) ImportExamples(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultImportChunkSize
+	}
+	if chunkSize < 0 || chunkSize > MaxImportChunkSize {
+		return nil, ErrInvalidImportChunkSize
+	}
+
+	dec, err := newImportDecoder(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{DryRun: opts.DryRun, Errors: []models.ImportLineError{}}
+	chunk := make([]pendingImport, 0, chunkSize)
+	keys := make(map[string]struct{}, chunkSize)
+
+	flush := func() {
+		if len(chunk) > 0 {
+			s.applyImportChunk(ctx, chunk, report)
+		}
+		chunk = chunk[:0]
+		clear(keys)
+		if opts.Progress != nil {
+			opts.Progress(report.Total)
+		}
+	}
+
+	for {
+		line, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// Вход оборван или нечитаем: фиксируем место и применяем то, что
+			// уже прочитано.
+			report.Total++
+			addImportError(report, line.line, "", err)
+			break
+		}
+
+		report.Total++
+		if line.err != nil {
+			addImportError(report, line.line, line.row.ExternalKey, line.err)
+			continue
+		}
+
+		example, err := s.importExample(&line.row)
+		if err != nil {
+			addImportError(report, line.line, line.row.ExternalKey, err)
+			continue
+		}
+		report.Valid++
+		if opts.DryRun {
+			continue
+		}
+
+		// Один ключ дважды в одном INSERT ... ON CONFLICT недопустим: сбрасываем
+		// порцию, и более поздняя строка обновит запись следующей порцией.
+		if _, dup := keys[example.ExternalKey]; dup {
+			flush()
+		}
+		chunk = append(chunk, pendingImport{line: line.line, example: example})
+		keys[example.ExternalKey] = struct{}{}
+		if len(chunk) == chunkSize {
+			flush()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if !opts.DryRun && ctx.Err() == nil {
+		flush()
+	}
+
+	s.logger.Info("Import finished",
+		slog.Bool("dry_run", opts.DryRun),
+		slog.Int("total", report.Total),
+		slog.Int("created", report.Created),
+		slog.Int("updated", report.Updated),
+		slog.Int("failed", report.Failed),
+	)
+	return report, ctx.Err()
+}
+
+func (s *service) importExample(row *models.ImportRow) (*models.Example, error) {
+	key := strings.TrimSpace(row.ExternalKey)
+	if key == "" {
+		return nil, ErrExternalKeyRequired
+	}
+	if len(key) > 255 {
+		return nil, ErrExternalKeyTooLong
+	}
+	if err := s.validateExampleRequest(&row.ExampleRequest); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &models.Example{
+		Name:        strings.TrimSpace(row.Name),
+		Description: strings.TrimSpace(row.Description),
+		Value:       row.Value,
+		IsActive:    row.IsActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ExternalKey: key,
+	}, nil
+}
+
+// applyImportChunk записывает порцию одной транзакцией вместе с аудитом и
+// сообщением в outbox по каждой записи. При сбое порция целиком попадает в отчёт как неуспешная,
+// импорт продолжается со следующей.
+func (s *service) applyImportChunk(ctx context.Context, chunk []pendingImport, report *models.ImportReport) {
+	examples := make([]*models.Example, len(chunk))
+	for i, p := range chunk {
+		examples[i] = p.example
+	}
+
+	var previous []*models.Example
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		var err error
+		previous, err = tx.UpsertExamples(ctx, examples)
+		if err != nil {
+			return err
+		}
+		for i, example := range examples {
+			action, eventType := AuditActionCreate, OutboxExampleCreated
+			if previous[i] != nil {
+				action, eventType = AuditActionUpdate, OutboxExampleUpdated
+			}
+			if err := appendExampleAudit(ctx, tx, action, example.ID, previous[i], example); err != nil {
+				return err
+			}
+			if err := appendExampleMessage(ctx, tx, eventType, example.ID, example); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to import chunk",
+			slog.Int("first_line", chunk[0].line),
+			slog.Int("rows", len(chunk)),
+			slog.String("error", err.Error()),
+		)
+		report.Valid -= len(chunk)
+		for _, p := range chunk {
+			addImportError(report, p.line, p.example.ExternalKey, ErrImportChunkFailed)
+		}
+		return
+	}
+
+	for _, before := range previous {
+		if before == nil {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+}
+
+func addImportError(report *models.ImportReport, line int, key string, err error) {
+	report.Failed++
+	if len(report.Errors) >= maxImportErrors {
+		report.ErrorsTruncated = true
+		return
+	}
+	report.Errors = append(report.Errors, models.ImportLineError{Line: line, ExternalKey: key, Error: err.Error()})
+}
+
+func newImportDecoder(r io.Reader, format string) (importDecoder, error) {
+	switch format {
+	case ImportFormatCSV, "":
+		return newCSVImportDecoder(r)
+	case ImportFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+		return &ndjsonImportDecoder{scanner: scanner}, nil
+	default:
+		return nil, ErrInvalidImportFormat
+	}
+}
+
+type ndjsonImportDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (d *ndjsonImportDecoder) next() (importLine, error) {
+	for d.scanner.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		line := importLine{line: d.line}
+		if err := json.Unmarshal(data, &line.row); err != nil {
+			line.err = ErrImportMalformedLine
+		}
+		return line, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return importLine{line: d.line + 1}, err
+	}
+	return importLine{}, io.EOF
+}
+
+// csvImportColumns — колонки, которые понимает импорт. Прочие (id,
+// created_at из экспорта) игнорируются, поэтому результат экспорта можно
+// загрузить обратно.
+var csvImportColumns = []string{"external_key", "name", "description", "value", "is_active"}
+
+type csvImportDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportDecoder(r io.Reader) (*csvImportDecoder, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, ErrImportInvalidHeader
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"external_key", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ErrImportInvalidHeader
+		}
+	}
+
+	return &csvImportDecoder{r: cr, columns: columns}, nil
+}
+
+func (d *csvImportDecoder) next() (importLine, error) {
+	record, err := d.r.Read()
+	if errors.Is(err, io.EOF) {
+		return importLine{}, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return importLine{line: parseErr.StartLine, err: ErrImportMalformedLine}, nil
+		}
+		return importLine{}, err
+	}
+
+	line, _ := d.r.FieldPos(0)
+	result := importLine{line: line}
+	field := func(name string) string {
+		if i, ok := d.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	result.row.ExternalKey = field("external_key")
+	result.row.Name = field("name")
+	result.row.Description = field("description")
+	if v := field("value"); v != "" {
+		if result.row.Value, err = strconv.ParseFloat(v, 64); err != nil {
+			result.err = ErrImportInvalidValue
+			return result, nil
+		}
+	}
+	if v := field("is_active"); v != "" {
+		if result.row.IsActive, err = strconv.ParseBool(v); err != nil {
+			result.err = ErrImportInvalidIsActive
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-service-template/internal/models"
+)
+
+func TestImportExamples_CSV(t *testing.T) {
+	var (
+		chunks  [][]string
+		actions []string
+	)
+	st := &mockStorage{
+		upsertFn: func(_ context.Context, examples []*models.Example) ([]*models.Example, error) {
+			keys := make([]string, len(examples))
+			previous := make([]*models.Example, len(examples))
+			for i, example := range examples {
+				keys[i] = example.ExternalKey
+				if example.ExternalKey == "b" {
+					previous[i] = &models.Example{ExternalKey: "b", Name: "Old"}
+				}
+			}
+			chunks = append(chunks, keys)
+			return previous, nil
+		},
+		appendAuditFn: func(_ context.Context, entry *models.AuditEntry) error {
+			actions = append(actions, entry.Action)
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	input := strings.Join([]string{
+		"id,external_key,name,value,is_active,created_at",
+		"1,a,First,1.5,true,2026-01-01T00:00:00Z",
+		"2,b,Second,2,false,",
+		"3,c,,1,true,",
+		"4,d,Fourth,abc,true,",
+		"5,,NoKey,1,true,",
+		"6,a,First again,3,true,",
+		"7,e,Fifth,5,yes,",
+	}, "\n")
+
+	report, err := svc.ImportExamples(context.Background(), strings.NewReader(input), models.ImportOptions{ChunkSize: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Total != 7 || report.Created != 2 || report.Updated != 1 || report.Failed != 4 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	wantErrors := map[int]error{4: ErrNameRequired, 5: ErrImportInvalidValue, 6: ErrExternalKeyRequired, 8: ErrImportInvalidIsActive}
+	for _, lineErr := range report.Errors {
+		want, ok := wantErrors[lineErr.Line]
+		if !ok || lineErr.Error != want.Error() {
+			t.Errorf("line %d: unexpected error %q", lineErr.Line, lineErr.Error)
+		}
+	}
+	// Повтор ключа "a" сбрасывает порцию, чтобы не попасть дважды в один upsert.
+	if len(chunks) != 2 || strings.Join(chunks[0], ",") != "a,b" || strings.Join(chunks[1], ",") != "a" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+	if strings.Join(actions, ",") != "create,update,create" {
+		t.Fatalf("expected audit entry per imported row, got %v", actions)
+	}
+}
+
+func TestImportExamples_NDJSONChunksAndDryRun(t *testing.T) {
+	var lines []string
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		lines = append(lines, `{"external_key":"`+key+`","name":"n","value":1}`)
+	}
+	lines = append(lines, "", "{broken")
+	input := strings.Join(lines, "\n")
+
+	t.Run("commits in chunks", func(t *testing.T) {
+		calls := 0
+		st := &mockStorage{
+			upsertFn: func(_ context.Context, examples []*models.Example) ([]*models.Example, error) {
+				calls++
+				if len(examples) > 2 {
+					t.Fatalf("chunk too large: %d", len(examples))
+				}
+				previous := make([]*models.Example, len(examples))
+				for i := range previous {
+					previous[i] = &models.Example{}
+				}
+				return previous, nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		report, err := svc.ImportExamples(context.Background(), strings.NewReader(input),
+			models.ImportOptions{Format: ImportFormatNDJSON, ChunkSize: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 3 || report.Updated != 5 || report.Failed != 1 || report.Errors[0].Line != 7 {
+			t.Fatalf("unexpected result: calls=%d report=%+v", calls, report)
+		}
+	})
+
+	t.Run("dry run does not write", func(t *testing.T) {
+		st := &mockStorage{
+			withinTxFn: func(context.Context, func(tx TxStorage) error) error {
+				t.Fatal("dry run must not write")
+				return nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		report, err := svc.ImportExamples(context.Background(), strings.NewReader(input),
+			models.ImportOptions{Format: ImportFormatNDJSON, DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !report.DryRun || report.Valid != 5 || report.Failed != 1 || report.Created+report.Updated != 0 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+	})
+}
+
+func TestImportExamples_Errors(t *testing.T) {
+	t.Run("invalid options", func(t *testing.T) {
+		svc := NewService(&mockStorage{}, testLogger())
+
+		if _, err := svc.ImportExamples(context.Background(), strings.NewReader(""), models.ImportOptions{Format: "xml"}); !errors.Is(err, ErrInvalidImportFormat) {
+			t.Fatalf("expected ErrInvalidImportFormat, got %v", err)
+		}
+		if _, err := svc.ImportExamples(context.Background(), strings.NewReader(""), models.ImportOptions{ChunkSize: MaxImportChunkSize + 1}); !errors.Is(err, ErrInvalidImportChunkSize) {
+			t.Fatalf("expected ErrInvalidImportChunkSize, got %v", err)
+		}
+		if _, err := svc.ImportExamples(context.Background(), strings.NewReader("id,title\n1,x"), models.ImportOptions{}); !errors.Is(err, ErrImportInvalidHeader) {
+			t.Fatalf("expected ErrImportInvalidHeader, got %v", err)
+		}
+	})
+
+	t.Run("failed chunk is reported per line", func(t *testing.T) {
+		st := &mockStorage{
+			upsertFn: func(context.Context, []*models.Example) ([]*models.Example, error) {
+				return nil, errors.New("connection reset")
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		report, err := svc.ImportExamples(context.Background(), strings.NewReader("external_key,name\na,x\nb,y"), models.ImportOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Failed != 2 || report.Valid != 0 || report.Errors[1].Line != 3 || report.Errors[1].Error != ErrImportChunkFailed.Error() {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+	})
+}
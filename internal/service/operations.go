@@ -0,0 +1,480 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+// Статусы операции.
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// Типы операций.
+const (
+	OperationImportExamples = "examples.import"
+	OperationExportExamples = "examples.export"
+	OperationPurgeExamples  = "examples.purge"
+)
+
+const (
+	// operationWatchInterval — как часто выполняющаяся операция сохраняет
+	// прогресс и проверяет, не запрошена ли отмена.
+	operationWatchInterval = time.Second
+	// operationStoreTimeout ограничивает запись итога операции; она
+	// выполняется и после отмены её контекста.
+	operationStoreTimeout = 5 * time.Second
+	// exportProgressRows — через сколько строк выгрузка обновляет прогресс и
+	// проверяет размер файла.
+	exportProgressRows = 500
+	// maxExportOutputSize — предел сжатого файла выгрузки: он собирается в
+	// памяти воркера и хранится в operations.output, поэтому предел держится
+	// небольшим. Для больших выборок сужайте фильтр или используйте потоковый
+	// GET /examples/export.
+	maxExportOutputSize = 16 << 20
+)
+
+type OperationService interface {
+	GetOperation(ctx context.Context, id int64) (*models.Operation, error)
+	// CancelOperation отменяет ожидающую операцию сразу, а у выполняющейся
+	// запрашивает отмену: воркер увидит её через контекст. Для завершённой
+	// операции возвращает ErrOperationFinished.
+	CancelOperation(ctx context.Context, id int64) (*models.Operation, error)
+	// StartImport проверяет параметры и ставит импорт body в очередь.
+	StartImport(ctx context.Context, body []byte, opts models.ImportOptions) (*models.Operation, error)
+	// StartExport проверяет параметры и ставит выгрузку записей под filter
+	// в формате format в очередь.
+	StartExport(ctx context.Context, filter models.ExampleFilter, format string) (*models.Operation, error)
+	// GetOperationOutput возвращает файл успешно завершённой операции:
+	// ErrOperationHasNoOutput, если операция такого типа файла не создаёт,
+	// ErrOperationOutputNotReady — если она ещё не завершилась успешно.
+	GetOperationOutput(ctx context.Context, id int64) (*models.OperationOutput, error)
+	// StartPurge проверяет фильтр и ставит удаление записей под ним в очередь.
+	StartPurge(ctx context.Context, filter models.ExampleFilter) (*models.Operation, error)
+	// RunOperation выполняет операцию; его вызывает обработчик фоновой
+	// задачи. Ошибка означает, что операцию нужно выполнить снова (сбой
+	// хранилища или остановка сервиса); ошибка самой операции записывается в
+	// неё и ошибкой RunOperation не считается.
+	RunOperation(ctx context.Context, id int64) error
+}
+
+// OperationStorage хранит операции.
+type OperationStorage interface {
+	// CreateOperation сохраняет операцию в статусе pending и заполняет её ID и
+	// время создания.
+	CreateOperation(ctx context.Context, op *models.Operation) error
+	GetOperation(ctx context.Context, id int64) (*models.Operation, error)
+	// StartOperation переводит незавершённую операцию в running — или сразу в
+	// cancelled, если отмена уже запрошена, — и возвращает её вместе с
+	// параметрами и входом. ErrNotFound — операции нет или она завершена.
+	StartOperation(ctx context.Context, id int64) (*models.Operation, error)
+	// SaveOperationProgress записывает прогресс выполняющейся операции и
+	// возвращает, запрошена ли отмена.
+	SaveOperationProgress(ctx context.Context, id int64, progress int64) (cancelRequested bool, err error)
+	// FinishOperation завершает незавершённую операцию со статусом status и
+	// очищает её вход.
+	FinishOperation(ctx context.Context, id int64, status string, progress int64, result json.RawMessage, errMsg string) error
+	// CancelOperation переводит ожидающую операцию в cancelled, у
+	// выполняющейся выставляет cancel_requested; завершённую не меняет.
+	// Возвращает операцию после изменения.
+	CancelOperation(ctx context.Context, id int64) (*models.Operation, error)
+	// SaveOperationOutput сохраняет файл-результат выполняющейся операции.
+	SaveOperationOutput(ctx context.Context, id int64, output []byte) error
+	// GetOperationOutput возвращает операцию с параметрами и её
+	// файл-результат (nil, если его нет).
+	GetOperationOutput(ctx context.Context, id int64) (*models.Operation, []byte, error)
+}
+
+// OperationJobArgs — аргументы фоновой задачи, выполняющей операцию.
+type OperationJobArgs struct {
+	OperationID int64 `json:"operation_id"`
+}
+
+func (OperationJobArgs) Kind() string { return "operation.run" }
+
+// operationExecutor выполняет операцию op и возвращает её результат.
+// progress обновляется по ходу работы и периодически сохраняется.
+type operationExecutor func(ctx context.Context, op *models.Operation, progress *atomic.Int64) (any, error)
+
+// exportOperationParams — параметры операции examples.export.
+type exportOperationParams struct {
+	Format        string     `json:"format"`
+	IsActive      *bool      `json:"is_active,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	Limit         int        `json:"limit,omitempty"`
+	Offset        int        `json:"offset,omitempty"`
+}
+
+// importOperationParams — параметры операции examples.import.
+type importOperationParams struct {
+	Format    string `json:"format"`
+	DryRun    bool   `json:"dry_run"`
+	ChunkSize int    `json:"chunk_size"`
+}
+
+type operationService struct {
+	storage   OperationStorage
+	examples  Service
+	enqueue   func(ctx context.Context, id int64) error
+	executors map[string]operationExecutor
+	// watchInterval — operationWatchInterval; в тестах короче.
+	watchInterval time.Duration
+	logger        *slog.Logger
+}
+
+// NewOperationService создаёт сервис операций. enqueue ставит в очередь
+// фоновую задачу, которая вызовет RunOperation для операции id.
+func NewOperationService(storage OperationStorage, examples Service, enqueue func(ctx context.Context, id int64) error, logger *slog.Logger) OperationService {
+	s := &operationService{
+		storage:       storage,
+		examples:      examples,
+		enqueue:       enqueue,
+		watchInterval: operationWatchInterval,
+		logger:        logger,
+	}
+	s.executors = map[string]operationExecutor{
+		OperationImportExamples: s.runImport,
+		OperationExportExamples: s.runExport,
+		OperationPurgeExamples:  s.runPurge,
+	}
+	return s
+}
+
+func (s *operationService) GetOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	if id <= 0 {
+		return nil, ErrInvalidOperationID
+	}
+
+	op, err := s.storage.GetOperation(ctx, id)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrOperationNotFound
+		}
+		s.logger.Error("Failed to get operation", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetOperationFailed
+	}
+	return op, nil
+}
+
+func (s *operationService) CancelOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	if id <= 0 {
+		return nil, ErrInvalidOperationID
+	}
+
+	op, err := s.storage.CancelOperation(ctx, id)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrOperationNotFound
+		}
+		s.logger.Error("Failed to cancel operation", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrCancelOperationFailed
+	}
+	if op.Status != OperationCancelled && !op.CancelRequested {
+		return nil, ErrOperationFinished
+	}
+
+	s.logger.Info("Operation cancellation requested", slog.Int64("id", id), slog.String("status", op.Status))
+	return op, nil
+}
+
+func (s *operationService) StartImport(ctx context.Context, body []byte, opts models.ImportOptions) (*models.Operation, error) {
+	if opts.Format != ImportFormatCSV && opts.Format != ImportFormatNDJSON {
+		return nil, ErrInvalidImportFormat
+	}
+	if opts.ChunkSize < 0 || opts.ChunkSize > MaxImportChunkSize {
+		return nil, ErrInvalidImportChunkSize
+	}
+
+	params := importOperationParams{Format: opts.Format, DryRun: opts.DryRun, ChunkSize: opts.ChunkSize}
+	return s.start(ctx, OperationImportExamples, params, body)
+}
+
+func (s *operationService) StartExport(ctx context.Context, filter models.ExampleFilter, format string) (*models.Operation, error) {
+	if !ValidExportFormat(format) {
+		return nil, ErrInvalidExportFormat
+	}
+	if err := validateExportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	params := exportOperationParams{
+		Format:        format,
+		IsActive:      filter.IsActive,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	}
+	return s.start(ctx, OperationExportExamples, params, nil)
+}
+
+func (s *operationService) GetOperationOutput(ctx context.Context, id int64) (*models.OperationOutput, error) {
+	if id <= 0 {
+		return nil, ErrInvalidOperationID
+	}
+
+	op, output, err := s.storage.GetOperationOutput(ctx, id)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrOperationNotFound
+		}
+		s.logger.Error("Failed to get operation output", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetOperationFailed
+	}
+	if op.Kind != OperationExportExamples {
+		return nil, ErrOperationHasNoOutput
+	}
+	if op.Status != OperationSucceeded || output == nil {
+		return nil, ErrOperationOutputNotReady
+	}
+
+	var params exportOperationParams
+	if err := json.Unmarshal(op.Params, &params); err != nil {
+		s.logger.Error("Failed to decode export params", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetOperationFailed
+	}
+	return &models.OperationOutput{Format: params.Format, Data: output}, nil
+}
+
+func (s *operationService) StartPurge(ctx context.Context, filter models.ExampleFilter) (*models.Operation, error) {
+	if err := validatePurgeFilter(filter); err != nil {
+		return nil, err
+	}
+
+	params := models.PurgeRequest{
+		IsActive:      filter.IsActive,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+	}
+	return s.start(ctx, OperationPurgeExamples, params, nil)
+}
+
+// start сохраняет операцию и ставит в очередь задачу для неё. Если задачу
+// поставить не удалось, операция сразу помечается failed.
+func (s *operationService) start(ctx context.Context, kind string, params any, input []byte) (*models.Operation, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode operation params: %w", err)
+	}
+
+	actor := ActorFrom(ctx)
+	op := &models.Operation{
+		Kind:      kind,
+		Params:    data,
+		Input:     input,
+		Actor:     actor.Principal,
+		RequestID: actor.RequestID,
+		ClientIP:  actor.ClientIP,
+	}
+	if err := s.storage.CreateOperation(ctx, op); err != nil {
+		s.logger.Error("Failed to create operation", slog.String("kind", kind), slog.String("error", err.Error()))
+		return nil, ErrStartOperationFailed
+	}
+
+	if err := s.enqueue(ctx, op.ID); err != nil {
+		s.logger.Error("Failed to schedule operation", slog.Int64("id", op.ID), slog.String("error", err.Error()))
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), operationStoreTimeout)
+		defer cancel()
+		if err := s.storage.FinishOperation(storeCtx, op.ID, OperationFailed, 0, nil, "failed to schedule operation"); err != nil {
+			s.logger.Error("Failed to mark operation failed", slog.Int64("id", op.ID), slog.String("error", err.Error()))
+		}
+		return nil, ErrStartOperationFailed
+	}
+
+	s.logger.Info("Operation started", slog.Int64("id", op.ID), slog.String("kind", kind))
+	return op, nil
+}
+
+func (s *operationService) RunOperation(ctx context.Context, id int64) error {
+	op, err := s.storage.StartOperation(ctx, id)
+	if errors.Is(err, storageerrors.ErrNotFound) {
+		// Уже завершена (например, повторная доставка задачи) или удалена.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("start operation %d: %w", id, err)
+	}
+	if op.Status != OperationRunning {
+		// Отменена до запуска.
+		return nil
+	}
+
+	logger := s.logger.With(slog.Int64("operation_id", op.ID), slog.String("kind", op.Kind))
+	storeCtx, cancelStore := context.WithTimeout(context.WithoutCancel(ctx), operationStoreTimeout)
+	defer cancelStore()
+
+	execute, ok := s.executors[op.Kind]
+	if !ok {
+		logger.Error("Unknown operation kind")
+		return s.storage.FinishOperation(storeCtx, op.ID, OperationFailed, 0, nil, "unknown operation kind")
+	}
+
+	// Изменения операции записываются в аудит от имени её инициатора.
+	ctx = WithActor(ctx, Actor{Principal: op.Actor, RequestID: op.RequestID, ClientIP: op.ClientIP})
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var progress atomic.Int64
+	var cancelled atomic.Bool
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		if s.watch(runCtx, op.ID, &progress, logger) {
+			cancelled.Store(true)
+			cancel()
+		}
+	}()
+
+	result, execErr := execute(runCtx, op, &progress)
+	cancel()
+	<-watchDone
+
+	status, errMsg := OperationSucceeded, ""
+	switch {
+	case execErr == nil:
+	case cancelled.Load():
+		status, errMsg = OperationCancelled, "operation cancelled"
+	case ctx.Err() != nil:
+		// Остановка сервиса или потерянный захват задачи: операцию выполнит
+		// заново другой воркер.
+		logger.Info("Operation interrupted", slog.Int64("progress", progress.Load()))
+		return ctx.Err()
+	default:
+		status, errMsg = OperationFailed, execErr.Error()
+	}
+
+	var data json.RawMessage
+	if result != nil {
+		if data, err = json.Marshal(result); err != nil {
+			return fmt.Errorf("encode operation %d result: %w", op.ID, err)
+		}
+	}
+	if err := s.storage.FinishOperation(storeCtx, op.ID, status, progress.Load(), data, errMsg); err != nil {
+		return fmt.Errorf("finish operation %d: %w", op.ID, err)
+	}
+
+	logger.Info("Operation finished", slog.String("status", status), slog.Int64("progress", progress.Load()))
+	return nil
+}
+
+// watch раз в watchInterval сохраняет прогресс, пока ctx не отменён.
+// Возвращает true, если запрошена отмена операции.
+func (s *operationService) watch(ctx context.Context, id int64, progress *atomic.Int64, logger *slog.Logger) bool {
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		cancelRequested, err := s.storage.SaveOperationProgress(ctx, id, progress.Load())
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Failed to save operation progress", slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if cancelRequested {
+			return true
+		}
+	}
+}
+
+func (s *operationService) runImport(ctx context.Context, op *models.Operation, progress *atomic.Int64) (any, error) {
+	var params importOperationParams
+	if err := json.Unmarshal(op.Params, &params); err != nil {
+		return nil, fmt.Errorf("decode import params: %w", err)
+	}
+
+	report, err := s.examples.ImportExamples(ctx, bytes.NewReader(op.Input), models.ImportOptions{
+		Format:    params.Format,
+		DryRun:    params.DryRun,
+		ChunkSize: params.ChunkSize,
+		Progress: func(processed int) {
+			progress.Store(int64(processed))
+		},
+	})
+	if report == nil {
+		return nil, err
+	}
+	return report, err
+}
+
+func (s *operationService) runPurge(ctx context.Context, op *models.Operation, progress *atomic.Int64) (any, error) {
+	var params models.PurgeRequest
+	if err := json.Unmarshal(op.Params, &params); err != nil {
+		return nil, fmt.Errorf("decode purge params: %w", err)
+	}
+
+	filter := models.ExampleFilter{
+		IsActive:      params.IsActive,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+	}
+	deleted, err := s.examples.PurgeExamples(ctx, filter, func(deleted int) {
+		progress.Store(int64(deleted))
+	})
+	return models.PurgeResult{Deleted: deleted}, err
+}
+
+// runExport собирает выгрузку в память, сжимая gzip, и сохраняет файл в
+// операции. Строки читаются курсором, как у потоковой выгрузки.
+func (s *operationService) runExport(ctx context.Context, op *models.Operation, progress *atomic.Int64) (any, error) {
+	var params exportOperationParams
+	if err := json.Unmarshal(op.Params, &params); err != nil {
+		return nil, fmt.Errorf("decode export params: %w", err)
+	}
+
+	rows, err := s.examples.ExportExamples(ctx, models.ExampleFilter{
+		IsActive:      params.IsActive,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Limit:         params.Limit,
+		Offset:        params.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	count, err := WriteExport(params.Format, gz, rows, exportProgressRows, func(rows int) error {
+		progress.Store(int64(rows))
+		if buf.Len() > maxExportOutputSize {
+			return ErrExportTooLarge
+		}
+		return nil
+	})
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil && buf.Len() > maxExportOutputSize {
+		err = ErrExportTooLarge
+	}
+	if err != nil {
+		return models.ExportResult{Format: params.Format, Rows: count}, err
+	}
+
+	if err := s.storage.SaveOperationOutput(ctx, op.ID, buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("save export output: %w", err)
+	}
+	return models.ExportResult{Format: params.Format, Rows: count, Size: buf.Len()}, nil
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// fakeOperationStorage хранит одну операцию в памяти.
+type fakeOperationStorage struct {
+	mu              sync.Mutex
+	op              *models.Operation
+	cancelRequested bool
+	saved           int
+	output          []byte
+}
+
+func (s *fakeOperationStorage) CreateOperation(_ context.Context, op *models.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op.ID = 1
+	op.Status = OperationPending
+	op.CreatedAt = time.Now()
+	s.op = op
+	return nil
+}
+
+func (s *fakeOperationStorage) GetOperation(context.Context, int64) (*models.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op := *s.op
+	return &op, nil
+}
+
+func (s *fakeOperationStorage) StartOperation(context.Context, int64) (*models.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.op.Status = OperationRunning
+	op := *s.op
+	return &op, nil
+}
+
+func (s *fakeOperationStorage) SaveOperationProgress(_ context.Context, _ int64, progress int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.op.Progress = progress
+	s.saved++
+	return s.cancelRequested, nil
+}
+
+func (s *fakeOperationStorage) FinishOperation(_ context.Context, _ int64, status string, progress int64, result json.RawMessage, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.op.Status, s.op.Progress, s.op.Result, s.op.Error = status, progress, result, errMsg
+	return nil
+}
+
+func (s *fakeOperationStorage) CancelOperation(context.Context, int64) (*models.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.op.Status {
+	case OperationPending:
+		s.op.Status = OperationCancelled
+	case OperationRunning:
+		s.op.CancelRequested = true
+	}
+	op := *s.op
+	return &op, nil
+}
+
+func (s *fakeOperationStorage) SaveOperationOutput(_ context.Context, _ int64, output []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = output
+	return nil
+}
+
+func (s *fakeOperationStorage) GetOperationOutput(context.Context, int64) (*models.Operation, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op := *s.op
+	return &op, s.output, nil
+}
+
+// stubExamples переопределяет только методы Service, нужные операциям.
+type stubExamples struct {
+	Service
+	importFn func(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error)
+	purgeFn  func(ctx context.Context, filter models.ExampleFilter, progress func(deleted int)) (int, error)
+	exportFn func(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error)
+}
+
+func (s *stubExamples) ExportExamples(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+	return s.exportFn(ctx, filter)
+}
+
+func (s *stubExamples) ImportExamples(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	return s.importFn(ctx, r, opts)
+}
+
+func (s *stubExamples) PurgeExamples(ctx context.Context, filter models.ExampleFilter, progress func(deleted int)) (int, error) {
+	return s.purgeFn(ctx, filter, progress)
+}
+
+func newTestOperationService(storage OperationStorage, examples Service, enqueue func(ctx context.Context, id int64) error) *operationService {
+	if enqueue == nil {
+		enqueue = func(context.Context, int64) error { return nil }
+	}
+	svc := NewOperationService(storage, examples, enqueue, testLogger()).(*operationService)
+	svc.watchInterval = 10 * time.Millisecond
+	return svc
+}
+
+func TestOperationService_StartAndRunImport(t *testing.T) {
+	storage := &fakeOperationStorage{}
+	var actor Actor
+	examples := &stubExamples{
+		importFn: func(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+			actor = ActorFrom(ctx)
+			body, _ := io.ReadAll(r)
+			if string(body) != "name,value\na,1\n" || opts.Format != ImportFormatCSV || !opts.DryRun {
+				t.Errorf("unexpected import input %q with options %+v", body, opts)
+			}
+			opts.Progress(1)
+			return &models.ImportReport{Total: 1, Created: 1}, nil
+		},
+	}
+	var enqueued int64
+	svc := newTestOperationService(storage, examples, func(_ context.Context, id int64) error {
+		enqueued = id
+		return nil
+	})
+
+	ctx := WithActor(context.Background(), Actor{Principal: "alice", RequestID: "req-1"})
+	op, err := svc.StartImport(ctx, []byte("name,value\na,1\n"), models.ImportOptions{Format: ImportFormatCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op.Status != OperationPending || enqueued != op.ID {
+		t.Fatalf("expected pending operation %d enqueued, got %+v, enqueued %d", op.ID, op, enqueued)
+	}
+
+	if err := svc.RunOperation(context.Background(), op.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if storage.op.Status != OperationSucceeded || storage.op.Progress != 1 {
+		t.Errorf("expected succeeded operation with progress 1, got %+v", storage.op)
+	}
+	var report models.ImportReport
+	if err := json.Unmarshal(storage.op.Result, &report); err != nil || report.Created != 1 {
+		t.Errorf("unexpected result %s (%v)", storage.op.Result, err)
+	}
+	if actor.Principal != "alice" || actor.RequestID != "req-1" {
+		t.Errorf("expected initiator actor restored, got %+v", actor)
+	}
+}
+
+func TestOperationService_StartAndRunExport(t *testing.T) {
+	storage := &fakeOperationStorage{}
+	examples := &stubExamples{
+		exportFn: func(_ context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error) {
+			if filter.IsActive == nil || !*filter.IsActive || filter.Limit != 2 {
+				t.Errorf("unexpected export filter %+v", filter)
+			}
+			return func(yield func(*models.Example, error) bool) {
+				_ = yield(&models.Example{ID: 1, Name: "a"}, nil) && yield(&models.Example{ID: 2, Name: "b"}, nil)
+			}, nil
+		},
+	}
+	svc := newTestOperationService(storage, examples, nil)
+
+	active := true
+	op, err := svc.StartExport(context.Background(), models.ExampleFilter{IsActive: &active, Limit: 2}, ExportFormatNDJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetOperationOutput(context.Background(), op.ID); !errors.Is(err, ErrOperationOutputNotReady) {
+		t.Fatalf("expected ErrOperationOutputNotReady before the run, got %v", err)
+	}
+
+	if err := svc.RunOperation(context.Background(), op.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if storage.op.Status != OperationSucceeded || storage.op.Progress != 2 {
+		t.Fatalf("expected succeeded operation with progress 2, got %+v", storage.op)
+	}
+	var result models.ExportResult
+	if err := json.Unmarshal(storage.op.Result, &result); err != nil || result.Rows != 2 || result.Size != len(storage.output) {
+		t.Errorf("unexpected result %s (%v)", storage.op.Result, err)
+	}
+
+	output, err := svc.GetOperationOutput(context.Background(), op.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(output.Data))
+	if err != nil {
+		t.Fatalf("expected gzip output: %v", err)
+	}
+	body, _ := io.ReadAll(gz)
+	if output.Format != ExportFormatNDJSON || strings.Count(string(body), "\n") != 2 || !strings.Contains(string(body), `"name":"b"`) {
+		t.Errorf("unexpected output %s: %q", output.Format, body)
+	}
+}
+
+func TestOperationService_StartFailures(t *testing.T) {
+	t.Run("purge requires filter", func(t *testing.T) {
+		svc := newTestOperationService(&fakeOperationStorage{}, &stubExamples{}, nil)
+		if _, err := svc.StartPurge(context.Background(), models.ExampleFilter{}); !errors.Is(err, ErrPurgeFilterRequired) {
+			t.Fatalf("expected ErrPurgeFilterRequired, got %v", err)
+		}
+	})
+
+	t.Run("invalid import format", func(t *testing.T) {
+		svc := newTestOperationService(&fakeOperationStorage{}, &stubExamples{}, nil)
+		if _, err := svc.StartImport(context.Background(), nil, models.ImportOptions{Format: "xml"}); !errors.Is(err, ErrInvalidImportFormat) {
+			t.Fatalf("expected ErrInvalidImportFormat, got %v", err)
+		}
+	})
+
+	t.Run("invalid export format", func(t *testing.T) {
+		svc := newTestOperationService(&fakeOperationStorage{}, &stubExamples{}, nil)
+		if _, err := svc.StartExport(context.Background(), models.ExampleFilter{}, "xml"); !errors.Is(err, ErrInvalidExportFormat) {
+			t.Fatalf("expected ErrInvalidExportFormat, got %v", err)
+		}
+	})
+
+	t.Run("output of an operation without a file", func(t *testing.T) {
+		storage := &fakeOperationStorage{op: &models.Operation{ID: 1, Kind: OperationPurgeExamples, Status: OperationSucceeded}}
+		svc := newTestOperationService(storage, &stubExamples{}, nil)
+		if _, err := svc.GetOperationOutput(context.Background(), 1); !errors.Is(err, ErrOperationHasNoOutput) {
+			t.Fatalf("expected ErrOperationHasNoOutput, got %v", err)
+		}
+	})
+
+	t.Run("enqueue failure marks operation failed", func(t *testing.T) {
+		storage := &fakeOperationStorage{}
+		svc := newTestOperationService(storage, &stubExamples{}, func(context.Context, int64) error {
+			return errors.New("queue unavailable")
+		})
+		active := true
+		if _, err := svc.StartPurge(context.Background(), models.ExampleFilter{IsActive: &active}); !errors.Is(err, ErrStartOperationFailed) {
+			t.Fatalf("expected ErrStartOperationFailed, got %v", err)
+		}
+		if storage.op.Status != OperationFailed {
+			t.Errorf("expected operation failed, got %q", storage.op.Status)
+		}
+	})
+}
+
+func TestOperationService_RunCancelled(t *testing.T) {
+	storage := &fakeOperationStorage{}
+	examples := &stubExamples{
+		purgeFn: func(ctx context.Context, _ models.ExampleFilter, progress func(deleted int)) (int, error) {
+			progress(500)
+			storage.mu.Lock()
+			storage.cancelRequested = true
+			storage.mu.Unlock()
+			<-ctx.Done()
+			return 500, ctx.Err()
+		},
+	}
+	svc := newTestOperationService(storage, examples, nil)
+
+	active := false
+	op, err := svc.StartPurge(context.Background(), models.ExampleFilter{IsActive: &active})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RunOperation(context.Background(), op.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if storage.op.Status != OperationCancelled || storage.op.Progress != 500 {
+		t.Errorf("expected cancelled operation with progress 500, got %+v", storage.op)
+	}
+	if string(storage.op.Result) != `{"deleted":500}` {
+		t.Errorf("expected partial result, got %s", storage.op.Result)
+	}
+}
+
+func TestOperationService_RunInterrupted(t *testing.T) {
+	storage := &fakeOperationStorage{}
+	ctx, stop := context.WithCancel(context.Background())
+	examples := &stubExamples{
+		purgeFn: func(ctx context.Context, _ models.ExampleFilter, _ func(deleted int)) (int, error) {
+			stop()
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	}
+	svc := newTestOperationService(storage, examples, nil)
+
+	active := true
+	op, _ := svc.StartPurge(context.Background(), models.ExampleFilter{IsActive: &active})
+	if err := svc.RunOperation(ctx, op.ID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled so the job is retried, got %v", err)
+	}
+	if storage.op.Status != OperationRunning {
+		t.Errorf("expected interrupted operation to stay running, got %q", storage.op.Status)
+	}
+}
+
+func TestOperationService_CancelOperation(t *testing.T) {
+	storage := &fakeOperationStorage{op: &models.Operation{ID: 1, Status: OperationPending}}
+	svc := newTestOperationService(storage, &stubExamples{}, nil)
+
+	op, err := svc.CancelOperation(context.Background(), 1)
+	if err != nil || op.Status != OperationCancelled {
+		t.Fatalf("expected pending operation cancelled, got %+v (%v)", op, err)
+	}
+
+	storage.op.Status = OperationRunning
+	if op, err := svc.CancelOperation(context.Background(), 1); err != nil || !op.CancelRequested {
+		t.Fatalf("expected cancellation requested, got %+v (%v)", op, err)
+	}
+
+	storage.op = &models.Operation{ID: 1, Status: OperationSucceeded}
+	if _, err := svc.CancelOperation(context.Background(), 1); !errors.Is(err, ErrOperationFinished) {
+		t.Fatalf("expected ErrOperationFinished, got %v", err)
+	}
+
+	if _, err := svc.CancelOperation(context.Background(), 0); !errors.Is(err, ErrInvalidOperationID) {
+		t.Fatalf("expected ErrInvalidOperationID, got %v", err)
+	}
+}
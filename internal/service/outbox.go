@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"go-service-template/internal/models"
+)
+
+// Типы сообщений outbox об изменениях examples. Payload created и updated —
+// запись после изменения, deleted — models.ExampleDeleted.
+const (
+	OutboxAggregateExample = "example"
+
+	OutboxExampleCreated = "example.created"
+	OutboxExampleUpdated = "example.updated"
+	OutboxExampleDeleted = "example.deleted"
+)
+
+// appendExampleMessage добавляет в outbox_messages строку eventType по
+// записи id с payload в JSON; relay опубликует её после коммита tx.
+func appendExampleMessage(ctx context.Context, tx TxStorage, eventType string, id int, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox payload: %w", err)
+	}
+
+	return tx.AppendOutbox(ctx, &models.OutboxMessage{
+		AggregateType: OutboxAggregateExample,
+		AggregateID:   strconv.Itoa(id),
+		EventType:     eventType,
+		Payload:       data,
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+// outboxRecorder — mockStorage, который запоминает сообщения outbox и
+// проверяет, что они пишутся внутри WithinTx.
+func outboxRecorder(t *testing.T) (*mockStorage, *[]models.OutboxMessage) {
+	t.Helper()
+	var (
+		messages []models.OutboxMessage
+		inTx     bool
+	)
+	st := &mockStorage{
+		createExampleFn: func(_ context.Context, example *models.Example) error {
+			example.ID = 7
+			return nil
+		},
+		appendOutboxFn: func(_ context.Context, msg *models.OutboxMessage) error {
+			if !inTx {
+				t.Error("outbox message written outside of transaction")
+			}
+			messages = append(messages, *msg)
+			return nil
+		},
+	}
+	st.withinTxFn = func(_ context.Context, fn func(tx TxStorage) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(st)
+	}
+	return st, &messages
+}
+
+func TestExampleChanges_WriteOutbox(t *testing.T) {
+	ctx := context.Background()
+	st, messages := outboxRecorder(t)
+	svc := NewService(st, testLogger())
+	req := &models.ExampleRequest{Name: "name"}
+
+	if _, err := svc.CreateExample(ctx, req); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.UpdateExample(ctx, 7, req); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := svc.DeleteExample(ctx, 7); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []string{OutboxExampleCreated, OutboxExampleUpdated, OutboxExampleDeleted}
+	if len(*messages) != len(want) {
+		t.Fatalf("expected %d outbox messages, got %+v", len(want), *messages)
+	}
+	for i, msg := range *messages {
+		if msg.EventType != want[i] || msg.AggregateType != OutboxAggregateExample || msg.AggregateID != "7" {
+			t.Errorf("message %d: unexpected %+v", i, msg)
+		}
+		var payload struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.ID != 7 {
+			t.Errorf("message %d: unexpected payload %s", i, msg.Payload)
+		}
+	}
+}
+
+func TestExampleChanges_OutboxFailureFailsOperation(t *testing.T) {
+	ctx := context.Background()
+	st := &mockStorage{
+		appendOutboxFn: func(context.Context, *models.OutboxMessage) error {
+			return errors.New("outbox unavailable")
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	if _, err := svc.CreateExample(ctx, &models.ExampleRequest{Name: "name"}); !errors.Is(err, ErrCreateExampleFailed) {
+		t.Errorf("expected ErrCreateExampleFailed, got %v", err)
+	}
+	if err := svc.DeleteExample(ctx, 1); !errors.Is(err, ErrDeleteExampleFailed) {
+		t.Errorf("expected ErrDeleteExampleFailed, got %v", err)
+	}
+
+	// Отсутствующая запись не порождает сообщения.
+	st.deleteFn = func(context.Context, int) error { return storageerrors.ErrNotFound }
+	st.appendOutboxFn = func(context.Context, *models.OutboxMessage) error {
+		t.Error("unexpected outbox message for missing example")
+		return nil
+	}
+	if err := svc.DeleteExample(ctx, 1); !errors.Is(err, ErrExampleNotFound) {
+		t.Errorf("expected ErrExampleNotFound, got %v", err)
+	}
+}
+
+func TestBatchAndImport_WriteOutbox(t *testing.T) {
+	ctx := context.Background()
+	st, messages := outboxRecorder(t)
+	st.createManyFn = func(_ context.Context, examples []*models.Example) error {
+		for _, example := range examples {
+			example.ID = 7
+		}
+		return nil
+	}
+	st.upsertFn = func(_ context.Context, examples []*models.Example) ([]*models.Example, error) {
+		for _, example := range examples {
+			example.ID = 7
+		}
+		return []*models.Example{nil, {ID: 7}}, nil
+	}
+	svc := NewService(st, testLogger())
+
+	for _, mode := range []string{BatchModeAtomic, BatchModeBestEffort} {
+		*messages = nil
+		resp, err := svc.BatchExamples(ctx, &models.BatchRequest{
+			Mode: mode,
+			Operations: []models.BatchOperation{
+				{Op: BatchOpCreate, Data: &models.ExampleRequest{Name: "a"}},
+				{Op: BatchOpUpdate, ID: 7, Data: &models.ExampleRequest{Name: "b"}},
+				{Op: BatchOpDelete, ID: 8},
+			},
+		})
+		if err != nil || resp.Failed != 0 {
+			t.Fatalf("%s: unexpected result %+v, err %v", mode, resp, err)
+		}
+		if got := outboxEventTypes(*messages); got != "example.created,example.updated,example.deleted" {
+			t.Errorf("%s: unexpected outbox messages %s", mode, got)
+		}
+	}
+
+	*messages = nil
+	_, err := svc.ImportExamples(ctx, strings.NewReader("external_key,name\na,x\nb,y"), models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if got := outboxEventTypes(*messages); got != "example.created,example.updated" {
+		t.Errorf("import: unexpected outbox messages %s", got)
+	}
+}
+
+func outboxEventTypes(messages []models.OutboxMessage) string {
+	types := make([]string, len(messages))
+	for i, msg := range messages {
+		types[i] = msg.EventType
+	}
+	return strings.Join(types, ",")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+// purgeChunkSize — сколько записей удаляется одной транзакцией.
+const purgeChunkSize = 500
+
+// PurgeExamples удаляет все записи под фильтром порциями по purgeChunkSize —
+// каждая в своей транзакции, с аудитом и сообщением в outbox, как у
+// DeleteExample. progress вызывается после каждой порции с числом удалённых
+// записей. При отмене ctx возвращает удалённое к этому моменту и ctx.Err().
+func (s *service) PurgeExamples(ctx context.Context, filter models.ExampleFilter, progress func(deleted int)) (int, error) {
+	filter.Limit, filter.Offset = purgeChunkSize, 0
+	if err := validatePurgeFilter(filter); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for ctx.Err() == nil {
+		// Удалённые записи выпадают из выборки, поэтому каждая порция — первая
+		// страница фильтра.
+		examples, err := s.storage.GetAllExamples(ctx, filter)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			s.logger.Error("Failed to select examples to purge", slog.String("error", err.Error()))
+			return deleted, ErrPurgeExamplesFailed
+		}
+		if len(examples) == 0 {
+			break
+		}
+
+		n, err := s.purgeChunk(ctx, examples)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			s.logger.Error("Failed to purge examples", slog.Int("deleted", deleted), slog.String("error", err.Error()))
+			return deleted, ErrPurgeExamplesFailed
+		}
+		deleted += n
+		if progress != nil {
+			progress(deleted)
+		}
+	}
+
+	s.logger.Info("Examples purged", slog.Int("deleted", deleted))
+	return deleted, ctx.Err()
+}
+
+// validatePurgeFilter не даёт удалить все записи случайно: нужно хотя бы
+// одно условие.
+func validatePurgeFilter(filter models.ExampleFilter) error {
+	if filter.IsActive == nil && filter.CreatedAfter == nil && filter.CreatedBefore == nil {
+		return ErrPurgeFilterRequired
+	}
+	return validateExampleFilter(filter)
+}
+
+// purgeChunk удаляет порцию одной транзакцией. Записи, удалённые
+// конкурентно, пропускаются.
+func (s *service) purgeChunk(ctx context.Context, examples []models.Example) (int, error) {
+	var deleted int
+	err := s.storage.WithinTx(ctx, func(tx TxStorage) error {
+		deleted = 0
+		for _, example := range examples {
+			before, err := tx.GetExampleForUpdate(ctx, example.ID)
+			if errors.Is(err, storageerrors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.DeleteExample(ctx, example.ID); err != nil {
+				return err
+			}
+			if err := appendExampleAudit(ctx, tx, AuditActionDelete, example.ID, before, nil); err != nil {
+				return err
+			}
+			if err := appendExampleMessage(ctx, tx, OutboxExampleDeleted, example.ID, models.ExampleDeleted{ID: example.ID}); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})
+	return deleted, err
+}
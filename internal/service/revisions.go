@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+// Операции, после которых записана версия.
+const (
+	RevisionCreated = "created"
+	RevisionUpdated = "updated"
+	RevisionDeleted = "deleted"
+)
+
+// revisionIgnoredFields не сравниваются в диффе: они меняются при каждом
+// изменении или не меняются никогда.
+var revisionIgnoredFields = []string{"id", "created_at", "updated_at"}
+
+func (s *service) GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error) {
+	if id <= 0 {
+		return nil, ErrInvalidExampleID
+	}
+	if limit <= 0 {
+		return nil, ErrLimitMustBePositive
+	}
+	if offset < 0 {
+		return nil, ErrOffsetMustBeNonNeg
+	}
+	limit = min(limit, 100)
+
+	revisions, err := s.storage.GetExampleRevisions(ctx, id, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to get revisions", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetRevisionsFailed
+	}
+	// У любой когда-либо существовавшей записи есть хотя бы одна версия.
+	if len(revisions) == 0 && offset == 0 {
+		return nil, ErrExampleNotFound
+	}
+
+	return revisions, nil
+}
+
+func (s *service) GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error) {
+	if id <= 0 {
+		return nil, ErrInvalidExampleID
+	}
+	if rev <= 0 {
+		return nil, ErrInvalidRevision
+	}
+
+	revision, err := s.storage.GetExampleRevision(ctx, id, rev)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		s.logger.Error("Failed to get revision", slog.Int("id", id), slog.Int("revision", rev), slog.String("error", err.Error()))
+		return nil, ErrGetRevisionsFailed
+	}
+
+	return revision, nil
+}
+
+// GetExampleAsOf возвращает запись в состоянии на момент asOf. Если запись
+// тогда ещё не была создана или уже была удалена — ErrExampleNotFound.
+func (s *service) GetExampleAsOf(ctx context.Context, id int, asOf time.Time) (*models.Example, error) {
+	if id <= 0 {
+		return nil, ErrInvalidExampleID
+	}
+
+	revision, err := s.storage.GetExampleRevisionAt(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrExampleNotFound
+		}
+		s.logger.Error("Failed to get example as of", slog.Int("id", id), slog.Time("as_of", asOf), slog.String("error", err.Error()))
+		return nil, ErrGetExampleAsOfFailed
+	}
+	if revision.Op == RevisionDeleted {
+		return nil, ErrExampleNotFound
+	}
+
+	return &revision.Example, nil
+}
+
+// RestoreExampleRevision возвращает записи поля версии rev. Восстановление —
+// обычное обновление: оно попадает в аудит, outbox и создаёт новую версию.
+// Удалённую запись восстановить нельзя.
+func (s *service) RestoreExampleRevision(ctx context.Context, id, rev int) (*models.Example, error) {
+	revision, err := s.GetExampleRevision(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	if revision.Op == RevisionDeleted {
+		return nil, ErrRestoreDeletedRevision
+	}
+
+	example, err := s.UpdateExample(ctx, id, &models.ExampleRequest{
+		Name:        revision.Example.Name,
+		Description: revision.Example.Description,
+		Value:       revision.Example.Value,
+		IsActive:    revision.Example.IsActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Example restored from revision", slog.Int("id", id), slog.Int("revision", rev))
+	return example, nil
+}
+
+// DiffExampleRevisions сравнивает версии from и to по полям записи.
+func (s *service) DiffExampleRevisions(ctx context.Context, id, from, to int) (*models.RevisionDiff, error) {
+	fromRevision, err := s.GetExampleRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetExampleRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffExamples(&fromRevision.Example, &toRevision.Example)
+	if err != nil {
+		s.logger.Error("Failed to diff revisions", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetRevisionsFailed
+	}
+
+	return &models.RevisionDiff{
+		ExampleID: id,
+		From:      from,
+		To:        to,
+		Changes:   changes,
+	}, nil
+}
+
+// diffExamples сравнивает JSON-представления записей поле за полем.
+// Отсутствующее поле (например, пустой external_key) считается null.
+func diffExamples(from, to *models.Example) ([]models.FieldChange, error) {
+	fromFields, err := exampleFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := exampleFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := maps.Clone(fromFields)
+	maps.Copy(keys, toFields)
+
+	changes := []models.FieldChange{}
+	for _, field := range slices.Sorted(maps.Keys(keys)) {
+		if slices.Contains(revisionIgnoredFields, field) {
+			continue
+		}
+		before, after := fromFields[field], toFields[field]
+		if string(before) != string(after) {
+			changes = append(changes, models.FieldChange{Field: field, From: before, To: after})
+		}
+	}
+	return changes, nil
+}
+
+func exampleFields(example *models.Example) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode example: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode example: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+func TestDiffExamples(t *testing.T) {
+	from := &models.Example{ID: 1, Name: "old", Value: 1, IsActive: true, UpdatedAt: time.Unix(1, 0)}
+	to := &models.Example{ID: 1, Name: "new", Value: 1, IsActive: true, ExternalKey: "ext-1", UpdatedAt: time.Unix(2, 0)}
+
+	changes, err := diffExamples(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.FieldChange{
+		{Field: "external_key", From: nil, To: []byte(`"ext-1"`)},
+		{Field: "name", From: []byte(`"old"`), To: []byte(`"new"`)},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, change := range changes {
+		if change.Field != want[i].Field || string(change.From) != string(want[i].From) || string(change.To) != string(want[i].To) {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], change)
+		}
+	}
+}
+
+func TestGetExampleAsOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision *models.ExampleRevision
+		err      error
+		want     error
+	}{
+		{"existing", &models.ExampleRevision{Op: RevisionUpdated, Example: models.Example{ID: 1}}, nil, nil},
+		{"deleted by then", &models.ExampleRevision{Op: RevisionDeleted}, nil, ErrExampleNotFound},
+		{"not created yet", nil, storageerrors.ErrNotFound, ErrExampleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &mockStorage{
+				revisionAtFn: func(context.Context, int, time.Time) (*models.ExampleRevision, error) {
+					return tt.revision, tt.err
+				},
+			}
+			svc := NewService(st, testLogger())
+
+			_, err := svc.GetExampleAsOf(context.Background(), 1, time.Now())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRestoreExampleRevision(t *testing.T) {
+	revisions := map[int]*models.ExampleRevision{
+		1: {Revision: 1, Op: RevisionCreated, Example: models.Example{ID: 1, Name: "first", Value: 10, IsActive: true}},
+		3: {Revision: 3, Op: RevisionDeleted, Example: models.Example{ID: 1}},
+	}
+	var updated *models.Example
+	st := &mockStorage{
+		revisionFn: func(_ context.Context, _ int, rev int) (*models.ExampleRevision, error) {
+			if revision, ok := revisions[rev]; ok {
+				return revision, nil
+			}
+			return nil, storageerrors.ErrNotFound
+		},
+		updateFn: func(_ context.Context, example *models.Example) error {
+			updated = example
+			return nil
+		},
+	}
+	svc := NewService(st, testLogger())
+
+	t.Run("restores fields", func(t *testing.T) {
+		if _, err := svc.RestoreExampleRevision(context.Background(), 1, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updated == nil || updated.Name != "first" || updated.Value != 10 || !updated.IsActive {
+			t.Errorf("expected fields of revision 1, got %+v", updated)
+		}
+	})
+
+	t.Run("deleted revision", func(t *testing.T) {
+		if _, err := svc.RestoreExampleRevision(context.Background(), 1, 3); !errors.Is(err, ErrRestoreDeletedRevision) {
+			t.Fatalf("expected ErrRestoreDeletedRevision, got %v", err)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := svc.RestoreExampleRevision(context.Background(), 1, 2); !errors.Is(err, ErrRevisionNotFound) {
+			t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+		}
+	})
+}
+
+func TestGetExampleRevisions_UnknownExample(t *testing.T) {
+	svc := NewService(&mockStorage{}, testLogger())
+
+	if _, err := svc.GetExampleRevisions(context.Background(), 1, 10, 0); !errors.Is(err, ErrExampleNotFound) {
+		t.Fatalf("expected ErrExampleNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"go-service-template/internal/models"
+)
+
+// Режимы поиска.
+const (
+	// SearchModeFullText — полнотекстовый поиск с учётом словоформ.
+	SearchModeFullText = "fulltext"
+	// SearchModeFuzzy — поиск по похожести триграмм, терпимый к опечаткам.
+	SearchModeFuzzy = "fuzzy"
+)
+
+const maxSearchQueryLength = 256
+
+func (s *service) SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if len(query.Query) > maxSearchQueryLength {
+		return nil, ErrSearchQueryTooLong
+	}
+	switch query.Mode {
+	case "":
+		query.Mode = SearchModeFullText
+	case SearchModeFullText, SearchModeFuzzy:
+	default:
+		return nil, ErrInvalidSearchMode
+	}
+	if query.Filter.Limit <= 0 {
+		return nil, ErrLimitMustBePositive
+	}
+	if err := validateExampleFilter(query.Filter); err != nil {
+		return nil, err
+	}
+	if query.Filter.Limit > 100 {
+		query.Filter.Limit = 100
+	}
+
+	results, err := s.storage.SearchExamples(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to search examples", slog.String("error", err.Error()))
+		return nil, ErrSearchExamplesFailed
+	}
+
+	return results, nil
+}
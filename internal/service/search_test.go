@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-service-template/internal/models"
+)
+
+func TestSearchExamples(t *testing.T) {
+	t.Run("invalid query is rejected", func(t *testing.T) {
+		st := &mockStorage{
+			searchFn: func(context.Context, models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+				t.Fatal("storage must not be queried for an invalid query")
+				return nil, nil
+			},
+		}
+		svc := NewService(st, testLogger())
+		page := models.ExampleFilter{Limit: 10}
+
+		tests := []struct {
+			query models.ExampleSearchQuery
+			want  error
+		}{
+			{models.ExampleSearchQuery{Filter: page, Query: "  "}, ErrSearchQueryRequired},
+			{models.ExampleSearchQuery{Filter: page, Query: strings.Repeat("a", 257)}, ErrSearchQueryTooLong},
+			{models.ExampleSearchQuery{Filter: page, Query: "a", Mode: "regex"}, ErrInvalidSearchMode},
+			{models.ExampleSearchQuery{Query: "a"}, ErrLimitMustBePositive},
+			{models.ExampleSearchQuery{Filter: models.ExampleFilter{Limit: 10, Offset: -1}, Query: "a"}, ErrOffsetMustBeNonNeg},
+		}
+		for _, tt := range tests {
+			if _, err := svc.SearchExamples(context.Background(), tt.query); !errors.Is(err, tt.want) {
+				t.Errorf("%q (%s): expected %v, got %v", tt.query.Query, tt.query.Mode, tt.want, err)
+			}
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		var got models.ExampleSearchQuery
+		st := &mockStorage{
+			searchFn: func(_ context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+				got = query
+				return []models.ExampleSearchResult{{Example: models.Example{ID: 1}}}, nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		results, err := svc.SearchExamples(context.Background(), models.ExampleSearchQuery{
+			Filter: models.ExampleFilter{Limit: 500},
+			Query:  "  red apple ",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 {
+			t.Errorf("unexpected results %+v", results)
+		}
+		if got.Query != "red apple" || got.Mode != SearchModeFullText || got.Filter.Limit != 100 {
+			t.Errorf("unexpected storage query %+v", got)
+		}
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		st := &mockStorage{
+			searchFn: func(context.Context, models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		_, err := svc.SearchExamples(context.Background(), models.ExampleSearchQuery{Filter: models.ExampleFilter{Limit: 10}, Query: "a"})
+		if !errors.Is(err, ErrSearchExamplesFailed) {
+			t.Fatalf("expected ErrSearchExamplesFailed, got %v", err)
+		}
+	})
+}
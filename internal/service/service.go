@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"io"
+	"iter"
 	"log/slog"
+	"time"
 
 	"go-service-template/internal/models"
 )
@@ -10,14 +13,37 @@ import (
 type Service interface {
 	CreateExample(ctx context.Context, req *models.ExampleRequest) (*models.Example, error)
 	GetExampleByID(ctx context.Context, id int) (*models.Example, error)
-	GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error)
+	GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error)
+	// GetExampleStats считает агрегаты по value для записей под фильтром.
+	GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error)
+	// SearchExamples ищет записи по name и description, самые релевантные первыми.
+	SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error)
+	ExportExamples(ctx context.Context, filter models.ExampleFilter) (iter.Seq2[*models.Example, error], error)
 	UpdateExample(ctx context.Context, id int, req *models.ExampleRequest) (*models.Example, error)
 	DeleteExample(ctx context.Context, id int) error
+	BatchExamples(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error)
+	ImportExamples(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error)
+	// PurgeExamples удаляет все записи под фильтром и возвращает их число.
+	PurgeExamples(ctx context.Context, filter models.ExampleFilter, progress func(deleted int)) (int, error)
+
+	// GetExampleRevisions возвращает историю версий записи, новые первыми.
+	GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error)
+	GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error)
+	GetExampleAsOf(ctx context.Context, id int, asOf time.Time) (*models.Example, error)
+	RestoreExampleRevision(ctx context.Context, id, rev int) (*models.Example, error)
+	DiffExampleRevisions(ctx context.Context, id, from, to int) (*models.RevisionDiff, error)
 }
 
 type Services struct {
-	Example  Service
-	PingFunc func(ctx context.Context) error
+	Example Service
+	// Webhooks — управление подписками на webhooks; nil отключает API подписок.
+	Webhooks WebhookService
+	// Audit — чтение журнала аудита; nil отключает GET /api/v1/audit.
+	Audit AuditService
+	// Operations — асинхронные операции; nil отключает /api/v1/operations и
+	// асинхронный запуск импорта и очистки.
+	Operations OperationService
+	PingFunc   func(ctx context.Context) error
 }
 
 func NewServices(storage Storage, logger *slog.Logger) *Services {
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// Интервалы группировки статистики по created_at.
+const (
+	StatsBucketHour  = "hour"
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+)
+
+// DefaultStatsTimezone — часовой пояс интервалов, если он не задан.
+const DefaultStatsTimezone = "UTC"
+
+func (s *service) GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+	query.Filter.Limit, query.Filter.Offset = 0, 0
+	if err := validateExampleFilter(query.Filter); err != nil {
+		return nil, err
+	}
+	switch query.Bucket {
+	case "", StatsBucketHour, StatsBucketDay, StatsBucketWeek, StatsBucketMonth:
+	default:
+		return nil, ErrInvalidStatsBucket
+	}
+	if query.Timezone == "" {
+		query.Timezone = DefaultStatsTimezone
+	}
+	// "Local" — часовой пояс процесса, в БД его нет.
+	loc, err := time.LoadLocation(query.Timezone)
+	if err != nil || query.Timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+
+	stats, err := s.storage.GetExampleStats(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to get example stats", slog.String("error", err.Error()))
+		return nil, ErrGetExampleStatsFailed
+	}
+
+	if query.Bucket != "" {
+		stats.Bucket, stats.Timezone = query.Bucket, query.Timezone
+		for i := range stats.Buckets {
+			stats.Buckets[i].Start = stats.Buckets[i].Start.In(loc)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+func TestGetExampleStats(t *testing.T) {
+	t.Run("invalid query is rejected", func(t *testing.T) {
+		st := &mockStorage{
+			statsFn: func(context.Context, models.ExampleStatsQuery) (*models.ExampleStats, error) {
+				t.Fatal("storage must not be queried for an invalid query")
+				return nil, nil
+			},
+		}
+		svc := NewService(st, testLogger())
+		after := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+		tests := []struct {
+			query models.ExampleStatsQuery
+			want  error
+		}{
+			{models.ExampleStatsQuery{Bucket: "year"}, ErrInvalidStatsBucket},
+			{models.ExampleStatsQuery{Bucket: StatsBucketDay, Timezone: "Mars/Olympus"}, ErrInvalidTimezone},
+			{models.ExampleStatsQuery{Bucket: StatsBucketDay, Timezone: "Local"}, ErrInvalidTimezone},
+			{models.ExampleStatsQuery{Filter: models.ExampleFilter{CreatedAfter: &after, CreatedBefore: &after}}, ErrInvalidTimeRange},
+		}
+		for _, tt := range tests {
+			if _, err := svc.GetExampleStats(context.Background(), tt.query); !errors.Is(err, tt.want) {
+				t.Errorf("%+v: expected %v, got %v", tt.query, tt.want, err)
+			}
+		}
+	})
+
+	t.Run("buckets are returned in the requested timezone", func(t *testing.T) {
+		var got models.ExampleStatsQuery
+		start := time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)
+		st := &mockStorage{
+			statsFn: func(_ context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+				got = query
+				return &models.ExampleStats{Buckets: []models.BucketStats{{Start: start}}}, nil
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		stats, err := svc.GetExampleStats(context.Background(), models.ExampleStatsQuery{
+			Filter:   models.ExampleFilter{Limit: 10, Offset: 5},
+			Bucket:   StatsBucketDay,
+			Timezone: "Europe/Moscow",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Filter.Limit != 0 || got.Filter.Offset != 0 || got.Timezone != "Europe/Moscow" {
+			t.Errorf("unexpected storage query %+v", got)
+		}
+		if stats.Bucket != StatsBucketDay || stats.Timezone != "Europe/Moscow" {
+			t.Errorf("unexpected bucket %q / timezone %q", stats.Bucket, stats.Timezone)
+		}
+		if s := stats.Buckets[0].Start.Format(time.RFC3339); s != "2026-03-02T00:00:00+03:00" {
+			t.Errorf("expected local midnight, got %s", s)
+		}
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		st := &mockStorage{
+			statsFn: func(context.Context, models.ExampleStatsQuery) (*models.ExampleStats, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+		svc := NewService(st, testLogger())
+
+		if _, err := svc.GetExampleStats(context.Background(), models.ExampleStatsQuery{}); !errors.Is(err, ErrGetExampleStatsFailed) {
+			t.Fatalf("expected ErrGetExampleStatsFailed, got %v", err)
+		}
+	})
+}
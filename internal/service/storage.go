@@ -2,17 +2,76 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"go-service-template/internal/models"
 )
 
-type Storage interface {
-	Ping(ctx context.Context) error
-	Close() error
-
+// TxStorage — операции с данными, доступные как вне транзакции, так и внутри
+// Storage.WithinTx.
+type TxStorage interface {
 	CreateExample(ctx context.Context, example *models.Example) error
 	GetExampleByID(ctx context.Context, id int) (*models.Example, error)
-	GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error)
+	// GetExampleForUpdate читает запись и блокирует её от конкурентных
+	// изменений до конца транзакции. Вне WithinTx равносилен GetExampleByID.
+	GetExampleForUpdate(ctx context.Context, id int) (*models.Example, error)
+	GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error)
+	// GetExampleStats считает агрегаты по value для записей под query.Filter:
+	// итог, разбивку по is_active и, если задан Bucket, по интервалам
+	// created_at в часовом поясе Timezone. Bucket и Timezone уже проверены.
+	GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error)
+	// SearchExamples возвращает страницу записей под query.Filter, подходящих
+	// под query.Query, по убыванию релевантности. Query и Mode уже проверены.
+	SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error)
+	// StreamExamples вызывает fn для каждой записи, подходящей под фильтр, в
+	// порядке ID, не загружая выборку в память целиком. Ошибка fn прерывает
+	// чтение и возвращается как есть.
+	StreamExamples(ctx context.Context, filter models.ExampleFilter, fn func(*models.Example) error) error
+	// UpdateExample обновляет запись и заполняет example итоговой строкой.
 	UpdateExample(ctx context.Context, example *models.Example) error
 	DeleteExample(ctx context.Context, id int) error
+
+	// CreateExamples вставляет записи одной пачкой и заполняет их ID.
+	CreateExamples(ctx context.Context, examples []*models.Example) error
+	// UpdateExamples обновляет записи пачкой и заполняет их итоговыми строками.
+	// Возвращает ошибку по каждой записи в том же порядке (ErrNotFound для
+	// отсутствующих) и общую ошибку, если пачку не удалось выполнить.
+	UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error)
+	// DeleteExamples удаляет записи пачкой; ошибки по записям — как в UpdateExamples.
+	DeleteExamples(ctx context.Context, ids []int) ([]error, error)
+	// UpsertExamples создаёт или обновляет записи по ExternalKey (ключи в пачке
+	// уникальны) и заполняет их ID и created_at. Возвращает по каждой записи
+	// её состояние до обновления (для аудита); nil — запись была создана.
+	// Внутри WithinTx обновляемые строки блокируются до конца транзакции.
+	UpsertExamples(ctx context.Context, examples []*models.Example) (previous []*models.Example, err error)
+
+	// GetExampleRevisions возвращает версии записи, новые первыми; пустой
+	// список, если у записи нет истории.
+	GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error)
+	// GetExampleRevision возвращает версию rev записи или ErrNotFound.
+	GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error)
+	// GetExampleRevisionAt возвращает последнюю версию записи, созданную не
+	// позже asOf, или ErrNotFound, если её тогда ещё не было.
+	GetExampleRevisionAt(ctx context.Context, id int, asOf time.Time) (*models.ExampleRevision, error)
+
+	// AppendOutbox записывает сообщение в outbox и заполняет его ID. Чтобы
+	// сообщение было опубликовано тогда и только тогда, когда изменение
+	// данных зафиксировано, вызывайте его на tx внутри WithinTx.
+	AppendOutbox(ctx context.Context, msg *models.OutboxMessage) error
+	// AppendAudit добавляет запись в журнал аудита и заполняет её ID и
+	// created_at. Как и AppendOutbox, вызывайте на tx внутри WithinTx.
+	AppendAudit(ctx context.Context, entry *models.AuditEntry) error
+}
+
+type Storage interface {
+	Ping(ctx context.Context) error
+	Close() error
+
+	TxStorage
+
+	// WithinTx выполняет fn в одной транзакции (unit of work): commit, если fn
+	// вернула nil, иначе rollback. При конфликте сериализации реализация может
+	// повторить fn целиком, поэтому fn не должна иметь внешних побочных эффектов.
+	// Вложенный вызов на tx выполняется как savepoint.
+	WithinTx(ctx context.Context, fn func(tx TxStorage) error) error
 }
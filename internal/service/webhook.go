@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+	"go-service-template/internal/webhook"
+)
+
+const (
+	// WebhookAllEvents в event_types подписывает на все типы событий.
+	WebhookAllEvents = "*"
+
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+	maxWebhookURLLength    = 2048
+)
+
+// webhookEventTypes — типы событий outbox, на которые можно подписаться.
+var webhookEventTypes = []string{
+	WebhookAllEvents,
+	OutboxExampleCreated,
+	OutboxExampleUpdated,
+	OutboxExampleDeleted,
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	GetAllWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, id int64, req *models.WebhookRequest) (*models.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	// GetWebhookAttempts возвращает до limit последних попыток доставки
+	// подписки, новые первыми.
+	GetWebhookAttempts(ctx context.Context, id int64, limit int) ([]models.WebhookAttempt, error)
+}
+
+// WebhookStorage хранит подписки и журнал попыток доставки.
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error
+	GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	GetAllWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	// UpdateWebhook меняет URL и типы событий и заполняет sub итоговой
+	// строкой. Пустой sub.Secret оставляет прежний секрет; active == nil не
+	// меняет состояние, true включает подписку и сбрасывает счётчик ошибок.
+	UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription, active *bool) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhookAttempts(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookAttempt, error)
+}
+
+type webhookService struct {
+	storage WebhookStorage
+	// allowPrivateNetworks разрешает адреса во внутренних сетях
+	// (WEBHOOK_ALLOW_PRIVATE_NETWORKS).
+	allowPrivateNetworks bool
+	logger               *slog.Logger
+}
+
+func NewWebhookService(storage WebhookStorage, allowPrivateNetworks bool, logger *slog.Logger) WebhookService {
+	return &webhookService{
+		storage:              storage,
+		allowPrivateNetworks: allowPrivateNetworks,
+		logger:               logger,
+	}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.WebhookSubscription, error) {
+	if err := validateWebhookRequest(req, true, s.allowPrivateNetworks); err != nil {
+		return nil, err
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:        strings.TrimSpace(req.URL),
+		Secret:     req.Secret,
+		EventTypes: normalizeEventTypes(req.EventTypes),
+		Active:     req.Active == nil || *req.Active,
+	}
+	if err := s.storage.CreateWebhook(ctx, sub); err != nil {
+		s.logger.Error("Failed to create webhook", slog.String("error", err.Error()))
+		return nil, ErrCreateWebhookFailed
+	}
+
+	s.logger.Info("Webhook created successfully", slog.Int64("id", sub.ID))
+	return sub, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, ErrInvalidWebhookID
+	}
+
+	sub, err := s.storage.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		s.logger.Error("Failed to get webhook", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetWebhookFailed
+	}
+
+	return sub, nil
+}
+
+func (s *webhookService) GetAllWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs, err := s.storage.GetAllWebhooks(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get webhooks", slog.String("error", err.Error()))
+		return nil, ErrGetWebhooksFailed
+	}
+
+	return subs, nil
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, id int64, req *models.WebhookRequest) (*models.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, ErrInvalidWebhookID
+	}
+	if err := validateWebhookRequest(req, false, s.allowPrivateNetworks); err != nil {
+		return nil, err
+	}
+
+	sub := &models.WebhookSubscription{
+		ID:         id,
+		URL:        strings.TrimSpace(req.URL),
+		Secret:     req.Secret,
+		EventTypes: normalizeEventTypes(req.EventTypes),
+	}
+	if err := s.storage.UpdateWebhook(ctx, sub, req.Active); err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		s.logger.Error("Failed to update webhook", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrUpdateWebhookFailed
+	}
+
+	s.logger.Info("Webhook updated successfully", slog.Int64("id", id))
+	return sub, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidWebhookID
+	}
+
+	if err := s.storage.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, storageerrors.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		s.logger.Error("Failed to delete webhook", slog.Int64("id", id), slog.String("error", err.Error()))
+		return ErrDeleteWebhookFailed
+	}
+
+	s.logger.Info("Webhook deleted successfully", slog.Int64("id", id))
+	return nil
+}
+
+func (s *webhookService) GetWebhookAttempts(ctx context.Context, id int64, limit int) ([]models.WebhookAttempt, error) {
+	if limit <= 0 {
+		return nil, ErrLimitMustBePositive
+	}
+	limit = min(limit, 100)
+
+	// Пустой журнал и несуществующая подписка должны различаться.
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.storage.GetWebhookAttempts(ctx, id, limit)
+	if err != nil {
+		s.logger.Error("Failed to get webhook attempts", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, ErrGetWebhookAttemptsFailed
+	}
+
+	return attempts, nil
+}
+
+// validateWebhookRequest проверяет запрос; secretRequired — при создании
+// секрет обязателен, при изменении пустой секрет оставляет прежний. Без
+// allowPrivateNetworks отклоняются URL с непубличным IP и localhost; имена,
+// которые разрешаются во внутреннюю сеть, отсекает уже Worker при
+// подключении.
+func validateWebhookRequest(req *models.WebhookRequest, secretRequired, allowPrivateNetworks bool) error {
+	if req == nil {
+		return ErrRequestCannotBeNil
+	}
+
+	raw := strings.TrimSpace(req.URL)
+	u, err := url.Parse(raw)
+	if err != nil || len(raw) > maxWebhookURLLength || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookURLInvalid
+	}
+	if !allowPrivateNetworks && privateWebhookHost(u.Hostname()) {
+		return ErrWebhookURLForbidden
+	}
+
+	if req.Secret != "" || secretRequired {
+		if len(req.Secret) < minWebhookSecretLength {
+			return ErrWebhookSecretTooShort
+		}
+		if len(req.Secret) > maxWebhookSecretLength {
+			return ErrWebhookSecretTooLong
+		}
+	}
+
+	if len(req.EventTypes) == 0 {
+		return ErrWebhookEventTypesRequired
+	}
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			return ErrWebhookUnknownEventType
+		}
+	}
+
+	return nil
+}
+
+// privateWebhookHost сообщает, что host — localhost или непубличный IP.
+func privateWebhookHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && webhook.ForbiddenAddress(ip)
+}
+
+// normalizeEventTypes сортирует типы и убирает повторы.
+func normalizeEventTypes(eventTypes []string) []string {
+	normalized := slices.Clone(eventTypes)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go-service-template/internal/models"
+	storageerrors "go-service-template/internal/storage"
+)
+
+type mockWebhookStorage struct {
+	createFn   func(ctx context.Context, sub *models.WebhookSubscription) error
+	getFn      func(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	updateFn   func(ctx context.Context, sub *models.WebhookSubscription, active *bool) error
+	attemptsFn func(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookAttempt, error)
+}
+
+func (m *mockWebhookStorage) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, sub)
+	}
+	return nil
+}
+
+func (m *mockWebhookStorage) GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, id)
+	}
+	return &models.WebhookSubscription{ID: id}, nil
+}
+
+func (m *mockWebhookStorage) GetAllWebhooks(context.Context) ([]models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookStorage) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription, active *bool) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, sub, active)
+	}
+	return nil
+}
+
+func (m *mockWebhookStorage) DeleteWebhook(context.Context, int64) error {
+	return nil
+}
+
+func (m *mockWebhookStorage) GetWebhookAttempts(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookAttempt, error) {
+	if m.attemptsFn != nil {
+		return m.attemptsFn(ctx, subscriptionID, limit)
+	}
+	return nil, nil
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	valid := func() *models.WebhookRequest {
+		return &models.WebhookRequest{
+			URL:        "https://partner.example.com/hooks",
+			Secret:     "0123456789abcdef",
+			EventTypes: []string{OutboxExampleCreated},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *models.WebhookRequest)
+		want   error
+	}{
+		{"relative url", func(r *models.WebhookRequest) { r.URL = "/hooks" }, ErrWebhookURLInvalid},
+		{"unsupported scheme", func(r *models.WebhookRequest) { r.URL = "ftp://partner.example.com" }, ErrWebhookURLInvalid},
+		{"loopback address", func(r *models.WebhookRequest) { r.URL = "http://127.0.0.1:8080/hooks" }, ErrWebhookURLForbidden},
+		{"localhost", func(r *models.WebhookRequest) { r.URL = "http://localhost/hooks" }, ErrWebhookURLForbidden},
+		{"cloud metadata", func(r *models.WebhookRequest) { r.URL = "http://169.254.169.254/latest" }, ErrWebhookURLForbidden},
+		{"private IPv6", func(r *models.WebhookRequest) { r.URL = "http://[fd00::1]/hooks" }, ErrWebhookURLForbidden},
+		{"missing secret", func(r *models.WebhookRequest) { r.Secret = "" }, ErrWebhookSecretTooShort},
+		{"short secret", func(r *models.WebhookRequest) { r.Secret = "short" }, ErrWebhookSecretTooShort},
+		{"no event types", func(r *models.WebhookRequest) { r.EventTypes = nil }, ErrWebhookEventTypesRequired},
+		{"unknown event type", func(r *models.WebhookRequest) { r.EventTypes = []string{"example.archived"} }, ErrWebhookUnknownEventType},
+	}
+
+	svc := NewWebhookService(&mockWebhookStorage{}, false, testLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(req)
+			if _, err := svc.CreateWebhook(context.Background(), req); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("normalizes event types", func(t *testing.T) {
+		req := valid()
+		req.EventTypes = []string{OutboxExampleUpdated, OutboxExampleCreated, OutboxExampleUpdated}
+		sub, err := svc.CreateWebhook(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{OutboxExampleCreated, OutboxExampleUpdated}
+		if !slices.Equal(sub.EventTypes, want) || !sub.Active {
+			t.Errorf("expected active subscription with %v, got %+v", want, sub)
+		}
+	})
+}
+
+func TestUpdateWebhook_KeepsSecret(t *testing.T) {
+	var stored *models.WebhookSubscription
+	st := &mockWebhookStorage{
+		updateFn: func(_ context.Context, sub *models.WebhookSubscription, _ *bool) error {
+			stored = sub
+			return nil
+		},
+	}
+	svc := NewWebhookService(st, false, testLogger())
+
+	_, err := svc.UpdateWebhook(context.Background(), 1, &models.WebhookRequest{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{WebhookAllEvents},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.Secret != "" {
+		t.Errorf("expected empty secret to be passed through, got %+v", stored)
+	}
+}
+
+func TestGetWebhookAttempts(t *testing.T) {
+	t.Run("unknown subscription", func(t *testing.T) {
+		st := &mockWebhookStorage{
+			getFn: func(context.Context, int64) (*models.WebhookSubscription, error) {
+				return nil, storageerrors.ErrNotFound
+			},
+		}
+		svc := NewWebhookService(st, false, testLogger())
+
+		if _, err := svc.GetWebhookAttempts(context.Background(), 1, 10); !errors.Is(err, ErrWebhookNotFound) {
+			t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+		}
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		var got int
+		st := &mockWebhookStorage{
+			attemptsFn: func(_ context.Context, _ int64, limit int) ([]models.WebhookAttempt, error) {
+				got = limit
+				return nil, nil
+			},
+		}
+		svc := NewWebhookService(st, false, testLogger())
+
+		if _, err := svc.GetWebhookAttempts(context.Background(), 1, 1000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != 100 {
+			t.Errorf("expected limit 100, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+// Package cache — read-through кеш записей перед любой реализацией
+// service.Storage. Чтение по ID сначала идёт в Cache, промахи по одному ID
+// схлопываются в один запрос к хранилищу, записи инвалидируют кеш.
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loadTimeout ограничивает чтение из хранилища при промахе. Загрузка не
+// привязана к контексту запроса: её результат ждут все схлопнутые вызовы.
+const loadTimeout = 5 * time.Second
+
+// Cache — хранилище закешированных записей. Встроенная реализация — LRU в
+// памяти процесса; внешний кеш (Redis, memcached) подключается реализацией
+// этого интерфейса. Ошибка кеша не ломает запрос: Storage логирует её и
+// идёт в хранилище.
+type Cache interface {
+	Get(ctx context.Context, id int) (*models.Example, bool, error)
+	// Version возвращает версию кеша; Delete и Purge её увеличивают.
+	Version(ctx context.Context) (uint64, error)
+	// Set сохраняет запись, только если версия кеша всё ещё равна version.
+	// Сравнение и запись атомарны: инвалидация между чтением из хранилища и
+	// Set не даст положить в кеш прочитанную до неё, устаревшую версию.
+	Set(ctx context.Context, example *models.Example, version uint64) error
+	Delete(ctx context.Context, ids ...int) error
+	// Purge удаляет все записи.
+	Purge(ctx context.Context) error
+}
+
+// Storage — декоратор service.Storage с read-through кешем для
+// GetExampleByID. Остальные чтения (списки, выгрузка) идут мимо кеша.
+type Storage struct {
+	service.Storage
+
+	cache  Cache
+	logger *slog.Logger
+	group  singleflight.Group
+}
+
+var _ service.Storage = (*Storage)(nil)
+
+func New(next service.Storage, cache Cache, logger *slog.Logger) *Storage {
+	return &Storage{
+		Storage: next,
+		cache:   cache,
+		logger:  logger,
+	}
+}
+
+func (s *Storage) GetExampleByID(ctx context.Context, id int) (*models.Example, error) {
+	example, ok, err := s.cache.Get(ctx, id)
+	if err != nil {
+		s.logger.Warn("Cache get failed", slog.Int("id", id), slog.String("error", err.Error()))
+	}
+	if ok {
+		metrics.CacheHits.Add(1)
+		return example, nil
+	}
+	metrics.CacheMisses.Add(1)
+
+	ch := s.group.DoChan(strconv.Itoa(id), func() (any, error) {
+		return s.load(context.WithoutCancel(ctx), id)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Результат общий для всех ожидающих — каждому своя копия.
+		example := *res.Val.(*models.Example)
+		return &example, nil
+	}
+}
+
+func (s *Storage) load(ctx context.Context, id int) (*models.Example, error) {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	// Версия берётся до чтения: если запись изменят между SELECT и Set,
+	// инвалидация увеличит версию, и Set ничего не запишет.
+	version, versionErr := s.cache.Version(ctx)
+	example, err := s.Storage.GetExampleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if versionErr != nil {
+		s.logger.Warn("Cache version failed", slog.Int("id", id), slog.String("error", versionErr.Error()))
+		return example, nil
+	}
+	if err := s.cache.Set(ctx, example, version); err != nil {
+		s.logger.Warn("Cache set failed", slog.Int("id", id), slog.String("error", err.Error()))
+	}
+	return example, nil
+}
+
+// Invalidate удаляет записи из кеша. Вызывается после записи в хранилище и
+// при внешних уведомлениях об изменениях.
+func (s *Storage) Invalidate(ctx context.Context, ids ...int) {
+	if len(ids) == 0 {
+		return
+	}
+	// Загрузка, начатая до изменения, могла прочитать старую версию: новые
+	// промахи не должны присоединяться к ней, а начинают своё чтение.
+	for _, id := range ids {
+		s.group.Forget(strconv.Itoa(id))
+	}
+	if err := s.cache.Delete(context.WithoutCancel(ctx), ids...); err != nil {
+		s.logger.Error("Cache invalidation failed", slog.Any("ids", ids), slog.String("error", err.Error()))
+	}
+}
+
+// Purge очищает кеш целиком.
+func (s *Storage) Purge(ctx context.Context) {
+	if err := s.cache.Purge(context.WithoutCancel(ctx)); err != nil {
+		s.logger.Error("Cache purge failed", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Storage) UpdateExample(ctx context.Context, example *models.Example) error {
+	err := s.Storage.UpdateExample(ctx, example)
+	s.Invalidate(ctx, example.ID)
+	return err
+}
+
+func (s *Storage) DeleteExample(ctx context.Context, id int) error {
+	err := s.Storage.DeleteExample(ctx, id)
+	s.Invalidate(ctx, id)
+	return err
+}
+
+func (s *Storage) UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error) {
+	errs, err := s.Storage.UpdateExamples(ctx, examples)
+	s.Invalidate(ctx, exampleIDs(examples)...)
+	return errs, err
+}
+
+func (s *Storage) DeleteExamples(ctx context.Context, ids []int) ([]error, error) {
+	errs, err := s.Storage.DeleteExamples(ctx, ids)
+	s.Invalidate(ctx, ids...)
+	return errs, err
+}
+
+func (s *Storage) UpsertExamples(ctx context.Context, examples []*models.Example) ([]*models.Example, error) {
+	previous, err := s.Storage.UpsertExamples(ctx, examples)
+	s.Invalidate(ctx, exampleIDs(examples)...)
+	return previous, err
+}
+
+// WithinTx запоминает ID, затронутые внутри транзакции, и инвалидирует их
+// после её завершения. Чтения внутри транзакции идут мимо кеша: она видит
+// собственные незакоммиченные изменения.
+func (s *Storage) WithinTx(ctx context.Context, fn func(tx service.TxStorage) error) error {
+	var touched []int
+	err := s.Storage.WithinTx(ctx, func(tx service.TxStorage) error {
+		return fn(&txStorage{TxStorage: tx, touched: &touched})
+	})
+	s.Invalidate(ctx, touched...)
+	return err
+}
+
+// txStorage — обёртка транзакции, собирающая ID изменённых записей.
+type txStorage struct {
+	service.TxStorage
+
+	touched *[]int
+}
+
+func (t *txStorage) UpdateExample(ctx context.Context, example *models.Example) error {
+	err := t.TxStorage.UpdateExample(ctx, example)
+	*t.touched = append(*t.touched, example.ID)
+	return err
+}
+
+func (t *txStorage) DeleteExample(ctx context.Context, id int) error {
+	err := t.TxStorage.DeleteExample(ctx, id)
+	*t.touched = append(*t.touched, id)
+	return err
+}
+
+func (t *txStorage) UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error) {
+	errs, err := t.TxStorage.UpdateExamples(ctx, examples)
+	*t.touched = append(*t.touched, exampleIDs(examples)...)
+	return errs, err
+}
+
+func (t *txStorage) DeleteExamples(ctx context.Context, ids []int) ([]error, error) {
+	errs, err := t.TxStorage.DeleteExamples(ctx, ids)
+	*t.touched = append(*t.touched, ids...)
+	return errs, err
+}
+
+func (t *txStorage) UpsertExamples(ctx context.Context, examples []*models.Example) ([]*models.Example, error) {
+	previous, err := t.TxStorage.UpsertExamples(ctx, examples)
+	*t.touched = append(*t.touched, exampleIDs(examples)...)
+	return previous, err
+}
+
+// WithinTx сохраняет вложенные транзакции (savepoint), если их поддерживает
+// нижележащая реализация.
+func (t *txStorage) WithinTx(ctx context.Context, fn func(tx service.TxStorage) error) error {
+	nested, ok := t.TxStorage.(interface {
+		WithinTx(ctx context.Context, fn func(tx service.TxStorage) error) error
+	})
+	if !ok {
+		return fn(t)
+	}
+	return nested.WithinTx(ctx, func(tx service.TxStorage) error {
+		return fn(&txStorage{TxStorage: tx, touched: t.touched})
+	})
+}
+
+func exampleIDs(examples []*models.Example) []int {
+	ids := make([]int, 0, len(examples))
+	for _, example := range examples {
+		if example.ID != 0 {
+			ids = append(ids, example.ID)
+		}
+	}
+	return ids
+}
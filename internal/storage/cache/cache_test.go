@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+	storageerrors "go-service-template/internal/storage"
+	"go-service-template/internal/storage/memory"
+)
+
+// countingStorage считает чтения по ID и может задерживать их.
+type countingStorage struct {
+	*memory.Storage
+
+	gets    atomic.Int32
+	release chan struct{}
+}
+
+func (s *countingStorage) GetExampleByID(ctx context.Context, id int) (*models.Example, error) {
+	s.gets.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	return s.Storage.GetExampleByID(ctx, id)
+}
+
+func newTestStorage(t *testing.T) (*Storage, *countingStorage) {
+	t.Helper()
+	next := &countingStorage{Storage: memory.NewStorage()}
+	if err := next.CreateExample(context.Background(), &models.Example{Name: "a"}); err != nil {
+		t.Fatalf("CreateExample() error = %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(next, NewLRU(10, time.Minute), logger), next
+}
+
+func TestStorage_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	s, next := newTestStorage(t)
+
+	for range 3 {
+		example, err := s.GetExampleByID(ctx, 1)
+		if err != nil || example.Name != "a" {
+			t.Fatalf("GetExampleByID() = %+v, %v", example, err)
+		}
+		example.Name = "mutated by caller"
+	}
+	if got := next.gets.Load(); got != 1 {
+		t.Fatalf("expected 1 storage read, got %d", got)
+	}
+
+	if _, err := s.GetExampleByID(ctx, 42); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStorage_InvalidatesOnWrite(t *testing.T) {
+	ctx := context.Background()
+	s, next := newTestStorage(t)
+
+	if _, err := s.GetExampleByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateExample(ctx, &models.Example{ID: 1, Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if example, _ := s.GetExampleByID(ctx, 1); example.Name != "b" {
+		t.Fatalf("expected updated name after UpdateExample, got %q", example.Name)
+	}
+
+	err := s.WithinTx(ctx, func(tx service.TxStorage) error {
+		return tx.UpdateExample(ctx, &models.Example{ID: 1, Name: "c"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if example, _ := s.GetExampleByID(ctx, 1); example.Name != "c" {
+		t.Fatalf("expected updated name after WithinTx, got %q", example.Name)
+	}
+
+	if err := s.DeleteExample(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetExampleByID(ctx, 1); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if got := next.gets.Load(); got != 4 {
+		t.Fatalf("expected 4 storage reads, got %d", got)
+	}
+}
+
+func TestStorage_CoalescesMisses(t *testing.T) {
+	s, next := newTestStorage(t)
+	next.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			if _, err := s.GetExampleByID(context.Background(), 1); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if got := next.gets.Load(); got != 1 {
+		t.Fatalf("expected concurrent misses to share 1 storage read, got %d", got)
+	}
+}
+
+func TestStorage_SkipsStaleFill(t *testing.T) {
+	ctx := context.Background()
+	s, next := newTestStorage(t)
+	next.release = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.GetExampleByID(ctx, 1)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	// Инвалидация во время загрузки: прочитанная версия могла устареть.
+	s.Invalidate(ctx, 1)
+	close(next.release)
+	<-done
+
+	if _, ok, _ := s.cache.Get(ctx, 1); ok {
+		t.Fatal("expected load racing with invalidation not to populate the cache")
+	}
+}
+
+func TestStorage_InvalidateDetachesInflightLoad(t *testing.T) {
+	ctx := context.Background()
+	s, next := newTestStorage(t)
+	next.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Go(func() { _, _ = s.GetExampleByID(ctx, 1) })
+	time.Sleep(20 * time.Millisecond)
+	s.Invalidate(ctx, 1)
+	// Промах после инвалидации не ждёт загрузку, начатую до неё.
+	wg.Go(func() { _, _ = s.GetExampleByID(ctx, 1) })
+	time.Sleep(20 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if got := next.gets.Load(); got != 2 {
+		t.Fatalf("expected a fresh storage read after invalidation, got %d reads", got)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	for id := 1; id <= 2; id++ {
+		_ = c.Set(ctx, &models.Example{ID: id}, 0)
+	}
+	_, _, _ = c.Get(ctx, 1) // 2 становится самой давней
+	_ = c.Set(ctx, &models.Example{ID: 3}, 0)
+
+	if _, ok, _ := c.Get(ctx, 2); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok, _ := c.Get(ctx, 1); !ok {
+		t.Error("expected recently read entry to stay")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, 3); ok {
+		t.Error("expected expired entry to be a miss")
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected expired entry to be removed, len = %d", c.Len())
+	}
+
+	_ = c.Purge(ctx)
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after Purge, len = %d", c.Len())
+	}
+}
+
+func TestLRU_VersionedSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10, time.Minute)
+
+	version, _ := c.Version(ctx)
+	_ = c.Delete(ctx, 1)
+	_ = c.Set(ctx, &models.Example{ID: 1}, version)
+	if _, ok, _ := c.Get(ctx, 1); ok {
+		t.Fatal("expected Set with a version older than Delete to be ignored")
+	}
+
+	version, _ = c.Version(ctx)
+	_ = c.Set(ctx, &models.Example{ID: 1}, version)
+	if _, ok, _ := c.Get(ctx, 1); !ok {
+		t.Fatal("expected Set with the current version to store the entry")
+	}
+}
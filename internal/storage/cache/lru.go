@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+)
+
+// LRU — кеш в памяти процесса с ограничением числа записей и временем
+// жизни записи. При переполнении вытесняется давно не читанная запись.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List // от недавно прочитанных к давно прочитанным
+	items      map[int]*list.Element
+	// version растёт при каждом Delete и Purge.
+	version uint64
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	example   models.Example
+	expiresAt time.Time
+}
+
+var _ Cache = (*LRU)(nil)
+
+func NewLRU(maxEntries int, ttl time.Duration) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		items:      make(map[int]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (c *LRU) Get(_ context.Context, id int) (*models.Example, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[id]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+
+	example := entry.example
+	return &example, true, nil
+}
+
+func (c *LRU) Version(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version, nil
+}
+
+func (c *LRU) Set(_ context.Context, example *models.Example, version uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return nil
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.items[example.ID]; ok {
+		elem.Value = &lruEntry{example: *example, expiresAt: expiresAt}
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[example.ID] = c.order.PushFront(&lruEntry{example: *example, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		metrics.CacheEvictions.Add(1)
+	}
+	return nil
+}
+
+func (c *LRU) Delete(_ context.Context, ids ...int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for _, id := range ids {
+		if elem, ok := c.items[id]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *LRU) Purge(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.order.Init()
+	clear(c.items)
+	return nil
+}
+
+// Len возвращает число записей, включая ещё не удалённые просроченные.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry).example.ID)
+}
@@ -0,0 +1,580 @@
+// Package memory — in-memory реализация service.Storage для тестов и локальных
+// экспериментов без PostgreSQL. Данные живут только в процессе.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+	storageerrors "go-service-template/internal/storage"
+)
+
+type Storage struct {
+	mu       sync.Mutex
+	examples map[int]models.Example
+	nextID   int
+	outbox   []models.OutboxMessage
+	audit    []models.AuditEntry
+	// revisions — история версий по ID записи, как в example_revisions.
+	revisions map[int][]models.ExampleRevision
+}
+
+var _ service.Storage = (*Storage)(nil)
+
+func NewStorage() *Storage {
+	return &Storage{
+		examples:  make(map[int]models.Example),
+		nextID:    1,
+		revisions: make(map[int][]models.ExampleRevision),
+	}
+}
+
+func (s *Storage) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *Storage) Close() error {
+	return nil
+}
+
+func (s *Storage) CreateExample(_ context.Context, example *models.Example) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	example.ID = s.nextID
+	s.nextID++
+	s.examples[example.ID] = *example
+	s.addRevision(service.RevisionCreated, *example)
+
+	return nil
+}
+
+func (s *Storage) GetExampleByID(_ context.Context, id int) (*models.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	example, ok := s.examples[id]
+	if !ok {
+		return nil, storageerrors.ErrNotFound
+	}
+
+	return &example, nil
+}
+
+// GetExampleForUpdate равносилен GetExampleByID: транзакции и так
+// выполняются по одной.
+func (s *Storage) GetExampleForUpdate(ctx context.Context, id int) (*models.Example, error) {
+	return s.GetExampleByID(ctx, id)
+}
+
+func (s *Storage) GetAllExamples(_ context.Context, filter models.ExampleFilter) ([]models.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filter(filter), nil
+}
+
+// StreamExamples в памяти отдаёт снимок выборки: fn вызывается без
+// удержания блокировки.
+func (s *Storage) StreamExamples(_ context.Context, filter models.ExampleFilter, fn func(*models.Example) error) error {
+	s.mu.Lock()
+	examples := s.filter(filter)
+	s.mu.Unlock()
+
+	for i := range examples {
+		if err := fn(&examples[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetExampleStats считает агрегаты так же, как GROUPING SETS в PostgreSQL.
+func (s *Storage) GetExampleStats(_ context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+	loc, err := time.LoadLocation(query.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	query.Filter.Limit, query.Filter.Offset = 0, 0
+	examples := s.filter(query.Filter)
+	s.mu.Unlock()
+
+	var (
+		total    valueStats
+		byActive = make(map[bool]*valueStats)
+		buckets  = make(map[time.Time]*valueStats)
+	)
+	for _, example := range examples {
+		total.add(example.Value)
+		if byActive[example.IsActive] == nil {
+			byActive[example.IsActive] = &valueStats{}
+		}
+		byActive[example.IsActive].add(example.Value)
+		if query.Bucket != "" {
+			start := truncateTime(example.CreatedAt.In(loc), query.Bucket)
+			if buckets[start] == nil {
+				buckets[start] = &valueStats{}
+			}
+			buckets[start].add(example.Value)
+		}
+	}
+
+	stats := &models.ExampleStats{Total: total.result(), ByActive: []models.ActiveStats{}}
+	for _, isActive := range []bool{true, false} {
+		if v, ok := byActive[isActive]; ok {
+			stats.ByActive = append(stats.ByActive, models.ActiveStats{IsActive: isActive, ValueStats: v.result()})
+		}
+	}
+	for _, start := range slices.SortedFunc(maps.Keys(buckets), time.Time.Compare) {
+		stats.Buckets = append(stats.Buckets, models.BucketStats{Start: start, ValueStats: buckets[start].result()})
+	}
+	return stats, nil
+}
+
+// fuzzySearchThreshold — порог похожести, как pg_trgm.word_similarity_threshold.
+const fuzzySearchThreshold = 0.6
+
+// SearchExamples приближает поиск PostgreSQL: в режиме fulltext все слова q,
+// кроме исключённых через «-», должны встретиться в name или description
+// (без учёта регистра, но и без словоформ); в режиме fuzzy похожесть
+// триграмм q и текста — не ниже fuzzySearchThreshold.
+func (s *Storage) SearchExamples(_ context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+	s.mu.Lock()
+	page := query.Filter
+	query.Filter.Limit, query.Filter.Offset = 0, 0
+	examples := s.filter(query.Filter)
+	s.mu.Unlock()
+
+	include, exclude := searchTerms(query.Query)
+	results := make([]models.ExampleSearchResult, 0)
+	for _, example := range examples {
+		var (
+			rank float64
+			ok   bool
+		)
+		if query.Mode == service.SearchModeFuzzy {
+			rank = max(wordSimilarity(query.Query, example.Name), wordSimilarity(query.Query, example.Description))
+			ok = rank >= fuzzySearchThreshold
+		} else {
+			rank, ok = fullTextRank(include, exclude, example)
+		}
+		if !ok {
+			continue
+		}
+		results = append(results, models.ExampleSearchResult{
+			Example:       example,
+			Rank:          rank,
+			NameHighlight: highlight(example.Name, include),
+			Snippet:       highlight(example.Description, include),
+		})
+	}
+
+	slices.SortStableFunc(results, func(a, b models.ExampleSearchResult) int {
+		if a.Rank != b.Rank {
+			return cmp.Compare(b.Rank, a.Rank)
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	results = results[min(page.Offset, len(results)):]
+	return results[:min(page.Limit, len(results))], nil
+}
+
+// searchTerms разбирает q на искомые слова и исключённые через «-».
+func searchTerms(q string) (include, exclude []string) {
+	for _, field := range strings.Fields(q) {
+		if rest, ok := strings.CutPrefix(field, "-"); ok {
+			exclude = append(exclude, searchWords(rest)...)
+			continue
+		}
+		include = append(include, searchWords(field)...)
+	}
+	return include, exclude
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// fullTextRank считает долю слов, найденных в name, и вдвое меньший вес
+// для найденных только в description.
+func fullTextRank(include, exclude []string, example models.Example) (float64, bool) {
+	if len(include) == 0 {
+		return 0, false
+	}
+	name, description := searchWords(example.Name), searchWords(example.Description)
+	for _, word := range exclude {
+		if slices.Contains(name, word) || slices.Contains(description, word) {
+			return 0, false
+		}
+	}
+	var rank float64
+	for _, word := range include {
+		switch {
+		case slices.Contains(name, word):
+			rank++
+		case slices.Contains(description, word):
+			rank += 0.5
+		default:
+			return 0, false
+		}
+	}
+	return rank / float64(len(include)), true
+}
+
+// highlight обрамляет слова из terms тегами <mark>, как ts_headline, и
+// экранирует остальной текст для HTML.
+func highlight(text string, terms []string) string {
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if word := html.EscapeString(text[start:end]); slices.Contains(terms, strings.ToLower(text[start:end])) {
+			b.WriteString("<mark>" + word + "</mark>")
+		} else {
+			b.WriteString(word)
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			flush(i)
+		}
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	if start >= 0 {
+		flush(len(text))
+	}
+	return b.String()
+}
+
+// wordSimilarity — доля триграмм query, найденных в text; приближение
+// word_similarity из pg_trgm.
+func wordSimilarity(query, text string) float64 {
+	q := trigrams(query)
+	if len(q) == 0 {
+		return 0
+	}
+	t := trigrams(text)
+	found := 0
+	for trigram := range q {
+		if _, ok := t[trigram]; ok {
+			found++
+		}
+	}
+	return float64(found) / float64(len(q))
+}
+
+// trigrams разбивает слова text на триграммы так же, как pg_trgm: слово
+// дополняется двумя пробелами в начале и одним в конце.
+func trigrams(text string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range searchWords(text) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+type valueStats struct {
+	count         int64
+	sum, min, max float64
+}
+
+func (v *valueStats) add(value float64) {
+	if v.count == 0 || value < v.min {
+		v.min = value
+	}
+	if v.count == 0 || value > v.max {
+		v.max = value
+	}
+	v.count++
+	v.sum += value
+}
+
+func (v *valueStats) result() models.ValueStats {
+	stats := models.ValueStats{Count: v.count, Sum: v.sum}
+	if v.count > 0 {
+		avg, minValue, maxValue := v.sum/float64(v.count), v.min, v.max
+		stats.Avg, stats.Min, stats.Max = &avg, &minValue, &maxValue
+	}
+	return stats
+}
+
+// truncateTime обрезает t до начала интервала, как date_trunc: неделя
+// начинается с понедельника.
+func truncateTime(t time.Time, bucket string) time.Time {
+	year, month, day := t.Date()
+	switch bucket {
+	case service.StatsBucketHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case service.StatsBucketWeek:
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case service.StatsBucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func (s *Storage) filter(filter models.ExampleFilter) []models.Example {
+	var examples []models.Example
+	for _, id := range slices.Sorted(maps.Keys(s.examples)) {
+		example := s.examples[id]
+		if filter.IsActive != nil && example.IsActive != *filter.IsActive {
+			continue
+		}
+		if filter.CreatedAfter != nil && example.CreatedAt.Before(*filter.CreatedAfter) {
+			continue
+		}
+		if filter.CreatedBefore != nil && !example.CreatedAt.Before(*filter.CreatedBefore) {
+			continue
+		}
+		examples = append(examples, example)
+	}
+
+	if filter.Offset >= len(examples) {
+		return nil
+	}
+	examples = examples[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(examples) {
+		examples = examples[:filter.Limit]
+	}
+
+	return examples
+}
+
+func (s *Storage) UpdateExample(_ context.Context, example *models.Example) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.examples[example.ID]
+	if !ok {
+		return storageerrors.ErrNotFound
+	}
+
+	example.CreatedAt = current.CreatedAt
+	example.ExternalKey = current.ExternalKey
+	s.examples[example.ID] = *example
+	s.addRevision(service.RevisionUpdated, *example)
+
+	return nil
+}
+
+func (s *Storage) DeleteExample(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.examples[id]
+	if !ok {
+		return storageerrors.ErrNotFound
+	}
+	delete(s.examples, id)
+	s.addRevision(service.RevisionDeleted, current)
+
+	return nil
+}
+
+// WithinTx выполняет fn на копии данных и публикует её, только если fn
+// вернула nil. Транзакции сериализуются: на время fn остальные операции с
+// хранилищем ждут, поэтому повторы при конфликтах не нужны. Вложенный вызов
+// на tx работает так же и ведёт себя как savepoint.
+func (s *Storage) WithinTx(_ context.Context, fn func(tx service.TxStorage) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &Storage{
+		examples: maps.Clone(s.examples),
+		nextID:   s.nextID,
+		outbox:   slices.Clone(s.outbox),
+		audit:    slices.Clone(s.audit),
+		// Срезы версий общие с исходной картой: addRevision не дописывает
+		// в них на месте.
+		revisions: maps.Clone(s.revisions),
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.examples = tx.examples
+	s.nextID = tx.nextID
+	s.outbox = tx.outbox
+	s.audit = tx.audit
+	s.revisions = tx.revisions
+
+	return nil
+}
+
+func (s *Storage) CreateExamples(ctx context.Context, examples []*models.Example) error {
+	for _, example := range examples {
+		if err := s.CreateExample(ctx, example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error) {
+	itemErrs := make([]error, len(examples))
+	for i, example := range examples {
+		itemErrs[i] = s.UpdateExample(ctx, example)
+	}
+	return itemErrs, nil
+}
+
+func (s *Storage) DeleteExamples(ctx context.Context, ids []int) ([]error, error) {
+	itemErrs := make([]error, len(ids))
+	for i, id := range ids {
+		itemErrs[i] = s.DeleteExample(ctx, id)
+	}
+	return itemErrs, nil
+}
+
+func (s *Storage) UpsertExamples(_ context.Context, examples []*models.Example) ([]*models.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byKey := make(map[string]int)
+	for id, example := range s.examples {
+		if example.ExternalKey != "" {
+			byKey[example.ExternalKey] = id
+		}
+	}
+
+	previous := make([]*models.Example, len(examples))
+	for i, example := range examples {
+		op := service.RevisionCreated
+		if id, ok := byKey[example.ExternalKey]; ok {
+			before := s.examples[id]
+			previous[i], op = &before, service.RevisionUpdated
+			example.ID = id
+			example.CreatedAt = before.CreatedAt
+		} else {
+			example.ID = s.nextID
+			s.nextID++
+			byKey[example.ExternalKey] = example.ID
+		}
+		s.examples[example.ID] = *example
+		s.addRevision(op, *example)
+	}
+
+	return previous, nil
+}
+
+// AppendOutbox сохраняет сообщение в памяти; relay для этой реализации нет,
+// сообщения доступны через Outbox.
+func (s *Storage) AppendOutbox(_ context.Context, msg *models.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg.ID = int64(len(s.outbox) + 1)
+	msg.CreatedAt = time.Now()
+	s.outbox = append(s.outbox, *msg)
+
+	return nil
+}
+
+// Outbox возвращает копию записанных сообщений outbox.
+func (s *Storage) Outbox() []models.OutboxMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.outbox)
+}
+
+// AppendAudit сохраняет запись журнала аудита в памяти; записи доступны
+// через Audit.
+func (s *Storage) AppendAudit(_ context.Context, entry *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = int64(len(s.audit) + 1)
+	entry.CreatedAt = time.Now()
+	s.audit = append(s.audit, *entry)
+
+	return nil
+}
+
+// Audit возвращает копию журнала аудита.
+func (s *Storage) Audit() []models.AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.audit)
+}
+
+// addRevision записывает версию записи, как триггеры example_revisions.
+// Вызывается под s.mu.
+func (s *Storage) addRevision(op string, example models.Example) {
+	history := s.revisions[example.ID]
+	s.revisions[example.ID] = append(slices.Clip(history), models.ExampleRevision{
+		ExampleID: example.ID,
+		Revision:  len(history) + 1,
+		Op:        op,
+		Example:   example,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *Storage) GetExampleRevisions(_ context.Context, id, limit, offset int) ([]models.ExampleRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revisions := slices.Clone(s.revisions[id])
+	slices.Reverse(revisions)
+	if offset >= len(revisions) {
+		return nil, nil
+	}
+	revisions = revisions[offset:]
+	return revisions[:min(limit, len(revisions))], nil
+}
+
+func (s *Storage) GetExampleRevision(_ context.Context, id, rev int) (*models.ExampleRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.revisions[id]
+	if rev <= 0 || rev > len(history) {
+		return nil, storageerrors.ErrNotFound
+	}
+	revision := history[rev-1]
+	return &revision, nil
+}
+
+func (s *Storage) GetExampleRevisionAt(_ context.Context, id int, asOf time.Time) (*models.ExampleRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.revisions[id]
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].CreatedAt.After(asOf) {
+			revision := history[i]
+			return &revision, nil
+		}
+	}
+	return nil, storageerrors.ErrNotFound
+}
@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+	storageerrors "go-service-template/internal/storage"
+)
+
+func TestStorage_CRUD(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+
+	example := &models.Example{Name: "first"}
+	if err := st.CreateExample(ctx, example); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if example.ID != 1 {
+		t.Fatalf("expected ID 1, got %d", example.ID)
+	}
+
+	example.Name = "renamed"
+	if err := st.UpdateExample(ctx, example); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := st.GetExampleByID(ctx, 1)
+	if err != nil || got.Name != "renamed" {
+		t.Fatalf("expected renamed example, got %+v (err %v)", got, err)
+	}
+
+	if err := st.DeleteExample(ctx, 1); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := st.GetExampleByID(ctx, 1); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := st.UpdateExample(ctx, &models.Example{ID: 1}); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on update, got %v", err)
+	}
+}
+
+func TestStorage_GetAllExamplesPagination(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+	for range 5 {
+		_ = st.CreateExample(ctx, &models.Example{Name: "n"})
+	}
+
+	got, _ := st.GetAllExamples(ctx, models.ExampleFilter{Limit: 2, Offset: 1})
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Fatalf("unexpected page: %+v", got)
+	}
+	if got, _ := st.GetAllExamples(ctx, models.ExampleFilter{Limit: 10, Offset: 10}); len(got) != 0 {
+		t.Fatalf("expected empty page, got %+v", got)
+	}
+}
+
+func TestStorage_Filter(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		_ = st.CreateExample(ctx, &models.Example{Name: "n", IsActive: i%2 == 0, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+
+	active := true
+	after, before := base.Add(time.Hour), base.Add(3*time.Hour)
+	got, _ := st.GetAllExamples(ctx, models.ExampleFilter{IsActive: &active})
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 3 {
+		t.Fatalf("unexpected active examples: %+v", got)
+	}
+
+	var ids []int
+	err := st.StreamExamples(ctx, models.ExampleFilter{CreatedAfter: &after, CreatedBefore: &before}, func(example *models.Example) error {
+		ids = append(ids, example.ID)
+		return nil
+	})
+	if err != nil || !slices.Equal(ids, []int{2, 3}) {
+		t.Fatalf("unexpected streamed ids %v, err %v", ids, err)
+	}
+}
+
+func TestStorage_GetExampleStats(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+	// 2026-03-01 — воскресенье; в Москве (UTC+3) последние две записи
+	// попадают уже в понедельник.
+	base := time.Date(2026, 3, 1, 19, 0, 0, 0, time.UTC)
+	for i, value := range []float64{1, 2, 3, 6} {
+		_ = st.CreateExample(ctx, &models.Example{Name: "n", Value: value, IsActive: i != 1, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+
+	stats, err := st.GetExampleStats(ctx, models.ExampleStatsQuery{Bucket: service.StatsBucketWeek, Timezone: "Europe/Moscow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Total.Count != 4 || stats.Total.Sum != 12 || *stats.Total.Avg != 3 || *stats.Total.Min != 1 || *stats.Total.Max != 6 {
+		t.Fatalf("unexpected total %+v", stats.Total)
+	}
+	if len(stats.ByActive) != 2 || !stats.ByActive[0].IsActive || stats.ByActive[0].Count != 3 || stats.ByActive[1].Sum != 2 {
+		t.Fatalf("unexpected by_active %+v", stats.ByActive)
+	}
+	if len(stats.Buckets) != 2 || stats.Buckets[0].Count != 2 || stats.Buckets[1].Count != 2 ||
+		stats.Buckets[1].Start.Format(time.RFC3339) != "2026-03-02T00:00:00+03:00" {
+		t.Fatalf("unexpected buckets %+v", stats.Buckets)
+	}
+
+	active := false
+	stats, _ = st.GetExampleStats(ctx, models.ExampleStatsQuery{Filter: models.ExampleFilter{IsActive: &active, CreatedAfter: &base}})
+	if stats.Total.Count != 1 || len(stats.ByActive) != 1 || stats.Buckets != nil {
+		t.Fatalf("unexpected filtered stats %+v", stats)
+	}
+
+	stats, _ = st.GetExampleStats(ctx, models.ExampleStatsQuery{Filter: models.ExampleFilter{CreatedBefore: &base}})
+	if stats.Total.Count != 0 || stats.Total.Avg != nil || stats.ByActive == nil {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+}
+
+func TestStorage_SearchExamples(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+	_ = st.CreateExample(ctx, &models.Example{Name: "Green apple", Description: "Sour"})
+	_ = st.CreateExample(ctx, &models.Example{Name: "Pie", Description: "Baked with an apple, cinnamon"})
+	_ = st.CreateExample(ctx, &models.Example{Name: "Red apple", Description: "Sweet"})
+	page := models.ExampleFilter{Limit: 10}
+
+	results, _ := st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: page, Query: "APPLE"})
+	if len(results) != 3 || results[0].ID != 1 || results[1].ID != 3 || results[2].ID != 2 {
+		t.Fatalf("expected name matches ranked first, got %+v", results)
+	}
+	if results[0].NameHighlight != "Green <mark>apple</mark>" || results[2].Snippet != "Baked with an <mark>apple</mark>, cinnamon" {
+		t.Fatalf("unexpected highlights %q, %q", results[0].NameHighlight, results[2].Snippet)
+	}
+
+	_ = st.CreateExample(ctx, &models.Example{Name: "Tart", Description: `<img src=x onerror="alert(1)"> & apple`})
+	results, _ = st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: page, Query: "onerror"})
+	if len(results) != 1 || results[0].Snippet != "&lt;img src=x <mark>onerror</mark>=&#34;alert(1)&#34;&gt; &amp; apple" {
+		t.Fatalf("expected escaped snippet, got %+v", results)
+	}
+	_ = st.DeleteExample(ctx, 4)
+
+	results, _ = st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: page, Query: "apple -green"})
+	if len(results) != 2 || results[0].ID != 3 {
+		t.Fatalf("expected excluded word to filter results, got %+v", results)
+	}
+
+	results, _ = st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: models.ExampleFilter{Limit: 1, Offset: 1}, Query: "apple"})
+	if len(results) != 1 || results[0].ID != 3 {
+		t.Fatalf("unexpected page %+v", results)
+	}
+
+	results, _ = st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: page, Query: "cinamon"})
+	if len(results) != 0 {
+		t.Fatalf("expected no full-text match for a typo, got %+v", results)
+	}
+	results, _ = st.SearchExamples(ctx, models.ExampleSearchQuery{Filter: page, Query: "cinamon", Mode: service.SearchModeFuzzy})
+	if len(results) != 1 || results[0].ID != 2 {
+		t.Fatalf("expected fuzzy match for a typo, got %+v", results)
+	}
+}
+
+func TestStorage_UpsertExamples(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+
+	first := &models.Example{Name: "first", ExternalKey: "k1"}
+	previous, _ := st.UpsertExamples(ctx, []*models.Example{first})
+	if previous[0] != nil || first.ID != 1 {
+		t.Fatalf("expected insert with ID 1, got %v %+v", previous, first)
+	}
+
+	again := &models.Example{Name: "updated", ExternalKey: "k1"}
+	other := &models.Example{Name: "second", ExternalKey: "k2"}
+	previous, _ = st.UpsertExamples(ctx, []*models.Example{again, other})
+	if previous[0] == nil || previous[0].Name != "first" || previous[1] != nil || again.ID != 1 || other.ID != 2 {
+		t.Fatalf("unexpected upsert result %v: %+v %+v", previous, again, other)
+	}
+
+	// Обновление через API не затирает внешний ключ.
+	_ = st.UpdateExample(ctx, &models.Example{ID: 1, Name: "via api"})
+	got, _ := st.GetExampleByID(ctx, 1)
+	if got.Name != "via api" || got.ExternalKey != "k1" {
+		t.Fatalf("unexpected example: %+v", got)
+	}
+}
+
+func TestStorage_WithinTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commit", func(t *testing.T) {
+		st := NewStorage()
+		err := st.WithinTx(ctx, func(tx service.TxStorage) error {
+			if err := tx.CreateExample(ctx, &models.Example{Name: "in tx"}); err != nil {
+				return err
+			}
+			return tx.AppendOutbox(ctx, &models.OutboxMessage{EventType: "example.created"})
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := st.GetExampleByID(ctx, 1); err != nil {
+			t.Fatalf("expected committed example, got %v", err)
+		}
+		if got := st.Outbox(); len(got) != 1 || got[0].ID != 1 {
+			t.Fatalf("expected committed outbox message, got %+v", got)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		st := NewStorage()
+		errAbort := errors.New("abort")
+		err := st.WithinTx(ctx, func(tx service.TxStorage) error {
+			if err := tx.CreateExample(ctx, &models.Example{Name: "in tx"}); err != nil {
+				return err
+			}
+			if err := tx.AppendOutbox(ctx, &models.OutboxMessage{EventType: "example.created"}); err != nil {
+				return err
+			}
+			if err := tx.AppendAudit(ctx, &models.AuditEntry{Action: "create"}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected errAbort, got %v", err)
+		}
+		if _, err := st.GetExampleByID(ctx, 1); !errors.Is(err, storageerrors.ErrNotFound) {
+			t.Fatalf("expected rolled back example, got %v", err)
+		}
+		if got := st.Outbox(); len(got) != 0 {
+			t.Fatalf("expected rolled back outbox, got %+v", got)
+		}
+		if got := st.Audit(); len(got) != 0 {
+			t.Fatalf("expected rolled back audit log, got %+v", got)
+		}
+	})
+
+	t.Run("nested rollback keeps outer changes", func(t *testing.T) {
+		st := NewStorage()
+		err := st.WithinTx(ctx, func(tx service.TxStorage) error {
+			if err := tx.CreateExample(ctx, &models.Example{Name: "outer"}); err != nil {
+				return err
+			}
+			_ = tx.(service.Storage).WithinTx(ctx, func(nested service.TxStorage) error {
+				_ = nested.CreateExample(ctx, &models.Example{Name: "inner"})
+				return errors.New("rollback savepoint")
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, _ := st.GetAllExamples(ctx, models.ExampleFilter{Limit: 10})
+		if len(got) != 1 || got[0].Name != "outer" {
+			t.Fatalf("expected only outer example, got %+v", got)
+		}
+	})
+}
+
+func TestStorage_Revisions(t *testing.T) {
+	ctx := context.Background()
+	st := NewStorage()
+
+	_ = st.CreateExample(ctx, &models.Example{Name: "v1"})
+	_ = st.UpdateExample(ctx, &models.Example{ID: 1, Name: "v2"})
+	beforeDelete := time.Now()
+	_ = st.DeleteExample(ctx, 1)
+
+	revisions, _ := st.GetExampleRevisions(ctx, 1, 10, 0)
+	if len(revisions) != 3 || revisions[0].Revision != 3 || revisions[0].Op != "deleted" || revisions[0].Example.Name != "v2" {
+		t.Fatalf("unexpected history %+v", revisions)
+	}
+
+	revision, err := st.GetExampleRevision(ctx, 1, 1)
+	if err != nil || revision.Example.Name != "v1" {
+		t.Fatalf("unexpected revision %+v, err %v", revision, err)
+	}
+	if _, err := st.GetExampleRevision(ctx, 1, 4); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	revision, err = st.GetExampleRevisionAt(ctx, 1, beforeDelete)
+	if err != nil || revision.Revision != 2 {
+		t.Fatalf("expected revision 2 before delete, got %+v, err %v", revision, err)
+	}
+	if _, err := st.GetExampleRevisionAt(ctx, 1, time.Time{}); !errors.Is(err, storageerrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before creation, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var _ service.AuditStorage = (*PostgresStorage)(nil)
+
+// AppendAudit пишет запись в audit_log. Внутри WithinTx запись фиксируется
+// вместе с изменением данных.
+func (s *PostgresStorage) AppendAudit(ctx context.Context, entry *models.AuditEntry) error {
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO audit_log (actor, action, entity_type, entity_id, before, after, request_id, client_ip)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''))
+		RETURNING id, created_at`,
+		entry.Actor, entry.Action, entry.EntityType, entry.EntityID, entry.Before, entry.After,
+		entry.RequestID, entry.ClientIP).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog возвращает записи журнала по фильтру, новые первыми.
+func (s *PostgresStorage) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query, args := auditFilterQuery(filter)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.AuditEntry, error) {
+		var e models.AuditEntry
+		err := row.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &e.Before, &e.After,
+			&e.RequestID, &e.ClientIP, &e.CreatedAt)
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// auditSelect — выборка журнала; отсутствующее состояние читается как JSON null.
+const auditSelect = `SELECT id, actor, action, entity_type, entity_id,
+	COALESCE(before, 'null'::jsonb), COALESCE(after, 'null'::jsonb),
+	COALESCE(request_id, ''), COALESCE(client_ip, ''), created_at FROM audit_log`
+
+// auditFilterQuery собирает SELECT по audit_log, как exampleFilterQuery.
+func auditFilterQuery(filter models.AuditFilter) (string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.Actor != "" {
+		where = append(where, "actor = "+arg(filter.Actor))
+	}
+	if filter.EntityType != "" {
+		where = append(where, "entity_type = "+arg(filter.EntityType))
+	}
+	if filter.EntityID != "" {
+		where = append(where, "entity_id = "+arg(filter.EntityID))
+	}
+	if filter.CreatedAfter != nil {
+		where = append(where, "created_at >= "+arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		where = append(where, "created_at < "+arg(*filter.CreatedBefore))
+	}
+
+	var b strings.Builder
+	b.WriteString(auditSelect)
+	if len(where) > 0 {
+		b.WriteString(" WHERE " + strings.Join(where, " AND "))
+	}
+	b.WriteString(" ORDER BY id DESC")
+	if filter.Limit > 0 {
+		b.WriteString(" LIMIT " + arg(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		b.WriteString(" OFFSET " + arg(filter.Offset))
+	}
+
+	return b.String(), args
+}
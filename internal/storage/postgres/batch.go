@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-service-template/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var exampleCopyColumns = []string{"id", "name", "description", "value", "is_active", "created_at", "updated_at"}
+
+// CreateExamples вставляет записи через COPY. COPY не умеет RETURNING, поэтому
+// ID заранее берутся из последовательности одним запросом. Вне транзакции
+// вставка всё равно атомарна: COPY — одна команда.
+func (s *PostgresStorage) CreateExamples(ctx context.Context, examples []*models.Example) error {
+	if len(examples) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT nextval(pg_get_serial_sequence('examples', 'id')) FROM generate_series(1, $1)`,
+		len(examples))
+	if err != nil {
+		return fmt.Errorf("failed to allocate example ids: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("failed to allocate example ids: %w", err)
+	}
+
+	_, err = s.db.CopyFrom(ctx, pgx.Identifier{"examples"}, exampleCopyColumns,
+		pgx.CopyFromSlice(len(examples), func(i int) ([]any, error) {
+			example := examples[i]
+			return []any{ids[i], example.Name, example.Description, example.Value,
+				example.IsActive, example.CreatedAt, example.UpdatedAt}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("failed to copy examples: %w", err)
+	}
+
+	for i, example := range examples {
+		example.ID = ids[i]
+	}
+
+	return nil
+}
+
+// UpdateExamples отправляет все UPDATE ... RETURNING одним pipeline (pgx.Batch).
+func (s *PostgresStorage) UpdateExamples(ctx context.Context, examples []*models.Example) ([]error, error) {
+	if len(examples) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		UPDATE examples
+		SET name = $1, description = $2, value = $3, is_active = $4, updated_at = $5
+		WHERE id = $6
+		RETURNING ` + exampleColumns
+
+	batch := &pgx.Batch{}
+	for _, example := range examples {
+		batch.Queue(query, example.Name, example.Description, example.Value,
+			example.IsActive, example.UpdatedAt, example.ID)
+	}
+
+	results := s.db.SendBatch(ctx, batch)
+	itemErrs := make([]error, len(examples))
+	for i, example := range examples {
+		err := scanExample(results.QueryRow(), example)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			itemErrs[i] = ErrExampleNotFound
+		case err != nil:
+			itemErrs[i] = fmt.Errorf("failed to update example: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return itemErrs, fmt.Errorf("failed to update examples: %w", err)
+	}
+
+	return itemErrs, nil
+}
+
+// DeleteExamples отправляет все DELETE одним pipeline (pgx.Batch).
+func (s *PostgresStorage) DeleteExamples(ctx context.Context, ids []int) ([]error, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, id := range ids {
+		batch.Queue(`DELETE FROM examples WHERE id = $1`, id)
+	}
+
+	results := s.db.SendBatch(ctx, batch)
+	itemErrs := make([]error, len(ids))
+	for i := range ids {
+		ct, err := results.Exec()
+		switch {
+		case err != nil:
+			itemErrs[i] = fmt.Errorf("failed to delete example: %w", err)
+		case ct.RowsAffected() == 0:
+			itemErrs[i] = ErrExampleNotFound
+		}
+	}
+	if err := results.Close(); err != nil {
+		return itemErrs, fmt.Errorf("failed to delete examples: %w", err)
+	}
+
+	return itemErrs, nil
+}
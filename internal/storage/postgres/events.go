@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-service-template/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventsChannel — канал NOTIFY, в который триггеры example_events (миграция
+// 000005) сигналят о новых событиях, а sequence_example_events (000014) — о
+// назначенных позициях. Payload пустой: события читаются из
+// таблицы по ID.
+const EventsChannel = "example_events"
+
+// EventsSince возвращает до limit событий с ID больше afterID по возрастанию ID.
+// ID события — его позиция в журнале (миграция 000014): перед чтением
+// позиции назначаются событиям уже завершённых транзакций.
+func (s *PostgresStorage) EventsSince(ctx context.Context, afterID int64, limit int) ([]models.ExampleEvent, error) {
+	if _, err := s.db.Exec(ctx, `SELECT sequence_example_events()`); err != nil {
+		return nil, fmt.Errorf("failed to sequence example events: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT position, type, example_id, data, created_at
+		FROM example_events
+		WHERE position > $1
+		ORDER BY position
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query example events: %w", err)
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ExampleEvent, error) {
+		var event models.ExampleEvent
+		err := row.Scan(&event.ID, &event.Type, &event.ExampleID, &event.Example, &event.CreatedAt)
+		return event, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan example events: %w", err)
+	}
+	return events, nil
+}
+
+// EventBounds возвращает ID самого старого и самого нового события журнала
+// (0, 0 — журнал пуст).
+func (s *PostgresStorage) EventBounds(ctx context.Context) (oldest, latest int64, err error) {
+	err = s.db.QueryRow(ctx, `SELECT COALESCE(MIN(position), 0), COALESCE(MAX(position), 0) FROM example_events`).Scan(&oldest, &latest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read example events bounds: %w", err)
+	}
+	return oldest, latest, nil
+}
+
+// DeleteEventsBefore удаляет события, созданные раньше before. События без
+// position ещё не упорядочены и подписчикам не отданы — их не трогает.
+func (s *PostgresStorage) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	ct, err := s.db.Exec(ctx, `DELETE FROM example_events WHERE created_at < $1 AND position IS NOT NULL`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old example events: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
+
+// ListenEvents вызывает notify на каждое уведомление EventsChannel и после
+// каждой (пере)подписки, чтобы подписчик дочитал события, пропущенные за
+// время обрыва.
+func (s *PostgresStorage) ListenEvents(ctx context.Context, notify func(), logger *slog.Logger) error {
+	return s.listen(ctx, EventsChannel, logger,
+		func(context.Context) { notify() },
+		func(context.Context, string) { notify() },
+	)
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"go-service-template/internal/models"
+)
+
+// StreamExamples читает выборку построчно: pgx.Rows получает строки из сокета
+// по мере чтения, поэтому в памяти одновременно находится одна запись, а
+// медленный потребитель через TCP-окно притормаживает и сам PostgreSQL.
+// Соединение занято до конца чтения — для многочасовых выгрузок лучше
+// отдельная реплика.
+func (s *PostgresStorage) StreamExamples(ctx context.Context, filter models.ExampleFilter, fn func(*models.Example) error) error {
+	query, args := exampleFilterQuery(filter)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query examples: %w", err)
+	}
+	defer rows.Close()
+
+	var example models.Example
+	for rows.Next() {
+		if err := scanExample(rows, &example); err != nil {
+			return fmt.Errorf("failed to scan example: %w", err)
+		}
+		if err := fn(&example); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate examples: %w", err)
+	}
+
+	return nil
+}
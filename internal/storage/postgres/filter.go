@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+
+	"go-service-template/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const exampleColumns = `id, name, description, value, is_active, created_at, updated_at, external_key`
+
+// exampleFilterQuery собирает SELECT по examples с условиями фильтра. Значения
+// передаются только через плейсхолдеры; Limit = 0 — без LIMIT.
+func exampleFilterQuery(filter models.ExampleFilter) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT " + exampleColumns + " FROM examples")
+	b.WriteString(exampleWhere(filter, arg))
+	b.WriteString(" ORDER BY id")
+	if filter.Limit > 0 {
+		b.WriteString(" LIMIT " + arg(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		b.WriteString(" OFFSET " + arg(filter.Offset))
+	}
+
+	return b.String(), args
+}
+
+// exampleWhere возвращает " WHERE ..." по условиям фильтра (пустую строку без
+// условий); arg добавляет значение в аргументы запроса и возвращает его
+// плейсхолдер.
+func exampleWhere(filter models.ExampleFilter, arg func(v any) string) string {
+	var where []string
+	if filter.IsActive != nil {
+		where = append(where, "is_active = "+arg(*filter.IsActive))
+	}
+	if filter.CreatedAfter != nil {
+		where = append(where, "created_at >= "+arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		where = append(where, "created_at < "+arg(*filter.CreatedBefore))
+	}
+
+	if len(where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(where, " AND ")
+}
+
+// scanExample читает строку, выбранную по exampleColumns.
+func scanExample(row pgx.Row, example *models.Example) error {
+	var externalKey *string
+	err := row.Scan(
+		&example.ID, &example.Name, &example.Description, &example.Value,
+		&example.IsActive, &example.CreatedAt, &example.UpdatedAt, &externalKey,
+	)
+	if err != nil {
+		return err
+	}
+	example.ExternalKey = ""
+	if externalKey != nil {
+		example.ExternalKey = *externalKey
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+)
+
+func TestExampleFilterQuery(t *testing.T) {
+	active := false
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter models.ExampleFilter
+		where  string
+		args   int
+	}{
+		{"no filter", models.ExampleFilter{}, " ORDER BY id", 0},
+		{"pagination", models.ExampleFilter{Limit: 10, Offset: 20}, " ORDER BY id LIMIT $1 OFFSET $2", 2},
+		{
+			"all conditions",
+			models.ExampleFilter{IsActive: &active, CreatedAfter: &after, CreatedBefore: &after, Limit: 5},
+			" WHERE is_active = $1 AND created_at >= $2 AND created_at < $3 ORDER BY id LIMIT $4",
+			4,
+		},
+	}
+
+	for _, tt := range tests {
+		query, args := exampleFilterQuery(tt.filter)
+		want := "SELECT " + exampleColumns + " FROM examples" + tt.where
+		if query != want {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, query, want)
+		}
+		if len(args) != tt.args {
+			t.Errorf("%s: expected %d args, got %d", tt.name, tt.args, len(args))
+		}
+	}
+}
+
+func TestAuditFilterQuery(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter models.AuditFilter
+		where  string
+		args   int
+	}{
+		{"no filter", models.AuditFilter{}, " ORDER BY id DESC", 0},
+		{
+			"all conditions",
+			models.AuditFilter{Actor: "alice", EntityType: "example", EntityID: "7", CreatedAfter: &after, CreatedBefore: &after, Limit: 5, Offset: 10},
+			" WHERE actor = $1 AND entity_type = $2 AND entity_id = $3 AND created_at >= $4 AND created_at < $5 ORDER BY id DESC LIMIT $6 OFFSET $7",
+			7,
+		},
+	}
+
+	for _, tt := range tests {
+		query, args := auditFilterQuery(tt.filter)
+		if want := auditSelect + tt.where; query != want {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, query, want)
+		}
+		if len(args) != tt.args {
+			t.Errorf("%s: expected %d args, got %d", tt.name, tt.args, len(args))
+		}
+	}
+}
+
+func TestExampleStatsQuery(t *testing.T) {
+	active := true
+
+	query, args := exampleStatsQuery(models.ExampleStatsQuery{Filter: models.ExampleFilter{IsActive: &active}})
+	if !strings.Contains(query, "FROM examples WHERE is_active = $1") || !strings.Contains(query, "GROUPING SETS ((), (is_active))") || len(args) != 1 {
+		t.Errorf("unexpected query without bucket: %q, args %v", query, args)
+	}
+
+	query, args = exampleStatsQuery(models.ExampleStatsQuery{Filter: models.ExampleFilter{IsActive: &active}, Bucket: "day", Timezone: "UTC"})
+	if !strings.Contains(query, "date_trunc($2, created_at, $3) AS bucket FROM examples WHERE is_active = $1") ||
+		!strings.Contains(query, "GROUPING SETS ((), (is_active), (bucket))") || len(args) != 3 {
+		t.Errorf("unexpected query with bucket: %q, args %v", query, args)
+	}
+}
+
+func TestExampleSearchQuery(t *testing.T) {
+	active := true
+	filter := models.ExampleFilter{IsActive: &active, Limit: 10, Offset: 20}
+
+	query, args := exampleSearchQuery(models.ExampleSearchQuery{Filter: filter, Query: "apple", Mode: service.SearchModeFullText}, "english")
+	if !strings.Contains(query, "FROM examples, q WHERE is_active = $3 AND search_vector @@ q.query") ||
+		!strings.Contains(query, "LIMIT $4 OFFSET $5") || len(args) != 5 || args[0] != "english" {
+		t.Errorf("unexpected full-text query: %q, args %v", query, args)
+	}
+	if !strings.Contains(query, "ts_headline($1::regconfig, replace(replace(replace(replace(replace(name, '&', '&amp;')") {
+		t.Errorf("expected highlights built from escaped text: %q", query)
+	}
+
+	query, args = exampleSearchQuery(models.ExampleSearchQuery{Filter: models.ExampleFilter{Limit: 10}, Query: "aple", Mode: service.SearchModeFuzzy}, "english")
+	if !strings.Contains(query, "FROM examples WHERE ($2 <% name OR $2 <% description)") || len(args) != 3 {
+		t.Errorf("unexpected fuzzy query: %q, args %v", query, args)
+	}
+}
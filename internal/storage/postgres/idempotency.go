@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/idempotency"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyStore хранит ключи Idempotency-Key в таблице idempotency_keys.
+// Время сравнивается по часам БД, поэтому реплики с рассинхроном часов
+// одинаково понимают истечение блокировок и TTL.
+type IdempotencyStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ idempotency.Store = (*IdempotencyStore)(nil)
+
+// IdempotencyStore возвращает хранилище ключей идемпотентности на том же пуле.
+func (s *PostgresStorage) IdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{pool: s.pool}
+}
+
+// Acquire вставляет запись или перезахватывает истёкшую/брошенную одним
+// INSERT ... ON CONFLICT: конкурентные запросы с одним ключом сериализуются
+// на первичном ключе, и закрепить его успевает только один.
+func (s *IdempotencyStore) Acquire(ctx context.Context, principal, key string, requestHash []byte, lockTimeout, ttl time.Duration) (*idempotency.Record, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (principal, key, request_hash, locked_until, created_at, expires_at)
+		VALUES ($1, $2, $3, now() + make_interval(secs => $4), now(), now() + make_interval(secs => $5))
+		ON CONFLICT (principal, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, response_headers = NULL,
+			response_body = NULL, locked_until = EXCLUDED.locked_until,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= now())
+		RETURNING true`
+
+	var acquired bool
+	err := s.pool.QueryRow(ctx, query, principal, key, requestHash, lockTimeout.Seconds(), ttl.Seconds()).Scan(&acquired)
+	if err == nil {
+		return &idempotency.Record{RequestHash: requestHash}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("failed to acquire idempotency key: %w", err)
+	}
+
+	record := &idempotency.Record{}
+	var (
+		status  *int
+		headers map[string]string
+		body    []byte
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT request_hash, status_code, response_headers, response_body
+		FROM idempotency_keys
+		WHERE principal = $1 AND key = $2`, principal, key).Scan(&record.RequestHash, &status, &headers, &body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	if status != nil {
+		record.Response = &idempotency.Response{Status: *status, Headers: headers, Body: body}
+	}
+
+	return record, false, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, principal, key string, resp *idempotency.Response) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_headers = $4, response_body = $5, locked_until = NULL
+		WHERE principal = $1 AND key = $2 AND status_code IS NULL`,
+		principal, key, resp.Status, resp.Headers, resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, principal, key string) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE principal = $1 AND key = $2 AND status_code IS NULL`,
+		principal, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// UpsertExamples выполняет всю пачку одним INSERT ... ON CONFLICT: колонки
+// передаются массивами и разворачиваются через unnest. Прежнее состояние
+// обновляемых строк читается перед этим с FOR UPDATE: RETURNING отдаёт только
+// новые значения.
+func (s *PostgresStorage) UpsertExamples(ctx context.Context, examples []*models.Example) ([]*models.Example, error) {
+	if len(examples) == 0 {
+		return nil, nil
+	}
+
+	var (
+		keys         = make([]string, len(examples))
+		names        = make([]string, len(examples))
+		descriptions = make([]string, len(examples))
+		values       = make([]float64, len(examples))
+		active       = make([]bool, len(examples))
+		createdAt    = make([]time.Time, len(examples))
+		updatedAt    = make([]time.Time, len(examples))
+		byKey        = make(map[string]int, len(examples))
+	)
+	for i, example := range examples {
+		keys[i] = example.ExternalKey
+		names[i] = example.Name
+		descriptions[i] = example.Description
+		values[i] = example.Value
+		active[i] = example.IsActive
+		createdAt[i] = example.CreatedAt
+		updatedAt[i] = example.UpdatedAt
+		byKey[example.ExternalKey] = i
+	}
+
+	previous, err := s.lockExamplesByKey(ctx, keys, byKey)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO examples (external_key, name, description, value, is_active, created_at, updated_at)
+		SELECT * FROM unnest($1::varchar[], $2::varchar[], $3::text[], $4::float8[], $5::bool[], $6::timestamptz[], $7::timestamptz[])
+		ON CONFLICT (external_key) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, value = EXCLUDED.value,
+			is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+		RETURNING external_key, id, created_at`
+
+	rows, err := s.db.Query(ctx, query, keys, names, descriptions, values, active, createdAt, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert examples: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key     string
+			id      int
+			created time.Time
+		)
+		if err := rows.Scan(&key, &id, &created); err != nil {
+			return nil, fmt.Errorf("failed to scan upserted example: %w", err)
+		}
+		i, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("upsert returned unexpected key %q", key)
+		}
+		examples[i].ID, examples[i].CreatedAt = id, created
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to upsert examples: %w", err)
+	}
+
+	return previous, nil
+}
+
+// lockExamplesByKey читает и блокирует существующие строки с ключами keys;
+// результат — по индексам byKey, nil для ключей, которых нет. Строку с новым
+// ключом, вставленную конкурентно после чтения, upsert обновит, но она будет
+// отмечена как созданная.
+func (s *PostgresStorage) lockExamplesByKey(ctx context.Context, keys []string, byKey map[string]int) ([]*models.Example, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+exampleColumns+` FROM examples
+		WHERE external_key = ANY($1::varchar[])
+		ORDER BY id
+		FOR UPDATE`, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock upserted examples: %w", err)
+	}
+	defer rows.Close()
+
+	previous := make([]*models.Example, len(keys))
+	for rows.Next() {
+		example := &models.Example{}
+		if err := scanExample(rows, example); err != nil {
+			return nil, fmt.Errorf("failed to scan upserted example: %w", err)
+		}
+		previous[byKey[example.ExternalKey]] = example
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock upserted examples: %w", err)
+	}
+	return previous, nil
+}
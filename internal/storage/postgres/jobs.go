@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/jobs"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobStore — очередь фоновых задач.
+type JobStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ jobs.Store = (*JobStore)(nil)
+
+// JobStore возвращает очередь задач на том же пуле.
+func (s *PostgresStorage) JobStore() *JobStore {
+	return &JobStore{pool: s.pool}
+}
+
+func (s *JobStore) Insert(ctx context.Context, job jobs.NewJob) (int64, bool, error) {
+	// Нулевой RunAt — «сразу» по часам БД: с ними сравнивается run_at при
+	// захвате, и расхождение часов реплики не откладывает задачу.
+	var runAt *time.Time
+	if !job.RunAt.IsZero() {
+		runAt = &job.RunAt
+	}
+
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO jobs (kind, payload, unique_key, max_attempts, run_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, COALESCE($5, now()))
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running')
+		DO NOTHING
+		RETURNING id`,
+		job.Kind, job.Payload, job.UniqueKey, job.MaxAttempts, runAt).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	// Конфликт по ключу уникальности: отдаём ID незавершённой задачи. Если
+	// она успела завершиться между вставкой и чтением, вернётся ошибка —
+	// постановку можно повторить.
+	err = s.pool.QueryRow(ctx, `
+		SELECT id FROM jobs
+		WHERE kind = $1 AND unique_key = $2 AND status IN ('pending', 'running')`,
+		job.Kind, job.UniqueKey).Scan(&id)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find duplicate job: %w", err)
+	}
+	return id, false, nil
+}
+
+func (s *JobStore) Claim(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]jobs.Job, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE jobs j
+		SET status = 'running',
+			attempts = j.attempts + 1,
+			locked_until = now() + make_interval(secs => $3),
+			updated_at = now()
+		WHERE j.id IN (
+			SELECT id FROM jobs
+			WHERE kind = ANY($1)
+				AND ((status = 'pending' AND run_at <= now())
+					OR (status = 'running' AND locked_until <= now()))
+			ORDER BY run_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED)
+		RETURNING j.id, j.kind, j.payload, j.attempts, j.max_attempts, COALESCE(j.unique_key, ''), j.run_at, j.created_at`,
+		kinds, limit, visibility.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+
+	claimed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (jobs.Job, error) {
+		var job jobs.Job
+		err := row.Scan(&job.ID, &job.Kind, &job.Payload, &job.Attempt, &job.MaxAttempts,
+			&job.UniqueKey, &job.RunAt, &job.CreatedAt)
+		return job, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+// Extend, Complete, Retry, Fail и Release меняют задачу, только если её не
+// перезахватили после истечения таймаута видимости (номер попытки совпадает).
+
+func (s *JobStore) Extend(ctx context.Context, job jobs.Job, visibility time.Duration) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE jobs
+		SET locked_until = now() + make_interval(secs => $3), updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`, job.ID, job.Attempt, visibility.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to extend job claim: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (s *JobStore) Complete(ctx context.Context, job jobs.Job) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE jobs
+		SET status = 'succeeded', locked_until = NULL, last_error = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`, job.ID, job.Attempt)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+func (s *JobStore) Retry(ctx context.Context, job jobs.Job, retryIn time.Duration, errMsg string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE jobs
+		SET status = 'pending',
+			run_at = now() + make_interval(secs => $3),
+			locked_until = NULL,
+			last_error = $4,
+			updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`, job.ID, job.Attempt, retryIn.Seconds(), errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule job: %w", err)
+	}
+	return nil
+}
+
+func (s *JobStore) Fail(ctx context.Context, job jobs.Job, errMsg string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE jobs
+		SET status = 'failed', locked_until = NULL, last_error = $3, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`, job.ID, job.Attempt, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to fail job: %w", err)
+	}
+	return nil
+}
+
+func (s *JobStore) Release(ctx context.Context, job jobs.Job) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE jobs
+		SET status = 'pending', attempts = attempts - 1, locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`, job.ID, job.Attempt)
+	if err != nil {
+		return fmt.Errorf("failed to release job: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinishedBefore удаляет завершённые задачи, последний раз менявшиеся
+// раньше before.
+func (s *JobStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM jobs WHERE status IN ('succeeded', 'failed') AND updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
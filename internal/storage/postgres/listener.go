@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-service-template/internal/metrics"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ChangesChannel — канал NOTIFY, в который триггер examples_changed
+// (миграция 000004) пишет изменения и удаления записей.
+const ChangesChannel = "examples_changed"
+
+const (
+	listenRetryMinDelay = 100 * time.Millisecond
+	listenRetryMaxDelay = 30 * time.Second
+)
+
+// ChangeHandler получает изменения из ChangesChannel. Purge вызывается после
+// каждой (пере)подписки: пропущенные уведомления по отдельности не
+// восстановить, поэтому кеш сбрасывается целиком.
+type ChangeHandler interface {
+	Invalidate(ctx context.Context, ids ...int)
+	Purge(ctx context.Context)
+}
+
+// Change — payload уведомления ChangesChannel.
+type Change struct {
+	Op      string    `json:"op"`
+	ID      int       `json:"id"`
+	Version time.Time `json:"version"`
+}
+
+// ListenChanges держит отдельное соединение с LISTEN на ChangesChannel и
+// передаёт изменения в handler до отмены ctx.
+func (s *PostgresStorage) ListenChanges(ctx context.Context, handler ChangeHandler, logger *slog.Logger) error {
+	return s.listen(ctx, ChangesChannel, logger,
+		func(ctx context.Context) {
+			handler.Purge(ctx)
+			metrics.CacheFlushes.Add(1)
+		},
+		func(ctx context.Context, payload string) {
+			change, err := parseChange(payload)
+			if err != nil {
+				logger.Error("Invalid change notification", slog.String("payload", payload), slog.String("error", err.Error()))
+				return
+			}
+			handler.Invalidate(ctx, change.ID)
+			metrics.CacheRemoteInvalidations.Add(1)
+		},
+	)
+}
+
+// listen подписывается на channel и вызывает onNotify для каждого
+// уведомления до отмены ctx. Обрыв соединения не завершает работу:
+// слушатель переподключается с экспоненциальной задержкой и после каждой
+// подписки вызывает onSubscribe — уведомления, отправленные, пока слушателя
+// не было, потеряны. Соединение не берётся из пула, чтобы не занимать его слот.
+func (s *PostgresStorage) listen(ctx context.Context, channel string, logger *slog.Logger, onSubscribe func(ctx context.Context), onNotify func(ctx context.Context, payload string)) error {
+	delay := listenRetryMinDelay
+	for {
+		subscribed, err := s.listenOnce(ctx, channel, logger, onSubscribe, onNotify)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if subscribed {
+			delay = listenRetryMinDelay
+		}
+		metrics.ListenerReconnects.Add(channel, 1)
+		logger.Warn("Listener disconnected, reconnecting",
+			slog.String("channel", channel),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, listenRetryMaxDelay)
+	}
+}
+
+// listenOnce обслуживает одно соединение. subscribed сообщает, удалось ли
+// подписаться на канал, — после успешной подписки задержка сбрасывается.
+func (s *PostgresStorage) listenOnce(ctx context.Context, channel string, logger *slog.Logger, onSubscribe func(ctx context.Context), onNotify func(ctx context.Context, payload string)) (subscribed bool, err error) {
+	conn, err := pgx.ConnectConfig(ctx, s.pool.Config().ConnConfig.Copy())
+	if err != nil {
+		return false, fmt.Errorf("connect: %w", err)
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		_ = conn.Close(closeCtx)
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("listen: %w", err)
+	}
+	onSubscribe(ctx)
+	logger.Info("Listener subscribed", slog.String("channel", channel))
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("wait for notification: %w", err)
+		}
+		onNotify(ctx, notification.Payload)
+	}
+}
+
+func parseChange(payload string) (Change, error) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return change, err
+	}
+	if change.ID <= 0 {
+		return change, fmt.Errorf("invalid id %d", change.ID)
+	}
+	return change, nil
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseChange(t *testing.T) {
+	change, err := parseChange(`{"op":"UPDATE","id":42,"version":"2026-01-02T03:04:05.123456+00:00"}`)
+	if err != nil {
+		t.Fatalf("parseChange() error = %v", err)
+	}
+	want := time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	if change.Op != "UPDATE" || change.ID != 42 || !change.Version.Equal(want) {
+		t.Fatalf("parseChange() = %+v", change)
+	}
+
+	for _, payload := range []string{`not json`, `{"op":"DELETE"}`, `{"op":"DELETE","id":-1}`} {
+		if _, err := parseChange(payload); err == nil {
+			t.Errorf("parseChange(%q) expected error", payload)
+		}
+	}
+}
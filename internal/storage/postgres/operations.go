@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+	storageerrors "go-service-template/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// operationColumns — поля, которые отдаются клиенту; params и input читает
+// только StartOperation, output — GetOperationOutput.
+const operationColumns = `id, kind, status, progress, result, COALESCE(error, ''), cancel_requested, actor,
+	COALESCE(request_id, ''), COALESCE(client_ip, ''), created_at, updated_at, started_at, finished_at`
+
+// OperationStore хранит асинхронные операции.
+type OperationStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ service.OperationStorage = (*OperationStore)(nil)
+
+// OperationStore возвращает хранилище операций на том же пуле.
+func (s *PostgresStorage) OperationStore() *OperationStore {
+	return &OperationStore{pool: s.pool}
+}
+
+func scanOperation(row pgx.Row, op *models.Operation, extra ...any) error {
+	return row.Scan(append([]any{&op.ID, &op.Kind, &op.Status, &op.Progress, &op.Result, &op.Error,
+		&op.CancelRequested, &op.Actor, &op.RequestID, &op.ClientIP,
+		&op.CreatedAt, &op.UpdatedAt, &op.StartedAt, &op.FinishedAt}, extra...)...)
+}
+
+func (s *OperationStore) CreateOperation(ctx context.Context, op *models.Operation) error {
+	err := scanOperation(s.pool.QueryRow(ctx, `
+		INSERT INTO operations (kind, params, input, actor, request_id, client_ip)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING `+operationColumns,
+		op.Kind, op.Params, op.Input, op.Actor, op.RequestID, op.ClientIP), op)
+	if err != nil {
+		return fmt.Errorf("failed to create operation: %w", err)
+	}
+	return nil
+}
+
+func (s *OperationStore) GetOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	op := &models.Operation{}
+	err := scanOperation(s.pool.QueryRow(ctx, `SELECT `+operationColumns+` FROM operations WHERE id = $1`, id), op)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storageerrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	return op, nil
+}
+
+func (s *OperationStore) StartOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	op := &models.Operation{}
+	err := scanOperation(s.pool.QueryRow(ctx, `
+		UPDATE operations
+		SET status = CASE WHEN cancel_requested THEN 'cancelled' ELSE 'running' END,
+			error = CASE WHEN cancel_requested THEN 'operation cancelled' END,
+			input = CASE WHEN cancel_requested THEN NULL ELSE input END,
+			started_at = COALESCE(started_at, now()),
+			finished_at = CASE WHEN cancel_requested THEN now() END,
+			updated_at = now()
+		WHERE id = $1 AND status IN ('pending', 'running')
+		RETURNING `+operationColumns+`, params, input`, id), op, &op.Params, &op.Input)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storageerrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to start operation: %w", err)
+	}
+	return op, nil
+}
+
+func (s *OperationStore) SaveOperationProgress(ctx context.Context, id int64, progress int64) (bool, error) {
+	var cancelRequested bool
+	err := s.pool.QueryRow(ctx, `
+		UPDATE operations SET progress = $2, updated_at = now()
+		WHERE id = $1
+		RETURNING cancel_requested`, id, progress).Scan(&cancelRequested)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, storageerrors.ErrNotFound
+		}
+		return false, fmt.Errorf("failed to save operation progress: %w", err)
+	}
+	return cancelRequested, nil
+}
+
+func (s *OperationStore) FinishOperation(ctx context.Context, id int64, status string, progress int64, result json.RawMessage, errMsg string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE operations
+		SET status = $2, progress = $3, result = $4, error = NULLIF($5, ''),
+			input = NULL, finished_at = now(), updated_at = now()
+		WHERE id = $1 AND status IN ('pending', 'running')`,
+		id, status, progress, result, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to finish operation: %w", err)
+	}
+	return nil
+}
+
+func (s *OperationStore) SaveOperationOutput(ctx context.Context, id int64, output []byte) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE operations SET output = $2, updated_at = now()
+		WHERE id = $1 AND status = 'running'`, id, output)
+	if err != nil {
+		return fmt.Errorf("failed to save operation output: %w", err)
+	}
+	return nil
+}
+
+func (s *OperationStore) GetOperationOutput(ctx context.Context, id int64) (*models.Operation, []byte, error) {
+	op := &models.Operation{}
+	var output []byte
+	err := scanOperation(s.pool.QueryRow(ctx, `
+		SELECT `+operationColumns+`, params, output FROM operations WHERE id = $1`, id), op, &op.Params, &output)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, storageerrors.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get operation output: %w", err)
+	}
+	return op, output, nil
+}
+
+// DeleteFinishedBefore удаляет операции, завершённые раньше before.
+func (s *OperationStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM operations WHERE finished_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished operations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *OperationStore) CancelOperation(ctx context.Context, id int64) (*models.Operation, error) {
+	op := &models.Operation{}
+	// Завершённая операция возвращается без изменений: UPDATE затрагивает
+	// строку, но условия CASE оставляют поля прежними.
+	err := scanOperation(s.pool.QueryRow(ctx, `
+		UPDATE operations
+		SET status = CASE WHEN status = 'pending' THEN 'cancelled' ELSE status END,
+			error = CASE WHEN status = 'pending' THEN 'operation cancelled' ELSE error END,
+			input = CASE WHEN status = 'pending' THEN NULL ELSE input END,
+			finished_at = CASE WHEN status = 'pending' THEN now() ELSE finished_at END,
+			cancel_requested = cancel_requested OR status = 'running',
+			updated_at = CASE WHEN status IN ('pending', 'running') THEN now() ELSE updated_at END
+		WHERE id = $1
+		RETURNING `+operationColumns, id), op)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storageerrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to cancel operation: %w", err)
+	}
+	return op, nil
+}
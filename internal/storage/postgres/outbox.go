@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/outbox"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AppendOutbox пишет сообщение в таблицу outbox. Внутри WithinTx сообщение
+// фиксируется вместе с изменением данных.
+func (s *PostgresStorage) AppendOutbox(ctx context.Context, msg *models.OutboxMessage) error {
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		msg.AggregateType, msg.AggregateID, msg.EventType, msg.Payload).Scan(&msg.ID, &msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to append outbox message: %w", err)
+	}
+	return nil
+}
+
+// OutboxStore — очередь сообщений outbox для relay. Время сравнивается по
+// часам БД, как и в IdempotencyStore.
+type OutboxStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ outbox.Store = (*OutboxStore)(nil)
+
+// OutboxStore возвращает очередь outbox на том же пуле.
+func (s *PostgresStorage) OutboxStore() *OutboxStore {
+	return &OutboxStore{pool: s.pool}
+}
+
+// Claim берёт сообщения, у которых нет более ранних неотправленных сообщений
+// того же агрегата, — так агрегат никогда не публикуется параллельно или не
+// по порядку. SKIP LOCKED позволяет нескольким репликам разбирать очередь
+// одновременно, не дожидаясь друг друга.
+func (s *OutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1, locked_until = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT o.id FROM outbox o
+			WHERE o.dead_at IS NULL
+				AND o.next_attempt_at <= now()
+				AND (o.locked_until IS NULL OR o.locked_until <= now())
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox p
+					WHERE p.aggregate_type = o.aggregate_type
+						AND p.aggregate_id = o.aggregate_id
+						AND p.id < o.id
+						AND p.dead_at IS NULL)
+			ORDER BY o.id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OutboxMessage, error) {
+		var msg models.OutboxMessage
+		err := row.Scan(&msg.ID, &msg.AggregateType, &msg.AggregateID, &msg.EventType, &msg.Payload, &msg.CreatedAt, &msg.Attempts)
+		return msg, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan outbox messages: %w", err)
+	}
+
+	// RETURNING не гарантирует порядок.
+	slices.SortFunc(messages, func(a, b models.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+func (s *OutboxStore) Delete(ctx context.Context, id int64) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete outbox message: %w", err)
+	}
+	return nil
+}
+
+// Retry и DeadLetter меняют сообщение, только если его не перезахватили
+// после истечения аренды: attempt должен совпадать с текущим.
+func (s *OutboxStore) Retry(ctx context.Context, id int64, attempt int, delay time.Duration, lastErr string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE outbox
+		SET locked_until = NULL, next_attempt_at = now() + make_interval(secs => $3), last_error = $4
+		WHERE id = $1 AND attempts = $2`,
+		id, attempt, delay.Seconds(), lastErr)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox message: %w", err)
+	}
+	return nil
+}
+
+func (s *OutboxStore) DeadLetter(ctx context.Context, id int64, attempt int, lastErr string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE outbox
+		SET locked_until = NULL, dead_at = now(), last_error = $3
+		WHERE id = $1 AND attempts = $2`,
+		id, attempt, lastErr)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter outbox message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Таблицу example_revisions заполняют триггеры (миграция 000009), поэтому
+// здесь только чтение.
+
+const revisionColumns = `example_id, revision, op, data, created_at`
+
+func scanRevision(row pgx.Row, revision *models.ExampleRevision) error {
+	return row.Scan(&revision.ExampleID, &revision.Revision, &revision.Op, &revision.Example, &revision.CreatedAt)
+}
+
+func (s *PostgresStorage) GetExampleRevisions(ctx context.Context, id, limit, offset int) ([]models.ExampleRevision, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+revisionColumns+` FROM example_revisions
+		WHERE example_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3`, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+
+	revisions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ExampleRevision, error) {
+		var revision models.ExampleRevision
+		err := scanRevision(row, &revision)
+		return revision, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (s *PostgresStorage) GetExampleRevision(ctx context.Context, id, rev int) (*models.ExampleRevision, error) {
+	return s.getRevision(ctx, `
+		SELECT `+revisionColumns+` FROM example_revisions
+		WHERE example_id = $1 AND revision = $2`, id, rev)
+}
+
+func (s *PostgresStorage) GetExampleRevisionAt(ctx context.Context, id int, asOf time.Time) (*models.ExampleRevision, error) {
+	return s.getRevision(ctx, `
+		SELECT `+revisionColumns+` FROM example_revisions
+		WHERE example_id = $1 AND created_at <= $2
+		ORDER BY revision DESC
+		LIMIT 1`, id, asOf)
+}
+
+func (s *PostgresStorage) getRevision(ctx context.Context, query string, args ...any) (*models.ExampleRevision, error) {
+	revision := &models.ExampleRevision{}
+	if err := scanRevision(s.db.QueryRow(ctx, query, args...), revision); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExampleNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return revision, nil
+}
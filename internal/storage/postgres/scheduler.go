@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/scheduler"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// schedulerLockNamespace — первый ключ advisory-блокировок планировщика,
+	// второй — hashtext(имя задачи). Отделяет их от других блокировок в БД.
+	schedulerLockNamespace int32 = 0x5ced
+	// unlockTimeout ограничивает снятие блокировки после отмены контекста.
+	unlockTimeout = 5 * time.Second
+)
+
+// SchedulerStore хранит запуски периодических задач.
+type SchedulerStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ scheduler.Store = (*SchedulerStore)(nil)
+
+// SchedulerStore возвращает хранилище планировщика на том же пуле.
+func (s *PostgresStorage) SchedulerStore() *SchedulerStore {
+	return &SchedulerStore{pool: s.pool}
+}
+
+// Acquire берёт сессионную advisory-блокировку на отдельном соединении и
+// держит его до release: если реплика упадёт посреди запуска, блокировка
+// снимется вместе с соединением.
+func (s *SchedulerStore) Acquire(ctx context.Context, name string, tick time.Time) (func(), bool, error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`,
+		schedulerLockNamespace, name).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("failed to lock scheduled task: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	release := func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1, hashtext($2))`,
+			schedulerLockNamespace, name); err != nil {
+			// Соединение с неснятой блокировкой нельзя возвращать в пул.
+			_ = conn.Conn().Close(unlockCtx)
+		}
+		conn.Release()
+	}
+
+	// Tick отмечается под блокировкой: реплика, запоздавшая к тому же tick,
+	// увидит его выполненным.
+	tag, err := conn.Exec(ctx, `
+		INSERT INTO scheduled_tasks (name, last_tick, status, started_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (name) DO UPDATE
+		SET last_tick = EXCLUDED.last_tick, status = EXCLUDED.status, error = NULL,
+			started_at = EXCLUDED.started_at, finished_at = NULL
+		WHERE scheduled_tasks.last_tick < EXCLUDED.last_tick`,
+		name, tick, scheduler.StatusRunning)
+	if err != nil {
+		release()
+		return nil, false, fmt.Errorf("failed to record scheduled task start: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		release()
+		return nil, false, nil
+	}
+
+	return release, true, nil
+}
+
+func (s *SchedulerStore) Finish(ctx context.Context, name string, tick time.Time, status, errMsg string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE scheduled_tasks SET status = $3, error = NULLIF($4, ''), finished_at = now()
+		WHERE name = $1 AND last_tick = $2`,
+		name, tick, status, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to record scheduled task run: %w", err)
+	}
+	return nil
+}
+
+func (s *SchedulerStore) LastRuns(ctx context.Context) (map[string]models.ScheduledTaskRun, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT name, last_tick, status, COALESCE(error, ''), started_at, finished_at
+		FROM scheduled_tasks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled task runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make(map[string]models.ScheduledTaskRun)
+	for rows.Next() {
+		var (
+			name string
+			run  models.ScheduledTaskRun
+		)
+		if err := rows.Scan(&name, &run.Tick, &run.Status, &run.Error, &run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled task run: %w", err)
+		}
+		runs[name] = run
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list scheduled task runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ExpectedSchemaVersion — номер последней миграции в migrations/, с которой
+// совместим код. Увеличивайте вместе с добавлением миграции.
+const ExpectedSchemaVersion = 17
+
+// CheckSchemaVersion сверяет версию схемы из таблицы schema_migrations
+// (golang-migrate) с ExpectedSchemaVersion. Используется health-проверкой
+// "migrations": под не считается запущенным, пока миграции не применены.
+func (s *PostgresStorage) CheckSchemaVersion(ctx context.Context) error {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := s.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("no migrations applied")
+		}
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if version < ExpectedSchemaVersion {
+		return fmt.Errorf("schema version %d is behind expected %d", version, ExpectedSchemaVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// searchHeadlineOptions — параметры ts_headline: в description показываются
+// до двух фрагментов вокруг совпадений.
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20, FragmentDelimiter=" … "`
+
+// exampleSearchQuery собирает поиск по examples. Страница отбирается по
+// индексу (GIN по search_vector или по триграммам) и сортируется по
+// релевантности, а фрагменты ts_headline строятся только для её строк:
+// ts_headline заново разбирает текст и дорог на больших выборках.
+func exampleSearchQuery(query models.ExampleSearchQuery, language string) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	lang, q := arg(language), arg(query.Query)
+	where := exampleWhere(query.Filter, arg)
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+
+	var page string
+	if query.Mode == service.SearchModeFuzzy {
+		// <% — word_similarity не ниже pg_trgm.word_similarity_threshold (0.6):
+		// q похожа на часть name или description.
+		page = `
+			SELECT ` + exampleColumns + `,
+				greatest(word_similarity(` + q + `, name), word_similarity(` + q + `, coalesce(description, ''))) AS rank
+			FROM examples` + where + `(` + q + ` <% name OR ` + q + ` <% description)`
+	} else {
+		// Совпадения в name весят больше, чем в description (setweight A/B).
+		page = `
+			SELECT ` + exampleColumns + `, ts_rank(search_vector, q.query) AS rank
+			FROM examples, q` + where + `search_vector @@ q.query`
+	}
+	page += `
+			ORDER BY rank DESC, id
+			LIMIT ` + arg(query.Filter.Limit)
+	if query.Filter.Offset > 0 {
+		page += " OFFSET " + arg(query.Filter.Offset)
+	}
+
+	return `
+		WITH q AS (SELECT websearch_to_tsquery(` + lang + `::regconfig, ` + q + `) AS query),
+		page AS (` + page + `)
+		SELECT ` + exampleColumns + `, rank,
+			ts_headline(` + lang + `::regconfig, ` + htmlEscape("name") + `, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline(` + lang + `::regconfig, ` + htmlEscape("coalesce(description, '')") + `, q.query, '` + searchHeadlineOptions + `')
+		FROM page, q
+		ORDER BY rank DESC, id`, args
+}
+
+// htmlEscape экранирует текст SQL-выражения expr, как html.EscapeString.
+// Фрагменты строятся по уже экранированному тексту: тогда <mark> —
+// единственная разметка в ответе. Парсер ts_headline читает &lt; и подобные
+// как отдельные лексемы-сущности, поэтому совпадения не смещаются.
+func htmlEscape(expr string) string {
+	return `replace(replace(replace(replace(replace(` + expr +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+}
+
+// checkSearchLanguage сверяет language с конфигурациями, которыми строится
+// колонка search_vector (миграция 000013). При расхождении запросы
+// разбирались бы другими основами слов и молча не находили бы записи.
+// Если колонки ещё нет (миграции не применены), проверять нечего.
+func checkSearchLanguage(ctx context.Context, pool *pgxpool.Pool, language string) error {
+	rows, err := pool.Query(ctx, `
+		SELECT m[1], m[1]::regconfig = $1::regconfig
+		FROM pg_attrdef d
+		JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum,
+			regexp_matches(pg_get_expr(d.adbin, d.adrelid), 'to_tsvector\(''([^'']+)''::regconfig', 'g') AS m
+		WHERE d.adrelid = to_regclass('examples') AND a.attname = 'search_vector'`, language)
+	if err != nil {
+		return fmt.Errorf("failed to read search_vector configuration: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			column string
+			same   bool
+		)
+		if err := rows.Scan(&column, &same); err != nil {
+			return fmt.Errorf("failed to scan search_vector configuration: %w", err)
+		}
+		if !same {
+			return fmt.Errorf("DB_SEARCH_LANGUAGE %q does not match search_vector configuration %q: rebuild the column with a migration or change DB_SEARCH_LANGUAGE", language, column)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read search_vector configuration: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStorage) SearchExamples(ctx context.Context, query models.ExampleSearchQuery) ([]models.ExampleSearchResult, error) {
+	sql, args := exampleSearchQuery(query, s.searchLanguage)
+	rows, err := s.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search examples: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]models.ExampleSearchResult, 0)
+	for rows.Next() {
+		var (
+			result      models.ExampleSearchResult
+			externalKey *string
+		)
+		if err := rows.Scan(
+			&result.ID, &result.Name, &result.Description, &result.Value,
+			&result.IsActive, &result.CreatedAt, &result.UpdatedAt, &externalKey,
+			&result.Rank, &result.NameHighlight, &result.Snippet,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if externalKey != nil {
+			result.ExternalKey = *externalKey
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search results: %w", err)
+	}
+
+	return results, nil
+}
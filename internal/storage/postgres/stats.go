@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-service-template/internal/models"
+)
+
+// exampleStatsQuery собирает агрегаты по value одним запросом: GROUPING SETS
+// даёт итог, разбивку по is_active и (если задан Bucket) по интервалам
+// created_at. Первые две колонки — GROUPING: 0 означает, что строка
+// сгруппирована по этому полю.
+func exampleStatsQuery(query models.ExampleStatsQuery) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	where := exampleWhere(query.Filter, arg)
+	if query.Bucket == "" {
+		return `
+		SELECT GROUPING(is_active), 1, is_active, NULL::timestamptz,
+			count(*), COALESCE(sum(value), 0), avg(value), min(value), max(value)
+		FROM examples` + where + `
+		GROUP BY GROUPING SETS ((), (is_active))
+		ORDER BY 1 DESC, is_active DESC`, args
+	}
+
+	// date_trunc с часовым поясом режет по местным границам суток и недель
+	// (неделя начинается с понедельника) и учитывает переход на летнее время.
+	bucket := "date_trunc(" + arg(query.Bucket) + ", created_at, " + arg(query.Timezone) + ")"
+	return `
+		SELECT GROUPING(is_active), GROUPING(bucket), is_active, bucket,
+			count(*), COALESCE(sum(value), 0), avg(value), min(value), max(value)
+		FROM (SELECT is_active, value, ` + bucket + ` AS bucket FROM examples` + where + `) e
+		GROUP BY GROUPING SETS ((), (is_active), (bucket))
+		ORDER BY 1 DESC, 2 DESC, is_active DESC, bucket`, args
+}
+
+func (s *PostgresStorage) GetExampleStats(ctx context.Context, query models.ExampleStatsQuery) (*models.ExampleStats, error) {
+	sql, args := exampleStatsQuery(query)
+	rows, err := s.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get example stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.ExampleStats{ByActive: []models.ActiveStats{}}
+	for rows.Next() {
+		var (
+			noActive, noBucket int
+			isActive           *bool
+			bucket             *time.Time
+			values             models.ValueStats
+		)
+		if err := rows.Scan(&noActive, &noBucket, &isActive, &bucket,
+			&values.Count, &values.Sum, &values.Avg, &values.Min, &values.Max); err != nil {
+			return nil, fmt.Errorf("failed to scan example stats: %w", err)
+		}
+
+		switch {
+		case noActive == 0:
+			// is_active допускает NULL; такие записи учтены только в итоге.
+			if isActive != nil {
+				stats.ByActive = append(stats.ByActive, models.ActiveStats{IsActive: *isActive, ValueStats: values})
+			}
+		case noBucket == 0:
+			stats.Buckets = append(stats.Buckets, models.BucketStats{Start: *bucket, ValueStats: values})
+		default:
+			stats.Total = values
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate example stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RefreshExampleStats пересчитывает материализованную статистику
+// example_stats_daily (миграция 000016), не блокируя её чтение.
+func (s *PostgresStorage) RefreshExampleStats(ctx context.Context) error {
+	if _, err := s.db.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY example_stats_daily`); err != nil {
+		return fmt.Errorf("failed to refresh example stats: %w", err)
+	}
+	return nil
+}
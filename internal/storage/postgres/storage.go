@@ -7,15 +7,36 @@ import (
 
 	"go-service-template/internal/config"
 	"go-service-template/internal/models"
+	"go-service-template/internal/service"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// querier — общее подмножество *pgxpool.Pool и pgx.Tx: одни и те же методы
+// хранилища работают и с пулом, и внутри транзакции.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
 type PostgresStorage struct {
 	pool *pgxpool.Pool
+	// db — пул или текущая транзакция (для экземпляров, созданных WithinTx).
+	db querier
+
+	txOptions    pgx.TxOptions
+	txMaxRetries int
+	// searchLanguage — конфигурация полнотекстового поиска (regconfig).
+	searchLanguage string
 }
 
+var _ service.Storage = (*PostgresStorage)(nil)
+
 func NewStorage(ctx context.Context, dsn string, dbCfg config.DatabaseConfig) (*PostgresStorage, error) {
 	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
@@ -43,16 +64,32 @@ func NewStorage(ctx context.Context, dsn string, dbCfg config.DatabaseConfig) (*
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
+	// Опечатка в имени конфигурации иначе всплыла бы только на первом поиске.
+	if _, err := pool.Exec(ctx, `SELECT $1::regconfig`, dbCfg.SearchLanguage); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("unknown text search configuration %q: %w", dbCfg.SearchLanguage, err)
+	}
+	if err := checkSearchLanguage(ctx, pool, dbCfg.SearchLanguage); err != nil {
+		pool.Close()
+		return nil, err
+	}
 
-	return &PostgresStorage{pool: pool}, nil
+	return &PostgresStorage{
+		pool:           pool,
+		db:             pool,
+		txOptions:      pgx.TxOptions{IsoLevel: isoLevel(dbCfg.TxIsolation)},
+		txMaxRetries:   dbCfg.TxMaxRetries,
+		searchLanguage: dbCfg.SearchLanguage,
+	}, nil
 }
 
 func (s *PostgresStorage) Ping(ctx context.Context) error {
 	return s.pool.Ping(ctx)
 }
 
+// Close закрывает пул. На экземпляре внутри транзакции ничего не делает.
 func (s *PostgresStorage) Close() error {
-	if s.pool != nil {
+	if s.pool != nil && s.db == s.pool {
 		s.pool.Close()
 	}
 	return nil
@@ -64,7 +101,7 @@ func (s *PostgresStorage) CreateExample(ctx context.Context, example *models.Exa
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id`
 
-	err := s.pool.QueryRow(ctx, query, example.Name, example.Description, example.Value,
+	err := s.db.QueryRow(ctx, query, example.Name, example.Description, example.Value,
 		example.IsActive, example.CreatedAt, example.UpdatedAt).Scan(&example.ID)
 	if err != nil {
 		return fmt.Errorf("failed to create example: %w", err)
@@ -74,17 +111,10 @@ func (s *PostgresStorage) CreateExample(ctx context.Context, example *models.Exa
 }
 
 func (s *PostgresStorage) GetExampleByID(ctx context.Context, id int) (*models.Example, error) {
-	query := `
-		SELECT id, name, description, value, is_active, created_at, updated_at
-		FROM examples
-		WHERE id = $1`
+	query := `SELECT ` + exampleColumns + ` FROM examples WHERE id = $1`
 
 	example := &models.Example{}
-	err := s.pool.QueryRow(ctx, query, id).Scan(
-		&example.ID, &example.Name, &example.Description, &example.Value,
-		&example.IsActive, &example.CreatedAt, &example.UpdatedAt,
-	)
-
+	err := scanExample(s.db.QueryRow(ctx, query, id), example)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrExampleNotFound
@@ -95,14 +125,27 @@ func (s *PostgresStorage) GetExampleByID(ctx context.Context, id int) (*models.E
 	return example, nil
 }
 
-func (s *PostgresStorage) GetAllExamples(ctx context.Context, limit, offset int) ([]models.Example, error) {
-	query := `
-		SELECT id, name, description, value, is_active, created_at, updated_at
-		FROM examples
-		ORDER BY id 
-		LIMIT $1 OFFSET $2`
+// GetExampleForUpdate читает запись с SELECT ... FOR UPDATE: внутри
+// WithinTx строка заблокирована до commit или rollback.
+func (s *PostgresStorage) GetExampleForUpdate(ctx context.Context, id int) (*models.Example, error) {
+	query := `SELECT ` + exampleColumns + ` FROM examples WHERE id = $1 FOR UPDATE`
+
+	example := &models.Example{}
+	err := scanExample(s.db.QueryRow(ctx, query, id), example)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExampleNotFound
+		}
+		return nil, fmt.Errorf("failed to lock example: %w", err)
+	}
+
+	return example, nil
+}
 
-	rows, err := s.pool.Query(ctx, query, limit, offset)
+func (s *PostgresStorage) GetAllExamples(ctx context.Context, filter models.ExampleFilter) ([]models.Example, error) {
+	query, args := exampleFilterQuery(filter)
+
+	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get examples: %w", err)
 	}
@@ -111,11 +154,7 @@ func (s *PostgresStorage) GetAllExamples(ctx context.Context, limit, offset int)
 	var examples []models.Example
 	for rows.Next() {
 		var example models.Example
-		err := rows.Scan(
-			&example.ID, &example.Name, &example.Description, &example.Value,
-			&example.IsActive, &example.CreatedAt, &example.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanExample(rows, &example); err != nil {
 			return nil, fmt.Errorf("failed to scan example: %w", err)
 		}
 		examples = append(examples, example)
@@ -127,29 +166,31 @@ func (s *PostgresStorage) GetAllExamples(ctx context.Context, limit, offset int)
 	return examples, nil
 }
 
+// UpdateExample обновляет запись и за тот же round-trip заполняет example
+// итоговой строкой из БД (RETURNING), включая created_at.
 func (s *PostgresStorage) UpdateExample(ctx context.Context, example *models.Example) error {
 	query := `
 		UPDATE examples 
 		SET name = $1, description = $2, value = $3, is_active = $4, updated_at = $5
-		WHERE id = $6`
+		WHERE id = $6
+		RETURNING ` + exampleColumns
 
-	ct, err := s.pool.Exec(ctx, query, example.Name, example.Description, example.Value,
-		example.IsActive, example.UpdatedAt, example.ID)
+	err := scanExample(s.db.QueryRow(ctx, query, example.Name, example.Description, example.Value,
+		example.IsActive, example.UpdatedAt, example.ID), example)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrExampleNotFound
+		}
 		return fmt.Errorf("failed to update example: %w", err)
 	}
 
-	if ct.RowsAffected() == 0 {
-		return ErrExampleNotFound
-	}
-
 	return nil
 }
 
 func (s *PostgresStorage) DeleteExample(ctx context.Context, id int) error {
 	query := `DELETE FROM examples WHERE id = $1`
 
-	ct, err := s.pool.Exec(ctx, query, id)
+	ct, err := s.db.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete example: %w", err)
 	}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/models"
+)
+
+// Бенчмарки ходят в настоящий PostgreSQL с применёнными миграциями и
+// пропускаются без TEST_DATABASE_DSN:
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres password=password dbname=service_db sslmode=disable" \
+//	    go test -run '^$' -bench UpdateExample -cpu 1,8,32 ./internal/storage/postgres/
+func newBenchStorage(b *testing.B) *PostgresStorage {
+	b.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st, err := NewStorage(ctx, dsn, config.DatabaseConfig{MaxConns: 32, TxIsolation: "read_committed", SearchLanguage: "english"})
+	if err != nil {
+		b.Fatalf("connect: %v", err)
+	}
+	b.Cleanup(func() { _ = st.Close() })
+
+	return st
+}
+
+// updateThenSelect — прежняя схема: UPDATE и отдельный SELECT за обновлённой строкой.
+func (s *PostgresStorage) updateThenSelect(ctx context.Context, example *models.Example) (*models.Example, error) {
+	if _, err := s.db.Exec(ctx, `
+		UPDATE examples
+		SET name = $1, description = $2, value = $3, is_active = $4, updated_at = $5
+		WHERE id = $6`,
+		example.Name, example.Description, example.Value, example.IsActive, example.UpdatedAt, example.ID); err != nil {
+		return nil, err
+	}
+	return s.GetExampleByID(ctx, example.ID)
+}
+
+func BenchmarkUpdateExample(b *testing.B) {
+	st := newBenchStorage(b)
+	ctx := context.Background()
+
+	// Каждая горутина обновляет свою строку, чтобы мерить round-trip'ы, а не
+	// блокировки одной и той же строки.
+	var ids []int
+	for range 64 {
+		example := &models.Example{Name: "bench", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := st.CreateExample(ctx, example); err != nil {
+			b.Fatalf("seed: %v", err)
+		}
+		ids = append(ids, example.ID)
+	}
+	b.Cleanup(func() {
+		_, _ = st.pool.Exec(context.Background(), `DELETE FROM examples WHERE id = ANY($1)`, ids)
+	})
+
+	run := func(b *testing.B, update func(example *models.Example) error) {
+		var next atomic.Int64
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			id := ids[int(next.Add(1)-1)%len(ids)]
+			for pb.Next() {
+				example := &models.Example{ID: id, Name: "bench", Value: 1, UpdatedAt: time.Now()}
+				if err := update(example); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	}
+
+	b.Run("returning", func(b *testing.B) {
+		run(b, func(example *models.Example) error {
+			return st.UpdateExample(ctx, example)
+		})
+	})
+
+	b.Run("update_then_select", func(b *testing.B) {
+		run(b, func(example *models.Example) error {
+			_, err := st.updateThenSelect(ctx, example)
+			return err
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"go-service-template/internal/service"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+
+	txRetryBaseDelay = 10 * time.Millisecond
+)
+
+// WithinTx выполняет fn в транзакции с уровнем изоляции DB_TX_ISOLATION.
+// Ошибки сериализации и дедлоки повторяются до DB_TX_MAX_RETRIES раз с
+// экспоненциальной задержкой. Вызов на экземпляре внутри транзакции создаёт
+// savepoint и не повторяется — повтор решает внешняя транзакция.
+func (s *PostgresStorage) WithinTx(ctx context.Context, fn func(tx service.TxStorage) error) error {
+	if tx, ok := s.db.(pgx.Tx); ok {
+		return pgx.BeginFunc(ctx, tx, func(nested pgx.Tx) error {
+			return fn(s.withQuerier(nested))
+		})
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := pgx.BeginTxFunc(ctx, s.pool, s.txOptions, func(tx pgx.Tx) error {
+			return fn(s.withQuerier(tx))
+		})
+		if err == nil || !isRetryableTxError(err) || attempt >= s.txMaxRetries {
+			return err
+		}
+
+		delay := txRetryBaseDelay<<attempt + rand.N(txRetryBaseDelay)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (s *PostgresStorage) withQuerier(db querier) *PostgresStorage {
+	return &PostgresStorage{
+		pool:           s.pool,
+		db:             db,
+		txOptions:      s.txOptions,
+		txMaxRetries:   s.txMaxRetries,
+		searchLanguage: s.searchLanguage,
+	}
+}
+
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+func isoLevel(name string) pgx.TxIsoLevel {
+	switch name {
+	case "repeatable_read":
+		return pgx.RepeatableRead
+	case "serializable":
+		return pgx.Serializable
+	default:
+		return pgx.ReadCommitted
+	}
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableTxError(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryableTxError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsoLevel(t *testing.T) {
+	tests := map[string]pgx.TxIsoLevel{
+		"read_committed":  pgx.ReadCommitted,
+		"repeatable_read": pgx.RepeatableRead,
+		"serializable":    pgx.Serializable,
+	}
+
+	for name, want := range tests {
+		if got := isoLevel(name); got != want {
+			t.Errorf("isoLevel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-service-template/internal/models"
+	"go-service-template/internal/service"
+	storageerrors "go-service-template/internal/storage"
+	"go-service-template/internal/webhook"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const webhookColumns = `id, url, secret, event_types, active, consecutive_failures, disabled_at, created_at, updated_at`
+
+// WebhookStore хранит подписки на webhooks, очередь доставок и журнал попыток.
+type WebhookStore struct {
+	pool *pgxpool.Pool
+}
+
+var (
+	_ service.WebhookStorage = (*WebhookStore)(nil)
+	_ webhook.Store          = (*WebhookStore)(nil)
+)
+
+// WebhookStore возвращает хранилище webhooks на том же пуле.
+func (s *PostgresStorage) WebhookStore() *WebhookStore {
+	return &WebhookStore{pool: s.pool}
+}
+
+func scanWebhook(row pgx.Row, sub *models.WebhookSubscription) error {
+	return row.Scan(&sub.ID, &sub.URL, &sub.Secret, &sub.EventTypes, &sub.Active,
+		&sub.ConsecutiveFailures, &sub.DisabledAt, &sub.CreatedAt, &sub.UpdatedAt)
+}
+
+func (s *WebhookStore) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	err := scanWebhook(s.pool.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+webhookColumns,
+		sub.URL, sub.Secret, sub.EventTypes, sub.Active), sub)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookStore) GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{}
+	err := scanWebhook(s.pool.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id), sub)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storageerrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *WebhookStore) GetAllWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	subs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.WebhookSubscription, error) {
+		var sub models.WebhookSubscription
+		err := scanWebhook(row, &sub)
+		return sub, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhooks: %w", err)
+	}
+	return subs, nil
+}
+
+func (s *WebhookStore) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription, active *bool) error {
+	err := scanWebhook(s.pool.QueryRow(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = $2,
+			event_types = $3,
+			secret = COALESCE(NULLIF($4, ''), secret),
+			active = COALESCE($5, active),
+			consecutive_failures = CASE WHEN $5 THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE
+				WHEN $5 THEN NULL
+				WHEN NOT $5 AND active THEN now()
+				ELSE disabled_at
+			END,
+			updated_at = now()
+		WHERE id = $1
+		RETURNING `+webhookColumns,
+		sub.ID, sub.URL, sub.EventTypes, sub.Secret, active), sub)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storageerrors.ErrNotFound
+		}
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookStore) DeleteWebhook(ctx context.Context, id int64) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return storageerrors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *WebhookStore) GetWebhookAttempts(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookAttempt, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.id, a.delivery_id, d.message_id, d.event_type, a.attempt,
+			COALESCE(a.status_code, 0), COALESCE(a.error, ''), a.duration_ms, a.created_at
+		FROM webhook_attempts a
+		JOIN webhook_deliveries d ON d.id = a.delivery_id
+		WHERE a.subscription_id = $1
+		ORDER BY a.id DESC
+		LIMIT $2`, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook attempts: %w", err)
+	}
+
+	attempts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.WebhookAttempt, error) {
+		var a models.WebhookAttempt
+		err := row.Scan(&a.ID, &a.DeliveryID, &a.MessageID, &a.EventType, &a.Attempt,
+			&a.StatusCode, &a.Error, &a.DurationMs, &a.CreatedAt)
+		return a, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// Enqueue раскладывает сообщение outbox по включённым подпискам на его тип.
+// Тело доставки — сообщение целиком; уникальность (подписка, сообщение)
+// делает повторную публикацию того же сообщения безопасной.
+func (s *WebhookStore) Enqueue(ctx context.Context, msg models.OutboxMessage) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, message_id, event_type, payload)
+		SELECT id, $1::bigint, $2::text, $3::jsonb
+		FROM webhook_subscriptions
+		WHERE active AND ($2::text = ANY(event_types) OR '*' = ANY(event_types))
+		ON CONFLICT (subscription_id, message_id) DO NOTHING`,
+		msg.ID, msg.EventType, msg)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Claim захватывает доставки включённых подписок. SKIP LOCKED позволяет
+// нескольким репликам разбирать очередь одновременно.
+func (s *WebhookStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+			locked_until = now() + make_interval(secs => $2),
+			updated_at = now()
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id
+			AND d.id IN (
+				SELECT q.id FROM webhook_deliveries q
+				JOIN webhook_subscriptions qs ON qs.id = q.subscription_id
+				WHERE q.status = 'pending'
+					AND qs.active
+					AND q.next_attempt_at <= now()
+					AND (q.locked_until IS NULL OR q.locked_until <= now())
+				ORDER BY q.next_attempt_at, q.id
+				LIMIT $1
+				FOR UPDATE OF q SKIP LOCKED)
+		RETURNING d.id, d.subscription_id, d.message_id, d.event_type, d.payload, d.attempts, s.url, s.secret`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (webhook.Delivery, error) {
+		var d webhook.Delivery
+		err := row.Scan(&d.ID, &d.SubscriptionID, &d.MessageID, &d.EventType, &d.Payload, &d.Attempt, &d.URL, &d.Secret)
+		return d, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Succeed и Fail меняют доставку, только если её не перезахватили после
+// истечения аренды (номер попытки совпадает); попытка в журнал пишется всегда.
+func (s *WebhookStore) Succeed(ctx context.Context, d webhook.Delivery, attempt models.WebhookAttempt) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := insertWebhookAttempt(ctx, tx, d, attempt); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE webhook_deliveries
+			SET status = 'succeeded', locked_until = NULL, updated_at = now()
+			WHERE id = $1 AND attempts = $2`, d.ID, d.Attempt)
+		if err != nil {
+			return fmt.Errorf("failed to complete webhook delivery: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE webhook_subscriptions SET consecutive_failures = 0
+			WHERE id = $1 AND consecutive_failures <> 0`, d.SubscriptionID)
+		if err != nil {
+			return fmt.Errorf("failed to reset webhook failures: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *WebhookStore) Fail(ctx context.Context, d webhook.Delivery, attempt models.WebhookAttempt, retryIn time.Duration, disableAfter int) (bool, error) {
+	var disabled bool
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := insertWebhookAttempt(ctx, tx, d, attempt); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE webhook_deliveries
+			SET status = CASE WHEN $3 > 0 THEN 'pending' ELSE 'failed' END,
+				next_attempt_at = now() + make_interval(secs => $3),
+				locked_until = NULL,
+				updated_at = now()
+			WHERE id = $1 AND attempts = $2`, d.ID, d.Attempt, retryIn.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to reschedule webhook delivery: %w", err)
+		}
+		// Порог срабатывает ровно один раз: доставки, завершившиеся после
+		// выключения, увеличивают счётчик дальше.
+		err = tx.QueryRow(ctx, `
+			UPDATE webhook_subscriptions
+			SET consecutive_failures = consecutive_failures + 1,
+				active = active AND consecutive_failures + 1 < $2,
+				disabled_at = CASE
+					WHEN active AND consecutive_failures + 1 >= $2 THEN now()
+					ELSE disabled_at
+				END,
+				updated_at = now()
+			WHERE id = $1
+			RETURNING consecutive_failures = $2`, d.SubscriptionID, disableAfter).Scan(&disabled)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Подписку удалили, пока шла доставка.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to count webhook failure: %w", err)
+		}
+		return nil
+	})
+	return disabled, err
+}
+
+func insertWebhookAttempt(ctx context.Context, tx pgx.Tx, d webhook.Delivery, attempt models.WebhookAttempt) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO webhook_attempts (delivery_id, subscription_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, ''), $6)`,
+		d.ID, d.SubscriptionID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinishedBefore удаляет завершённые доставки (и их попытки), последний
+// раз менявшиеся раньше before.
+func (s *WebhookStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	ct, err := s.pool.Exec(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE status <> 'pending' AND updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old webhook deliveries: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
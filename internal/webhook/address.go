@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrForbiddenAddress — адрес получателя ведёт во внутреннюю сеть.
+var ErrForbiddenAddress = errors.New("webhook: destination address is not public")
+
+// Диапазоны, которых нет в проверках netip.Addr: «эта сеть» (0.0.0.0/8) и
+// shared address space провайдеров (100.64.0.0/10, RFC 6598).
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// ForbiddenAddress сообщает, что ip не является публичным адресом: loopback,
+// частные сети, link-local (в том числе 169.254.169.254 — метаданные
+// облака), multicast и неуказанный адрес. IPv4, записанный как IPv6
+// (::ffff:127.0.0.1), проверяется как IPv4.
+func ForbiddenAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialControl — net.Dialer.Control, запрещающий соединения с непубличными
+// адресами. Он вызывается после разрешения имени для каждого адреса, к
+// которому идёт подключение, поэтому имя, указывающее во внутреннюю сеть (в
+// том числе подменённое после создания подписки — DNS rebinding), не
+// проходит так же, как IP в URL.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || ForbiddenAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
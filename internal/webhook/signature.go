@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader — заголовок с подписью запроса вида
+// "t=<unix-время>,v1=<hex HMAC-SHA256>". Подписывается строка
+// "<unix-время>.<тело>" секретом подписки: время внутри подписи не даёт
+// переиграть перехваченный запрос позже.
+const SignatureHeader = "Webhook-Signature"
+
+var (
+	ErrInvalidSignatureHeader = errors.New("invalid signature header")
+	ErrSignatureMismatch      = errors.New("signature mismatch")
+	ErrSignatureExpired       = errors.New("signature timestamp outside tolerance")
+)
+
+// Sign возвращает значение SignatureHeader для тела body, отправленного в момент at.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+// Verify проверяет подпись на стороне получателя: HMAC совпадает, а время
+// подписи отличается от now не больше чем на tolerance.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidSignatureHeader
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignatureHeader
+	}
+
+	if !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrSignatureMismatch
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,245 @@
+// Package webhook доставляет события outbox партнёрам по HTTP. Publisher
+// подключается к relay outbox и раскладывает каждое сообщение в очередь
+// доставок — по строке на подходящую подписку; Worker отправляет их
+// POST-запросами с подписью HMAC-SHA256 (см. Sign), повторяет неудачные с
+// экспоненциальной задержкой и выключает подписки, которые долго не отвечают.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-service-template/internal/background"
+	"go-service-template/internal/config"
+	"go-service-template/internal/metrics"
+	"go-service-template/internal/models"
+)
+
+// maxDrainBytes — сколько тела ответа вычитывается, чтобы переиспользовать
+// соединение. Содержимое ответа не используется.
+const maxDrainBytes = 64 << 10
+
+// Заголовки запроса доставки. MessageIDHeader — ID сообщения outbox:
+// одинаков во всех попытках, по нему получатель отбрасывает дубликаты.
+const (
+	MessageIDHeader = "Webhook-ID"
+	EventTypeHeader = "Webhook-Event"
+	userAgent       = "go-service-template-webhooks"
+)
+
+// Delivery — захваченная доставка вместе с адресом и секретом подписки.
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	MessageID      int64
+	EventType      string
+	// Payload — тело запроса: сообщение outbox в JSON.
+	Payload []byte
+	// Attempt — номер текущей попытки.
+	Attempt int
+	URL     string
+	Secret  string
+}
+
+// Store — подписки и очередь доставок.
+type Store interface {
+	// Enqueue создаёт доставки сообщения для всех включённых подписок на его
+	// тип. Повторный вызов для того же сообщения ничего не добавляет.
+	Enqueue(ctx context.Context, msg models.OutboxMessage) error
+	// Claim захватывает на lease до limit доставок, чьё время пришло, и
+	// увеличивает их номер попытки. Доставки выключенных подписок не берутся.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	// Succeed записывает успешную попытку и сбрасывает счётчик ошибок подписки.
+	Succeed(ctx context.Context, d Delivery, attempt models.WebhookAttempt) error
+	// Fail записывает неудачную попытку: доставка повторится через retryIn,
+	// а при retryIn == 0 помечается failed. Счётчик ошибок подписки растёт;
+	// достигнув disableAfter, подписка выключается — тогда disabled = true.
+	Fail(ctx context.Context, d Delivery, attempt models.WebhookAttempt, retryIn time.Duration, disableAfter int) (disabled bool, err error)
+}
+
+// Publisher — outbox.Publisher, ставящий сообщения в очередь доставок.
+// Сообщение считается опубликованным, когда доставки записаны в БД.
+type Publisher struct {
+	store Store
+}
+
+func NewPublisher(store Store) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	return p.store.Enqueue(ctx, msg)
+}
+
+// Worker отправляет доставки из очереди.
+type Worker struct {
+	store  Store
+	client *http.Client
+	cfg    config.WebhookConfig
+	logger *slog.Logger
+}
+
+func NewWorker(store Store, cfg config.WebhookConfig, logger *slog.Logger) *Worker {
+	dialer := &net.Dialer{Timeout: cfg.Timeout, KeepAlive: 30 * time.Second}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = dialControl
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Через прокси проверялся бы адрес прокси, а не получателя.
+	transport.Proxy = nil
+
+	return &Worker{
+		store: store,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			// Редирект считается ошибкой: подписанное тело не должно уходить
+			// на адрес, которого нет в подписке.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run разбирает очередь, пока ctx не отменён: полная пачка — сразу следующая,
+// иначе ожидание PollInterval.
+func (w *Worker) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		wait := w.cfg.PollInterval
+		if w.deliverBatch(ctx) == w.cfg.BatchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	batch, err := w.store.Claim(ctx, w.cfg.BatchSize, w.cfg.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to claim webhook deliveries", slog.String("error", err.Error()))
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range batch {
+		wg.Go(func() {
+			w.deliver(ctx, d)
+		})
+	}
+	wg.Wait()
+
+	return len(batch)
+}
+
+func (w *Worker) deliver(ctx context.Context, d Delivery) {
+	started := time.Now()
+	status, sendErr := w.send(ctx, d)
+	attempt := models.WebhookAttempt{
+		DeliveryID: d.ID,
+		MessageID:  d.MessageID,
+		EventType:  d.EventType,
+		Attempt:    d.Attempt,
+		StatusCode: status,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+
+	storeCtx, cancel := background.StoreContext(ctx)
+	defer cancel()
+
+	logger := w.logger.With(
+		slog.Int64("subscription_id", d.SubscriptionID),
+		slog.Int64("delivery_id", d.ID),
+		slog.Int("attempt", d.Attempt),
+	)
+
+	if sendErr == nil {
+		metrics.WebhookAttempts.Add("success", 1)
+		if err := w.store.Succeed(storeCtx, d, attempt); err != nil {
+			logger.Error("Failed to record webhook delivery", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	if ctx.Err() != nil {
+		// Остановка прервала запрос: получатель не виноват. Доставку
+		// подхватит другая реплика после истечения аренды.
+		logger.Info("Webhook delivery interrupted by shutdown")
+		return
+	}
+	metrics.WebhookAttempts.Add("failure", 1)
+	attempt.Error = background.ErrorText(sendErr)
+	var retryIn time.Duration
+	if d.Attempt < w.cfg.MaxAttempts {
+		retryIn = w.backoff(d.Attempt)
+	}
+	logger.Warn("Webhook delivery failed",
+		slog.Int("status", status),
+		slog.Duration("retry_in", retryIn),
+		slog.String("error", attempt.Error),
+	)
+
+	disabled, err := w.store.Fail(storeCtx, d, attempt, retryIn, w.cfg.DisableAfter)
+	if err != nil {
+		logger.Error("Failed to record webhook failure", slog.String("error", err.Error()))
+		return
+	}
+	if disabled {
+		metrics.WebhookSubscriptionsDisabled.Add(1)
+		logger.Error("Webhook subscription disabled after repeated failures",
+			slog.Int("failures", w.cfg.DisableAfter))
+	}
+}
+
+// send выполняет одну попытку. Успех — любой ответ 2xx; status == 0, если
+// ответа не было.
+func (w *Worker) send(ctx context.Context, d Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(MessageIDHeader, strconv.FormatInt(d.MessageID, 10))
+	req.Header.Set(EventTypeHeader, d.EventType)
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff — background.Backoff с jitter по настройкам повторов доставок.
+func (w *Worker) backoff(attempt int) time.Duration {
+	return background.Jitter(background.Backoff(w.cfg.RetryBaseDelay, w.cfg.RetryMaxDelay, attempt))
+}
@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-service-template/internal/config"
+	"go-service-template/internal/models"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":1}`)
+	header := Sign("secret", now, body)
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"valid", "secret", header, body, now, nil},
+		{"within tolerance", "secret", header, body, now.Add(4 * time.Minute), nil},
+		{"wrong secret", "other", header, body, now, ErrSignatureMismatch},
+		{"tampered body", "secret", header, []byte(`{"id":2}`), now, ErrSignatureMismatch},
+		{"expired", "secret", header, body, now.Add(10 * time.Minute), ErrSignatureExpired},
+		{"malformed", "secret", "v1=abc", body, now, ErrInvalidSignatureHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// fakeStore отдаёт заданные доставки одной пачкой и запоминает результаты.
+type fakeStore struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+	succeeded  []models.WebhookAttempt
+	failed     []models.WebhookAttempt
+	retries    []time.Duration
+	failures   int
+}
+
+func (s *fakeStore) Enqueue(context.Context, models.OutboxMessage) error {
+	return nil
+}
+
+func (s *fakeStore) Claim(context.Context, int, time.Duration) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := s.deliveries
+	s.deliveries = nil
+	return batch, nil
+}
+
+func (s *fakeStore) Succeed(_ context.Context, _ Delivery, attempt models.WebhookAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.succeeded = append(s.succeeded, attempt)
+	s.failures = 0
+	return nil
+}
+
+func (s *fakeStore) Fail(_ context.Context, _ Delivery, attempt models.WebhookAttempt, retryIn time.Duration, disableAfter int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = append(s.failed, attempt)
+	s.retries = append(s.retries, retryIn)
+	s.failures++
+	return s.failures == disableAfter, nil
+}
+
+func newTestWorker(store Store) *Worker {
+	return NewWorker(store, config.WebhookConfig{
+		Timeout:        time.Second,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  time.Minute,
+		DisableAfter:   5,
+		// httptest слушает 127.0.0.1.
+		AllowPrivateNetworks: true,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestWorker_SignsDelivery(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{deliveries: []Delivery{{
+		ID: 1, SubscriptionID: 1, MessageID: 42, EventType: "example.created",
+		Payload: []byte(`{"id":42}`), Attempt: 1, URL: srv.URL, Secret: "0123456789abcdef",
+	}}}
+	newTestWorker(store).deliverBatch(context.Background())
+
+	r := <-received
+	if err := Verify("0123456789abcdef", r.Header.Get(SignatureHeader), body, time.Now(), time.Minute); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+	if r.Header.Get(MessageIDHeader) != "42" || r.Header.Get(EventTypeHeader) != "example.created" {
+		t.Errorf("unexpected headers: %v", r.Header)
+	}
+	if len(store.succeeded) != 1 || store.succeeded[0].StatusCode != http.StatusOK {
+		t.Errorf("expected one successful attempt, got %+v", store.succeeded)
+	}
+}
+
+func TestWorker_RetriesAndGivesUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{}
+	worker := newTestWorker(store)
+	for attempt := 1; attempt <= 3; attempt++ {
+		store.deliveries = []Delivery{{ID: 1, SubscriptionID: 1, Attempt: attempt, URL: srv.URL, Secret: "s"}}
+		worker.deliverBatch(context.Background())
+	}
+
+	if len(store.failed) != 3 || store.failed[0].StatusCode != http.StatusInternalServerError || store.failed[0].Error == "" {
+		t.Fatalf("expected three recorded failures, got %+v", store.failed)
+	}
+	// Вторая половина задержки случайна: [delay/2, delay].
+	bounds := [][2]time.Duration{{500 * time.Millisecond, time.Second}, {time.Second, 2 * time.Second}}
+	for i, b := range bounds {
+		if got := store.retries[i]; got < b[0] || got > b[1] {
+			t.Errorf("retry %d: expected delay in [%v, %v], got %v", i+1, b[0], b[1], got)
+		}
+	}
+	if store.retries[2] != 0 {
+		t.Errorf("expected last attempt to give up, got retry in %v", store.retries[2])
+	}
+}
+
+func TestWorker_DoesNotFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://elsewhere.example.com", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{deliveries: []Delivery{{ID: 1, Attempt: 1, URL: srv.URL, Secret: "s"}}}
+	newTestWorker(store).deliverBatch(context.Background())
+
+	if len(store.failed) != 1 || store.failed[0].StatusCode != http.StatusFound {
+		t.Fatalf("expected redirect to fail the attempt, got %+v", store.failed)
+	}
+}
+
+func TestWorker_RefusesPrivateAddresses(t *testing.T) {
+	var called atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called.Store(true)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{deliveries: []Delivery{{ID: 1, Attempt: 1, URL: srv.URL, Secret: "s"}}}
+	NewWorker(store, config.WebhookConfig{
+		Timeout:        time.Second,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  time.Minute,
+		DisableAfter:   5,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil))).deliverBatch(context.Background())
+
+	if called.Load() {
+		t.Fatal("request reached a loopback address")
+	}
+	if len(store.failed) != 1 || !strings.Contains(store.failed[0].Error, "not public") {
+		t.Fatalf("expected the attempt to fail on the address check, got %+v", store.failed)
+	}
+}
+
+func TestForbiddenAddress(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1":        true,
+		"::1":              true,
+		"10.1.2.3":         true,
+		"172.16.0.1":       true,
+		"192.168.1.1":      true,
+		"169.254.169.254":  true,
+		"fe80::1":          true,
+		"fd00::1":          true,
+		"0.0.0.0":          true,
+		"100.64.0.1":       true,
+		"::ffff:127.0.0.1": true,
+		"8.8.8.8":          false,
+		"2001:4860::8888":  false,
+	}
+	for addr, want := range tests {
+		if got := ForbiddenAddress(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("ForbiddenAddress(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	worker := newTestWorker(&fakeStore{})
+	for range 100 {
+		if got := worker.backoff(30); got < 30*time.Second || got > time.Minute {
+			t.Fatalf("expected capped delay in [30s, 1m], got %v", got)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE examples DROP CONSTRAINT IF EXISTS examples_external_key_key;
+ALTER TABLE examples DROP COLUMN IF EXISTS external_key;
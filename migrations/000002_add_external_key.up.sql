@@ -0,0 +1,4 @@
+-- Внешний ключ записи из системы-источника: по нему импорт делает upsert.
+-- NULL допускается многократно, поэтому записи, созданные через API, не конфликтуют.
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS external_key VARCHAR(255);
+ALTER TABLE examples ADD CONSTRAINT examples_external_key_key UNIQUE (external_key);
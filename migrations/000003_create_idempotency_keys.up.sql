@@ -0,0 +1,16 @@
+-- Ответы на запросы с заголовком Idempotency-Key. status_code IS NULL — первый
+-- запрос ещё выполняется (блокировка до locked_until).
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    principal VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash BYTEA NOT NULL,
+    status_code INTEGER,
+    response_headers JSONB,
+    response_body BYTEA,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (principal, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
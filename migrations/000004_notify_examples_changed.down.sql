@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS examples_changed ON examples;
+DROP FUNCTION IF EXISTS notify_examples_changed();
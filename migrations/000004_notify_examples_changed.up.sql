@@ -0,0 +1,26 @@
+-- Уведомление об изменении записи для инвалидации кешей во всех репликах.
+-- Payload — JSON {"op", "id", "version"}, где version — updated_at изменённой
+-- строки. NOTIFY доставляется только после commit; одинаковые уведомления в
+-- одной транзакции Postgres схлопывает. Вставки не отправляются: новой записи
+-- ещё нет ни в одном кеше.
+CREATE OR REPLACE FUNCTION notify_examples_changed() RETURNS trigger AS $$
+DECLARE
+    changed examples%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+    PERFORM pg_notify('examples_changed', json_build_object(
+        'op', TG_OP,
+        'id', changed.id,
+        'version', changed.updated_at
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER examples_changed
+    AFTER UPDATE OR DELETE ON examples
+    FOR EACH ROW EXECUTE FUNCTION notify_examples_changed();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS example_events_delete ON examples;
+DROP TRIGGER IF EXISTS example_events_update ON examples;
+DROP TRIGGER IF EXISTS example_events_insert ON examples;
+DROP TRIGGER IF EXISTS example_events_lock ON examples;
+DROP FUNCTION IF EXISTS record_example_events();
+DROP FUNCTION IF EXISTS lock_example_events();
+DROP TABLE IF EXISTS example_events;
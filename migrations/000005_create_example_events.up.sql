@@ -0,0 +1,57 @@
+-- Журнал изменений examples для потока /api/v1/examples/stream. ID события
+-- монотонно растёт в порядке commit, поэтому клиент продолжает поток с
+-- Last-Event-ID без пропусков. data — строка после изменения (для deleted —
+-- до удаления).
+CREATE TABLE IF NOT EXISTS example_events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(16) NOT NULL,
+    example_id INTEGER NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_example_events_created_at ON example_events(created_at);
+
+-- Пишущие в examples транзакции сериализуются блокировкой, взятой до первого
+-- изменения и удерживаемой до commit: иначе транзакция, получившая ID 9,
+-- могла бы закоммититься позже той, что получила 10, и читатель, уже
+-- прошедший 10, пропустил бы 9.
+CREATE OR REPLACE FUNCTION lock_example_events() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('example_events'));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_example_events() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'created', r.id, to_jsonb(r) FROM new_rows r ORDER BY r.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'updated', r.id, to_jsonb(r) FROM new_rows r ORDER BY r.id;
+    ELSE
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'deleted', r.id, to_jsonb(r) FROM old_rows r ORDER BY r.id;
+    END IF;
+    PERFORM pg_notify('example_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER example_events_lock
+    BEFORE INSERT OR UPDATE OR DELETE ON examples
+    FOR EACH STATEMENT EXECUTE FUNCTION lock_example_events();
+
+CREATE TRIGGER example_events_insert
+    AFTER INSERT ON examples REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_events();
+
+CREATE TRIGGER example_events_update
+    AFTER UPDATE ON examples REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_events();
+
+CREATE TRIGGER example_events_delete
+    AFTER DELETE ON examples REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_events();
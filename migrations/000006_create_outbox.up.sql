@@ -0,0 +1,21 @@
+-- Transactional outbox: сообщения для внешних систем пишутся в одной
+-- транзакции с изменением данных, relay публикует их и удаляет. Сообщения
+-- одного агрегата публикуются строго по порядку id. dead_at IS NOT NULL —
+-- сообщение исчерпало попытки (dead letter) и больше не публикуется.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    dead_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Очередь на отправку и поиск более ранних сообщений того же агрегата.
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE dead_at IS NULL;
+CREATE INDEX idx_outbox_aggregate ON outbox(aggregate_type, aggregate_id, id) WHERE dead_at IS NULL;
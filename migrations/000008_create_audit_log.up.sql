@@ -0,0 +1,34 @@
+-- Журнал аудита изменений: пишется в одной транзакции с изменением. before и
+-- after — состояние сущности до и после (NULL для create и delete
+-- соответственно). Таблица только дополняется: UPDATE, DELETE и TRUNCATE
+-- запрещены триггерами.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    entity_type VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id TEXT,
+    client_ip TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_modify
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
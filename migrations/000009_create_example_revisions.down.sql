@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS example_revisions_insert ON examples;
+DROP TRIGGER IF EXISTS example_revisions_update ON examples;
+DROP TRIGGER IF EXISTS example_revisions_delete ON examples;
+DROP FUNCTION IF EXISTS record_example_revisions();
+DROP TABLE IF EXISTS example_revisions;
@@ -0,0 +1,53 @@
+-- История версий examples: строка на каждое изменение записи. revision
+-- нумеруется с 1 отдельно для каждой записи; data — строка после изменения
+-- (для deleted — до удаления). История переживает удаление записи, поэтому
+-- внешнего ключа на examples нет. Номера не конфликтуют: пишущие в examples
+-- транзакции уже сериализованы блокировкой example_events_lock (000005).
+CREATE TABLE IF NOT EXISTS example_revisions (
+    example_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    op VARCHAR(16) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (example_id, revision)
+);
+
+CREATE INDEX idx_example_revisions_as_of ON example_revisions(example_id, created_at);
+
+CREATE OR REPLACE FUNCTION record_example_revisions() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'created', to_jsonb(r)
+        FROM new_rows r;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'updated', to_jsonb(r)
+        FROM new_rows r;
+    ELSE
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'deleted', to_jsonb(r)
+        FROM old_rows r;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER example_revisions_insert
+    AFTER INSERT ON examples REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_revisions();
+
+CREATE TRIGGER example_revisions_update
+    AFTER UPDATE ON examples REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_revisions();
+
+CREATE TRIGGER example_revisions_delete
+    AFTER DELETE ON examples REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION record_example_revisions();
+
+-- Существующие записи получают первую версию на момент последнего изменения.
+INSERT INTO example_revisions (example_id, revision, op, data, created_at)
+SELECT e.id, 1, 'created', to_jsonb(e), e.updated_at FROM examples e;
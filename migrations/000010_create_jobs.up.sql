@@ -0,0 +1,25 @@
+-- Очередь фоновых задач. status: pending | running | succeeded | failed
+-- (попытки исчерпаны или ошибка неисправима). Захваченная задача держит
+-- locked_until; если воркер упал, после этого момента её забирает другой.
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    unique_key TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Ключ уникальности действует, пока задача не завершена: после этого
+-- задачу с тем же ключом можно поставить снова.
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs(kind, unique_key)
+    WHERE unique_key IS NOT NULL AND status IN ('pending', 'running');
+CREATE INDEX idx_jobs_pending ON jobs(run_at) WHERE status = 'pending';
+CREATE INDEX idx_jobs_running ON jobs(locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_finished ON jobs(updated_at) WHERE status IN ('succeeded', 'failed');
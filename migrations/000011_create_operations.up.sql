@@ -0,0 +1,24 @@
+-- Долгие асинхронные операции (GET /api/v1/operations/{id}).
+-- status: pending | running | succeeded | failed | cancelled.
+-- Выполняет их фоновая задача; cancel_requested — отмена, которую выполняющий
+-- воркер замечает при опросе. actor, request_id и client_ip — инициатор: от
+-- его имени изменения попадают в журнал аудита. input — тело запроса запуска
+-- (файл импорта), очищается по завершении.
+CREATE TABLE IF NOT EXISTS operations (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    params JSONB NOT NULL DEFAULT '{}',
+    input BYTEA,
+    progress BIGINT NOT NULL DEFAULT 0,
+    result JSONB,
+    error TEXT,
+    cancel_requested BOOLEAN NOT NULL DEFAULT false,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    client_ip TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,11 @@
+-- Последний запуск каждой периодической задачи планировщика. last_tick —
+-- момент расписания, который уже выполнен: реплика, опоздавшая к тому же
+-- tick, его не повторит. status: running | succeeded | failed.
+CREATE TABLE IF NOT EXISTS scheduled_tasks (
+    name VARCHAR(64) PRIMARY KEY,
+    last_tick TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
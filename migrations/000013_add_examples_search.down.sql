@@ -0,0 +1,44 @@
+-- Триггеры возвращаются к версиям из 000005 и 000009.
+CREATE OR REPLACE FUNCTION record_example_events() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'created', r.id, to_jsonb(r) FROM new_rows r ORDER BY r.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'updated', r.id, to_jsonb(r) FROM new_rows r ORDER BY r.id;
+    ELSE
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'deleted', r.id, to_jsonb(r) FROM old_rows r ORDER BY r.id;
+    END IF;
+    PERFORM pg_notify('example_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_example_revisions() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'created', to_jsonb(r)
+        FROM new_rows r;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'updated', to_jsonb(r)
+        FROM new_rows r;
+    ELSE
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'deleted', to_jsonb(r)
+        FROM old_rows r;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_examples_description_trgm;
+DROP INDEX IF EXISTS idx_examples_name_trgm;
+DROP INDEX IF EXISTS idx_examples_search_vector;
+ALTER TABLE examples DROP COLUMN IF EXISTS search_vector;
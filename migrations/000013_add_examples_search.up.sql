@@ -0,0 +1,56 @@
+-- Полнотекстовый поиск по name и description. Конфигурация english должна
+-- совпадать с DB_SEARCH_LANGUAGE: запросы разбираются ею же. Совпадения в
+-- name весят больше (A), чем в description (B).
+ALTER TABLE examples ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_examples_search_vector ON examples USING GIN (search_vector);
+
+-- Нечёткий поиск (mode=fuzzy) по триграммам: индексы обслуживают оператор <%.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_examples_name_trgm ON examples USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_examples_description_trgm ON examples USING GIN (description gin_trgm_ops);
+
+-- search_vector — служебная колонка: в журнал изменений и историю версий
+-- пишется только содержимое записи.
+CREATE OR REPLACE FUNCTION record_example_events() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'created', r.id, to_jsonb(r) - 'search_vector' FROM new_rows r ORDER BY r.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'updated', r.id, to_jsonb(r) - 'search_vector' FROM new_rows r ORDER BY r.id;
+    ELSE
+        INSERT INTO example_events (type, example_id, data)
+        SELECT 'deleted', r.id, to_jsonb(r) - 'search_vector' FROM old_rows r ORDER BY r.id;
+    END IF;
+    PERFORM pg_notify('example_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_example_revisions() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'created', to_jsonb(r) - 'search_vector'
+        FROM new_rows r;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'updated', to_jsonb(r) - 'search_vector'
+        FROM new_rows r;
+    ELSE
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'deleted', to_jsonb(r) - 'search_vector'
+        FROM old_rows r;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
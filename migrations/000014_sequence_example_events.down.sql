@@ -0,0 +1,16 @@
+DROP FUNCTION IF EXISTS sequence_example_events();
+DROP INDEX IF EXISTS idx_example_events_pending;
+DROP INDEX IF EXISTS idx_example_events_position;
+ALTER TABLE example_events DROP COLUMN IF EXISTS position, DROP COLUMN IF EXISTS tx_id;
+
+-- Возвращает сериализующую блокировку из 000005.
+CREATE OR REPLACE FUNCTION lock_example_events() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('example_events'));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER example_events_lock
+    BEFORE INSERT OR UPDATE OR DELETE ON examples
+    FOR EACH STATEMENT EXECUTE FUNCTION lock_example_events();
@@ -0,0 +1,58 @@
+-- Порядок журнала example_events без глобальной блокировки записи. Раньше
+-- пишущие транзакции сериализовались advisory-блокировкой из 000005: это
+-- упорядочивало ID по commit, но выстраивало в очередь все записи и брало
+-- блокировку раньше блокировок строк (GetExampleForUpdate), что приводило к
+-- взаимоблокировкам.
+--
+-- Теперь событие запоминает транзакцию, которая его записала (tx_id), а
+-- позицию в журнале (position) ему назначает читатель: только событиям
+-- транзакций старше pg_snapshot_xmin — все они уже завершены, и новых
+-- событий с меньшим tx_id не появится. Нумерация идёт по (tx_id, id), поэтому
+-- позиции растут без пропусков в порядке, который уже не изменится.
+DROP TRIGGER IF EXISTS example_events_lock ON examples;
+DROP FUNCTION IF EXISTS lock_example_events();
+
+ALTER TABLE example_events
+    ADD COLUMN tx_id xid8 NOT NULL DEFAULT pg_current_xact_id(),
+    ADD COLUMN position BIGINT;
+
+-- Уже записанные события сохраняют свои ID: клиенты продолжают поток с
+-- прежним Last-Event-ID.
+UPDATE example_events SET position = id;
+
+CREATE SEQUENCE example_events_position_seq OWNED BY example_events.position;
+SELECT setval('example_events_position_seq', COALESCE((SELECT max(id) FROM example_events), 0) + 1, false);
+
+CREATE UNIQUE INDEX idx_example_events_position ON example_events(position);
+CREATE INDEX idx_example_events_pending ON example_events(tx_id, id) WHERE position IS NULL;
+
+-- sequence_example_events назначает позиции завершённым событиям и
+-- возвращает их число. Нумеруют реплики-читатели; одновременно — только
+-- одна, остальные пропускают ход: занятая реплика разбудит их через NOTIFY.
+CREATE OR REPLACE FUNCTION sequence_example_events() RETURNS integer AS $$
+DECLARE
+    horizon xid8;
+    pending RECORD;
+    assigned integer := 0;
+BEGIN
+    IF NOT pg_try_advisory_xact_lock(hashtext('example_events_sequence')) THEN
+        RETURN 0;
+    END IF;
+
+    horizon := pg_snapshot_xmin(pg_current_snapshot());
+    FOR pending IN
+        SELECT id FROM example_events
+        WHERE position IS NULL AND tx_id < horizon
+        ORDER BY tx_id, id
+    LOOP
+        UPDATE example_events SET position = nextval('example_events_position_seq')
+        WHERE id = pending.id;
+        assigned := assigned + 1;
+    END LOOP;
+
+    IF assigned > 0 THEN
+        PERFORM pg_notify('example_events', '');
+    END IF;
+    RETURN assigned;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,22 @@
+-- Триггер возвращается к версии из 000013.
+CREATE OR REPLACE FUNCTION record_example_revisions() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'created', to_jsonb(r) - 'search_vector'
+        FROM new_rows r;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'updated', to_jsonb(r) - 'search_vector'
+        FROM new_rows r;
+    ELSE
+        INSERT INTO example_revisions (example_id, revision, op, data)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'deleted', to_jsonb(r) - 'search_vector'
+        FROM old_rows r;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,30 @@
+-- created_at версии — момент изменения, а не начала транзакции: NOW()
+-- одинаков для всей транзакции, и версии из долгой транзакции (импорт,
+-- пакетная операция) получали время раньше тех, что закоммичены до неё, —
+-- запрос as_of по created_at возвращал не ту версию.
+--
+-- Номера версий одной записи после удаления блокировки example_events_lock
+-- (000014) не конфликтуют по-прежнему: UPDATE и DELETE держат блокировку
+-- строки до commit, и триггер следующей транзакции видит уже закоммиченную
+-- версию.
+CREATE OR REPLACE FUNCTION record_example_revisions() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data, created_at)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'created', to_jsonb(r) - 'search_vector', clock_timestamp()
+        FROM new_rows r;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO example_revisions (example_id, revision, op, data, created_at)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'updated', to_jsonb(r) - 'search_vector', clock_timestamp()
+        FROM new_rows r;
+    ELSE
+        INSERT INTO example_revisions (example_id, revision, op, data, created_at)
+        SELECT r.id, 1 + COALESCE((SELECT max(v.revision) FROM example_revisions v WHERE v.example_id = r.id), 0),
+               'deleted', to_jsonb(r) - 'search_vector', clock_timestamp()
+        FROM old_rows r;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1 @@
+DROP MATERIALIZED VIEW IF EXISTS example_stats_daily;
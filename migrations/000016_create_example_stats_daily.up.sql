@@ -0,0 +1,19 @@
+-- Материализованная статистика по value за сутки (UTC) и is_active — для
+-- дашбордов и отчётов, которым не нужна точность до секунды и которые не
+-- должны сканировать examples на каждый запрос. Пересчитывает задача
+-- планировщика stats-refresh (STATS_REFRESH_SCHEDULE); точные агрегаты с
+-- фильтрами отдаёт GET /examples/stats.
+CREATE MATERIALIZED VIEW IF NOT EXISTS example_stats_daily AS
+SELECT
+    date_trunc('day', created_at, 'UTC') AS day,
+    is_active,
+    count(*) AS count,
+    COALESCE(sum(value), 0) AS sum,
+    min(value) AS min,
+    max(value) AS max
+FROM examples
+GROUP BY 1, 2;
+
+-- Уникальный индекс нужен REFRESH ... CONCURRENTLY: чтение не блокируется
+-- на время пересчёта.
+CREATE UNIQUE INDEX idx_example_stats_daily ON example_stats_daily(day, is_active);
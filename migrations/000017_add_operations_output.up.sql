@@ -0,0 +1,4 @@
+-- Файл-результат операции (выгрузка examples.export), сжатый gzip. Отдаётся
+-- GET /api/v1/operations/{id}/output и удаляется вместе с операцией через
+-- OPERATIONS_RETENTION.
+ALTER TABLE operations ADD COLUMN output BYTEA;